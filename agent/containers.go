@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// ErrNotFound is matched by the errors of requests for pods or containers
+// that do not run on this node.
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalid is matched by the errors of requests that cannot be served as
+// asked.
+var ErrInvalid = errors.New("invalid request")
+
+type notFoundError string
+
+func (e notFoundError) Error() string        { return string(e) }
+func (e notFoundError) Is(target error) bool { return target == ErrNotFound }
+
+type invalidError string
+
+func (e invalidError) Error() string        { return string(e) }
+func (e invalidError) Is(target error) bool { return target == ErrInvalid }
+
+var podResource, _ = client.LookupResource("pods")
+
+// containerName returns the docker container running a container of a pod
+// bound to this node, or its first container when container is empty.
+// Docker containers are named after their pod, so only a pod's first
+// container is ever started.
+func (a *NodeAgent) containerName(namespace, podName, container string) (string, error) {
+	var pod models.Pod
+	if err := a.client.Get(podResource, namespace, podName, &pod); errors.Is(err, client.ErrNotFound) {
+		return "", notFoundError(fmt.Sprintf("pod %q not found", podName))
+	} else if err != nil {
+		return "", err
+	}
+	if pod.Spec.NodeName != a.nodeName {
+		return "", notFoundError(fmt.Sprintf("pod %q does not run on node %q", podName, a.nodeName))
+	}
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	for i, c := range pod.Spec.Containers {
+		if c.Name != container {
+			continue
+		}
+		if i > 0 {
+			return "", notFoundError(fmt.Sprintf("container %q of pod %q is not started: only the first container of a pod runs", container, podName))
+		}
+		return pod.Metadata.Name, nil
+	}
+	return "", notFoundError(fmt.Sprintf("container %q not found in pod %q", container, podName))
+}
+
+// ContainerLogs writes the log of a pod's container to out, following it
+// until ctx is done if opts ask to. Pods are not restarted, so the last
+// terminated instance opts.Previous asks for is the container itself, once
+// it has exited.
+func (a *NodeAgent) ContainerLogs(ctx context.Context, namespace, podName string, opts models.PodLogOptions, out io.Writer) error {
+	name, err := a.containerName(namespace, podName, opts.Container)
+	if err != nil {
+		return err
+	}
+	if err := exec.Command("docker", "inspect", name).Run(); err != nil {
+		return notFoundError(fmt.Sprintf("container %q of pod %q has not been created", opts.Container, podName))
+	}
+	running := isContainerRunning(name)
+	if opts.Previous && running {
+		return invalidError(fmt.Sprintf("previous terminated container %q in pod %q not found", opts.Container, podName))
+	}
+
+	args := []string{"logs"}
+	if opts.Follow && running {
+		args = append(args, "--follow")
+	}
+	if opts.Timestamps {
+		args = append(args, "--timestamps")
+	}
+	if opts.TailLines != nil {
+		args = append(args, "--tail", strconv.FormatInt(*opts.TailLines, 10))
+	}
+	if opts.SinceSeconds != nil {
+		args = append(args, "--since", strconv.FormatInt(*opts.SinceSeconds, 10)+"s")
+	}
+	if opts.SinceTime != nil {
+		args = append(args, "--since", opts.SinceTime.Format(time.RFC3339))
+	}
+	args = append(args, name)
+
+	// Sharing one writer makes docker's stdout and stderr arrive in order
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("docker logs %s failed: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"github.com/creack/pty"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+)
+
+// RunningContainer returns the docker container running a container of a
+// pod bound to this node, or its first container when container is empty.
+// It must be running for commands to run in it.
+func (a *NodeAgent) RunningContainer(namespace, podName, container string) (string, error) {
+	name, err := a.containerName(namespace, podName, container)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case isContainerRunning(name):
+		return name, nil
+	case container == "":
+		return "", invalidError(fmt.Sprintf("pod %q is not running", podName))
+	}
+	return "", invalidError(fmt.Sprintf("container %q of pod %q is not running", container, podName))
+}
+
+// ExecInContainer runs command in a docker container with streams, until it
+// exits or ctx is done. With tty the command gets a terminal, sized by
+// streams.Resize, whose output all goes to streams.Stdout. A command that
+// exits non-zero returns an *exec.ExitError.
+func (a *NodeAgent) ExecInContainer(ctx context.Context, name string, command []string, tty bool, streams wsstream.ExecStreams) error {
+	args := []string{"exec"}
+	if streams.Stdin != nil {
+		args = append(args, "-i")
+	}
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, name)
+	args = append(args, command...)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+
+	if !tty {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = streams.Stdin, streams.Stdout, streams.Stderr
+		return cmd.Run()
+	}
+
+	// docker exec -t needs a terminal of its own to pass on
+	terminal, err := pty.Start(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to start docker exec: %v", err)
+	}
+	defer terminal.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case size := <-streams.Resize:
+				pty.Setsize(terminal, &pty.Winsize{Rows: size.Height, Cols: size.Width})
+			}
+		}
+	}()
+	if streams.Stdin != nil {
+		go io.Copy(terminal, streams.Stdin)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Reading fails with EIO once the command has exited
+		if _, err := io.Copy(streams.Stdout, terminal); err != nil && !errors.Is(err, syscall.EIO) {
+			fmt.Printf("⚠️ Terminal of container %s: %v\n", name, err)
+		}
+	}()
+
+	err = cmd.Wait()
+	wg.Wait()
+	return err
+}
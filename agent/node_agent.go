@@ -1,12 +1,15 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
 	"github.com/selimhanmrl/Own-Kubernetes/client"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
 )
@@ -45,27 +48,58 @@ func matchLabels(podLabels, selector map[string]string) bool {
 }
 
 type NodeAgent struct {
-	nodeName string
-	nodeIP   string
-	client   *client.Client
+	nodeName  string
+	nodeIP    string
+	client    *client.Client
+	recorder  *client.EventRecorder
+	intervals Intervals
 }
 
-func NewNodeAgent(nodeName, nodeIP, apiHost, apiPort string) *NodeAgent {
-	return &NodeAgent{
-		nodeName: nodeName,
-		nodeIP:   nodeIP,
-		client: client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		}),
+// Intervals say how often the agent works. Zero fields use the defaults.
+type Intervals struct {
+	Heartbeat time.Duration // node status reports, default 30s
+	PodSync   time.Duration // starting and cleaning up pods, default 10s
+}
+
+// NewNodeAgent creates an agent for nodeName. The credentials in apiConfig
+// should identify the agent as "node:<nodeName>".
+func NewNodeAgent(nodeName, nodeIP string, apiConfig client.ClientConfig, intervals Intervals) (*NodeAgent, error) {
+	if intervals.Heartbeat == 0 {
+		intervals.Heartbeat = 30 * time.Second
+	}
+	if intervals.PodSync == 0 {
+		intervals.PodSync = 10 * time.Second
 	}
+	c, err := client.NewClient(apiConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeAgent{
+		nodeName:  nodeName,
+		nodeIP:    nodeIP,
+		client:    c,
+		recorder:  client.NewEventRecorder(c, "kubelet", nodeName),
+		intervals: intervals,
+	}, nil
 }
 
-func (a *NodeAgent) Start() error {
+// Run registers the node, then sends heartbeats and manages the node's pods
+// until ctx is cancelled.
+func (a *NodeAgent) Run(ctx context.Context) error {
 	fmt.Printf("🚀 Starting node agent for node %s (IP: %s)\n", a.nodeName, a.nodeIP)
 	fmt.Printf("📡 Connecting to API server at %s:%s\n", a.client.GetConfig().Host, a.client.GetConfig().Port)
 
+	// Make sure the API server sees us as this node
+	if user, err := a.client.WhoAmI(); err != nil {
+		fmt.Printf("⚠️ Could not verify node identity: %v\n", err)
+	} else if name, ok := user.NodeName(); !ok || name != a.nodeName {
+		fmt.Printf("⚠️ Authenticated as '%s' instead of '%s%s'\n", user.Name, auth.NodeUserPrefix, a.nodeName)
+	} else {
+		fmt.Printf("🔐 Authenticated as %s\n", user.Name)
+	}
+
 	// Register node
+	capacity := getNodeCapacity()
 	node := models.Node{
 		Name: a.nodeName,
 		IP:   a.nodeIP,
@@ -79,7 +113,8 @@ func (a *NodeAgent) Start() error {
 					LastUpdateTime: time.Now(),
 				},
 			},
-			Capacity: getNodeCapacity(),
+			Capacity:    capacity,
+			Allocatable: capacity,
 		},
 		Labels: make(map[string]string), // Initialize empty labels
 	}
@@ -89,21 +124,39 @@ func (a *NodeAgent) Start() error {
 		return fmt.Errorf("❌ failed to register node: %v", err)
 	}
 	fmt.Printf("✅ Successfully registered node %s\n", a.nodeName)
+	a.recorder.Event(models.NodeReference(a.nodeName), models.EventTypeNormal, "Starting", "Starting node agent")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
 
 	// Start monitoring pods
 	fmt.Printf("👀 Starting pod monitor...\n")
-	go a.monitorAndManagePods()
+	go func() {
+		defer wg.Done()
+		a.monitorAndManagePods(ctx)
+	}()
 
 	// Start heartbeat
 	fmt.Printf("💓 Starting heartbeat...\n")
-	go a.startHeartbeat()
+	go func() {
+		defer wg.Done()
+		a.startHeartbeat(ctx)
+	}()
 
+	wg.Wait()
 	return nil
 }
 
-func (a *NodeAgent) startHeartbeat() {
-	ticker := time.NewTicker(30 * time.Second)
-	for range ticker.C {
+func (a *NodeAgent) startHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(a.intervals.Heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		capacity := getNodeCapacity()
 		status := models.NodeStatus{
 			Phase:         "Ready",
 			LastHeartbeat: time.Now(),
@@ -114,7 +167,8 @@ func (a *NodeAgent) startHeartbeat() {
 					LastUpdateTime: time.Now(),
 				},
 			},
-			Capacity: getNodeCapacity(),
+			Capacity:    capacity,
+			Allocatable: capacity,
 		}
 
 		if err := a.client.UpdateNodeStatus(a.nodeName, status); err != nil {
@@ -123,14 +177,20 @@ func (a *NodeAgent) startHeartbeat() {
 	}
 }
 
-func (a *NodeAgent) monitorAndManagePods() {
-	ticker := time.NewTicker(10 * time.Second)
-	previousPods := make(map[string]bool)
-	for range ticker.C {
+func (a *NodeAgent) monitorAndManagePods(ctx context.Context) {
+	ticker := time.NewTicker(a.intervals.PodSync)
+	defer ticker.Stop()
+	previousPods := make(map[string]models.ObjectReference)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 		fmt.Printf("🔍 Checking for pods assigned to node %s...\n", a.nodeName)
-		currentPods := make(map[string]bool)
+		currentPods := make(map[string]models.ObjectReference)
 
-		pods, err := a.client.ListPods("")
+		pods, err := a.ListPods()
 		if err != nil {
 			fmt.Printf("❌ Failed to list pods: %v\n", err)
 			continue
@@ -138,7 +198,7 @@ func (a *NodeAgent) monitorAndManagePods() {
 
 		// Process each pod
 		for _, pod := range pods {
-			currentPods[pod.Metadata.Name] = true
+			currentPods[pod.Metadata.Name] = models.PodReference(pod)
 
 			fmt.Printf("📦 Found pod %s (status: %s, node: %s)\n",
 				pod.Metadata.Name, pod.Status.Phase, pod.Spec.NodeName)
@@ -154,9 +214,10 @@ func (a *NodeAgent) monitorAndManagePods() {
 				fmt.Printf("✅ Successfully started pod %s\n", pod.Metadata.Name)
 			}
 		}
-		for podName := range previousPods {
-			if !currentPods[podName] {
+		for podName, ref := range previousPods {
+			if _, ok := currentPods[podName]; !ok {
 				fmt.Printf("🗑️ Pod %s was deleted, cleaning up containers\n", podName)
+				a.recorder.Eventf(ref, models.EventTypeNormal, "Killing", "Stopping container %s", podName)
 				if err := a.cleanupPod(podName); err != nil {
 					fmt.Printf("❌ Failed to cleanup pod %s: %v\n", podName, err)
 				}
@@ -216,15 +277,15 @@ func (a *NodeAgent) StartPod(pod *models.Pod) error {
 		cpuLimit := "1.0"
 
 		if container.Resources.Limits != nil {
-			if memory := container.Resources.Limits["memory"]; memory != "" {
+			if memory, ok := container.Resources.Limits[models.ResourceMemory]; ok {
 				if converted, err := convertMemoryToDockerFormat(memory); err == nil {
 					memoryLimit = converted
-					fmt.Printf("📦 Using memory limit: %s\n", memoryLimit)
+					fmt.Printf("📦 Using memory limit: %s (%s bytes)\n", memory.String(), memoryLimit)
 				} else {
 					fmt.Printf("⚠️ Memory conversion failed: %v\n", err)
 				}
 			}
-			if cpu := container.Resources.Limits["cpu"]; cpu != "" {
+			if cpu, ok := container.Resources.Limits[models.ResourceCPU]; ok {
 				if converted, err := convertCPU(cpu); err == nil {
 					cpuLimit = converted
 					fmt.Printf("⚙️  Using CPU limit: %s\n", cpuLimit)
@@ -257,21 +318,68 @@ func (a *NodeAgent) StartPod(pod *models.Pod) error {
 				}
 			}
 		}
+		mounts, err := volumeArgs(pod.Spec.Volumes, container.VolumeMounts)
+		if err != nil {
+			a.recorder.Eventf(models.PodReference(*pod), models.EventTypeWarning, "FailedMount",
+				"Container %s: %v", container.Name, err)
+			return err
+		}
+		args = append(args, mounts...)
 		args = append(args, container.Image)
 
 		fmt.Printf("🔧 Starting container with args: docker %s\n", strings.Join(args, " "))
 		cmd = exec.Command("docker", args...)
 		if output, err := cmd.CombinedOutput(); err != nil {
+			a.recorder.Eventf(models.PodReference(*pod), models.EventTypeWarning, "Failed",
+				"Failed to start container %s: %v: %s", containerName, err, strings.TrimSpace(string(output)))
 			return fmt.Errorf("❌ Failed to start container: %v\nOutput: %s", err, string(output))
 		}
 
 		fmt.Printf("✅ Started container %s\n", containerName)
+		a.recorder.Eventf(models.PodReference(*pod), models.EventTypeNormal, "Started",
+			"Started container %s with image %s", containerName, container.Image)
 	}
 
 	pod.Status.Phase = "Running"
 	return a.client.UpdatePodStatus(pod)
 }
 
+// volumeArgs turns a container's volume mounts into docker run flags. Host
+// paths are bind mounted; empty dirs become anonymous volumes, which are
+// removed with the container.
+func volumeArgs(volumes []models.Volume, mounts []models.VolumeMount) ([]string, error) {
+	var args []string
+	for _, mount := range mounts {
+		var volume *models.Volume
+		for i := range volumes {
+			if volumes[i].Name == mount.Name {
+				volume = &volumes[i]
+			}
+		}
+		if volume == nil {
+			return nil, fmt.Errorf("volume mount %s refers to unknown volume %q", mount.MountPath, mount.Name)
+		}
+
+		switch {
+		case volume.HostPath != nil:
+			spec := volume.HostPath.Path + ":" + mount.MountPath
+			if mount.ReadOnly {
+				spec += ":ro"
+			}
+			args = append(args, "-v", spec)
+		case volume.EmptyDir != nil:
+			spec := "type=volume,destination=" + mount.MountPath
+			if mount.ReadOnly {
+				spec += ",readonly"
+			}
+			args = append(args, "--mount", spec)
+		default:
+			return nil, fmt.Errorf("volume %q has no source", volume.Name)
+		}
+	}
+	return args, nil
+}
+
 func isContainerRunning(name string) bool {
 	cmd := exec.Command("docker", "inspect", "-f", "{{.State.Running}}", name)
 	output, err := cmd.CombinedOutput()
@@ -282,98 +390,63 @@ func isContainerRunning(name string) bool {
 }
 
 func getNodeCapacity() models.ResourceList {
+	capacity := models.ResourceList{
+		models.ResourceCPU:    models.NewQuantity(1, models.DecimalSI),
+		models.ResourceMemory: models.MustParse("1024Mi"),
+		models.ResourcePods:   models.NewQuantity(110, models.DecimalSI), // Maximum pods per node
+	}
+
 	// Get CPU info
 	cmd := exec.Command("nproc")
-	output, err := cmd.Output()
-	cpuCount := "1"
-	if err == nil {
-		cpuCount = strings.TrimSpace(string(output))
+	if output, err := cmd.Output(); err == nil {
+		if cpu, err := models.ParseQuantity(strings.TrimSpace(string(output))); err == nil {
+			capacity[models.ResourceCPU] = cpu
+		}
 	}
 
 	// Get memory info
 	cmd = exec.Command("free", "-m")
-	output, err = cmd.Output()
-	memoryMB := "1024"
-	if err == nil {
+	if output, err := cmd.Output(); err == nil {
 		lines := strings.Split(string(output), "\n")
 		if len(lines) > 1 {
 			fields := strings.Fields(lines[1])
 			if len(fields) > 1 {
-				memoryMB = fields[1]
+				if memory, err := models.ParseQuantity(fields[1] + "Mi"); err == nil {
+					capacity[models.ResourceMemory] = memory
+				}
 			}
 		}
 	}
 
-	return models.ResourceList{
-		"cpu":    cpuCount,
-		"memory": fmt.Sprintf("%sMi", memoryMB),
-	}
+	return capacity
 }
 
 func (a *NodeAgent) getNodeResources() models.NodeResources {
-	resources := models.NodeResources{
-		CPU:    "4", // Default values
-		Memory: "8Gi",
-		Pods:   "110", // Maximum pods per node
+	capacity := getNodeCapacity()
+	return models.NodeResources{
+		CPU:    capacity.Cpu(),
+		Memory: capacity.Memory(),
+		Pods:   capacity.Pods(),
 	}
-
-	// Get actual CPU cores
-	if output, err := exec.Command("nproc").Output(); err == nil {
-		resources.CPU = strings.TrimSpace(string(output))
-	}
-
-	// Get actual memory in GB
-	if output, err := exec.Command("free", "-g").Output(); err == nil {
-		lines := strings.Split(string(output), "\n")
-		if len(lines) > 1 {
-			fields := strings.Fields(lines[1])
-			if len(fields) > 1 {
-				resources.Memory = fmt.Sprintf("%sGi", fields[1])
-			}
-		}
-	}
-
-	return resources
 }
 
-func convertMemoryToDockerFormat(memory string) (string, error) {
-	memory = strings.ToLower(strings.TrimSpace(memory))
-	var value float64
-	var unit string
-
-	if _, err := fmt.Sscanf(memory, "%f%s", &value, &unit); err != nil {
-		return "", fmt.Errorf("invalid memory format: %s", memory)
-	}
-
-	switch unit {
-	case "mi", "m":
-		return fmt.Sprintf("%.0fm", value), nil
-	case "gi", "g":
-		return fmt.Sprintf("%.0fg", value), nil
-	case "ki", "k":
-		return fmt.Sprintf("%.0fk", value), nil
-	default:
-		return "", fmt.Errorf("unsupported memory unit: %s", unit)
+// convertMemoryToDockerFormat renders a memory quantity as a plain byte
+// count, which docker run --memory accepts without any suffix.
+func convertMemoryToDockerFormat(memory models.Quantity) (string, error) {
+	bytes := memory.Value()
+	if bytes <= 0 {
+		return "", fmt.Errorf("invalid memory limit: %s", memory.String())
 	}
+	return strconv.FormatInt(bytes, 10), nil
 }
 
-func convertCPU(cpu string) (string, error) {
-	// Handle millicpu format (e.g., "250m")
-	if strings.HasSuffix(cpu, "m") {
-		value, err := strconv.ParseFloat(strings.TrimSuffix(cpu, "m"), 64)
-		if err != nil {
-			return "", fmt.Errorf("invalid CPU format: %s", cpu)
-		}
-		// Convert millicpu to CPU cores (e.g., 250m -> 0.25)
-		return fmt.Sprintf("%.3f", value/1000.0), nil
-	}
-
-	// Handle whole CPU cores
-	value, err := strconv.ParseFloat(cpu, 64)
-	if err != nil {
-		return "", fmt.Errorf("invalid CPU format: %s", cpu)
+// convertCPU renders a CPU quantity as the decimal core count docker run
+// --cpus expects (e.g. 250m -> 0.250).
+func convertCPU(cpu models.Quantity) (string, error) {
+	if cpu.MilliValue() <= 0 {
+		return "", fmt.Errorf("invalid CPU limit: %s", cpu.String())
 	}
-	return fmt.Sprintf("%.3f", value), nil
+	return fmt.Sprintf("%.3f", cpu.AsFloat64()), nil
 }
 
 func getContainerId(containerName string) (string, error) {
@@ -399,7 +472,7 @@ func (a *NodeAgent) cleanupPod(podName string) error {
 		}
 
 		// Remove the container
-		rmCmd := exec.Command("docker", "rm", containerName)
+		rmCmd := exec.Command("docker", "rm", "-v", containerName)
 		if err := rmCmd.Run(); err != nil {
 			return fmt.Errorf("failed to remove container %s: %v", containerName, err)
 		}
@@ -412,7 +485,7 @@ func (a *NodeAgent) cleanupPod(podName string) error {
 
 func (a *NodeAgent) ListPods() ([]models.Pod, error) {
 	// List pods assigned to this node
-	return a.client.ListPods(fmt.Sprintf("?fieldSelector=spec.nodeName=%s", a.nodeName))
+	return a.client.ListPodsWithOptions("", client.ListOptions{FieldSelector: "spec.nodeName=" + a.nodeName})
 }
 
 func (a *NodeAgent) CleanupPod(podName string) error {
@@ -428,7 +501,7 @@ func (a *NodeAgent) CleanupPod(podName string) error {
 		if id == "" {
 			continue
 		}
-		cmd := exec.Command("docker", "rm", "-f", id)
+		cmd := exec.Command("docker", "rm", "-f", "-v", id)
 		if err := cmd.Run(); err != nil {
 			fmt.Printf("❌ Failed to remove container %s: %v\n", id, err)
 		}
@@ -440,3 +513,8 @@ func (a *NodeAgent) CleanupPod(podName string) error {
 func (a *NodeAgent) GetClient() *client.Client {
 	return a.client
 }
+
+// Recorder returns the recorder for events about this node and its pods.
+func (a *NodeAgent) Recorder() *client.EventRecorder {
+	return a.recorder
+}
@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DialPod connects to a port of a running pod bound to this node, on the
+// address docker gave the pod's container.
+func (a *NodeAgent) DialPod(ctx context.Context, namespace, podName string, port int32) (net.Conn, error) {
+	name, err := a.RunningContainer(namespace, podName, "")
+	if err != nil {
+		return nil, err
+	}
+	output, err := exec.Command("docker", "inspect", "-f",
+		"{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker inspect %s failed: %v", name, err)
+	}
+	addresses := strings.Fields(string(output))
+	if len(addresses) == 0 {
+		return nil, invalidError(fmt.Sprintf("pod %q has no IP address", podName))
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addresses[0], strconv.Itoa(int(port))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to port %d of pod %q: %v", port, podName, err)
+	}
+	return conn, nil
+}
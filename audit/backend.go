@@ -0,0 +1,221 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Backend stores audit events. ProcessEvent must not block the request for
+// long; Shutdown flushes anything still buffered.
+type Backend interface {
+	ProcessEvent(event *Event)
+	Shutdown()
+}
+
+// LogBackend writes events as JSON lines to a file, rotating it once it grows
+// past maxSize bytes and keeping maxBackups old files as path.1, path.2, ...
+// A path of "-" writes to stdout without rotation.
+type LogBackend struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewLogBackend(path string, maxSizeMB, maxBackups int) (*LogBackend, error) {
+	b := &LogBackend{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if path == "-" {
+		b.file = os.Stdout
+		return b, nil
+	}
+	if err := b.open(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *LogBackend) open() error {
+	file, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	b.file = file
+	b.size = info.Size()
+	return nil
+}
+
+func (b *LogBackend) ProcessEvent(event *Event) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("❌ Failed to encode audit event: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.path != "-" && b.maxSize > 0 && b.size+int64(len(line)) > b.maxSize && b.size > 0 {
+		if err := b.rotate(); err != nil {
+			log.Printf("❌ Failed to rotate audit log: %v", err)
+		}
+	}
+	n, err := b.file.Write(line)
+	b.size += int64(n)
+	if err != nil {
+		log.Printf("❌ Failed to write audit event: %v", err)
+	}
+}
+
+// rotate shifts path.N to path.N+1, dropping the oldest, and starts a new
+// file. Called with b.mu held.
+func (b *LogBackend) rotate() error {
+	b.file.Close()
+
+	if b.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", b.path, b.maxBackups))
+		for i := b.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", b.path, i), fmt.Sprintf("%s.%d", b.path, i+1))
+		}
+		if err := os.Rename(b.path, b.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(b.path); err != nil {
+		return err
+	}
+	return b.open()
+}
+
+func (b *LogBackend) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.path != "-" {
+		b.file.Close()
+	}
+}
+
+const (
+	webhookBufferSize = 10000
+	webhookBatchSize  = 100
+	webhookBatchWait  = time.Second
+	webhookRetries    = 3
+)
+
+// WebhookBackend posts batches of events as an EventList to a URL. Events
+// are buffered and sent in the background; when the buffer is full new
+// events are dropped rather than holding up requests.
+type WebhookBackend struct {
+	url    string
+	client *http.Client
+	events chan *Event
+	done   chan struct{}
+}
+
+func NewWebhookBackend(url string) *WebhookBackend {
+	b := &WebhookBackend{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		events: make(chan *Event, webhookBufferSize),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *WebhookBackend) ProcessEvent(event *Event) {
+	select {
+	case b.events <- event:
+	default:
+		log.Printf("⚠️ Audit webhook buffer full, dropping event %s", event.AuditID)
+	}
+}
+
+func (b *WebhookBackend) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(webhookBatchWait)
+	defer ticker.Stop()
+
+	var batch []Event
+	for {
+		select {
+		case event, ok := <-b.events:
+			if !ok {
+				b.send(batch)
+				return
+			}
+			batch = append(batch, *event)
+			if len(batch) >= webhookBatchSize {
+				b.send(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			b.send(batch)
+			batch = nil
+		}
+	}
+}
+
+func (b *WebhookBackend) send(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := json.Marshal(EventList{Kind: "EventList", Items: batch})
+	if err != nil {
+		log.Printf("❌ Failed to encode audit events: %v", err)
+		return
+	}
+
+	for attempt := 1; attempt <= webhookRetries; attempt++ {
+		resp, err := b.client.Post(b.url, "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		log.Printf("⚠️ Audit webhook attempt %d failed: %v", attempt, err)
+		time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+	}
+	log.Printf("❌ Dropped %d audit events after %d attempts", len(batch), webhookRetries)
+}
+
+// Shutdown sends buffered events and waits for the sender to finish.
+func (b *WebhookBackend) Shutdown() {
+	close(b.events)
+	<-b.done
+}
+
+// union sends every event to several backends.
+type union []Backend
+
+func (u union) ProcessEvent(event *Event) {
+	for _, b := range u {
+		b.ProcessEvent(event)
+	}
+}
+
+func (u union) Shutdown() {
+	for _, b := range u {
+		b.Shutdown()
+	}
+}
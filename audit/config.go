@@ -0,0 +1,40 @@
+package audit
+
+// Config selects the audit policy and where events are written.
+type Config struct {
+	PolicyFile    string // defaults to recording metadata for every request
+	LogPath       string // JSON lines file, "-" for stdout
+	LogMaxSizeMB  int    // rotate the log after this size, 0 never rotates
+	LogMaxBackups int    // rotated files to keep
+	WebhookURL    string // POST batches of events here
+}
+
+// Enabled reports whether any backend is configured.
+func (c Config) Enabled() bool {
+	return c.LogPath != "" || c.WebhookURL != ""
+}
+
+// New loads the policy and builds the configured backends.
+func New(c Config) (*Policy, Backend, error) {
+	policy := DefaultPolicy()
+	if c.PolicyFile != "" {
+		loaded, err := LoadPolicy(c.PolicyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		policy = loaded
+	}
+
+	var backends union
+	if c.LogPath != "" {
+		logBackend, err := NewLogBackend(c.LogPath, c.LogMaxSizeMB, c.LogMaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		backends = append(backends, logBackend)
+	}
+	if c.WebhookURL != "" {
+		backends = append(backends, NewWebhookBackend(c.WebhookURL))
+	}
+	return policy, backends, nil
+}
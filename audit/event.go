@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+)
+
+// Level controls how much of a request is recorded.
+type Level string
+
+const (
+	LevelNone            Level = "None"            // not recorded
+	LevelMetadata        Level = "Metadata"        // user, verb, resource, response code
+	LevelRequest         Level = "Request"         // metadata and request body
+	LevelRequestResponse Level = "RequestResponse" // metadata, request and response bodies
+)
+
+// Less reports whether l records less than other.
+func (l Level) Less(other Level) bool {
+	return levelOrder[l] < levelOrder[other]
+}
+
+var levelOrder = map[Level]int{
+	LevelNone:            0,
+	LevelMetadata:        1,
+	LevelRequest:         2,
+	LevelRequestResponse: 3,
+}
+
+// ObjectRef identifies the object a request acted on.
+type ObjectRef struct {
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type ResponseStatus struct {
+	Code int `json:"code"`
+}
+
+// Event is a single audit record, written once the response is complete.
+type Event struct {
+	Kind                     string          `json:"kind"`
+	Level                    Level           `json:"level"`
+	AuditID                  string          `json:"auditID"`
+	RequestURI               string          `json:"requestURI"`
+	Verb                     string          `json:"verb"`
+	User                     auth.UserInfo   `json:"user"`
+	SourceIPs                []string        `json:"sourceIPs,omitempty"`
+	UserAgent                string          `json:"userAgent,omitempty"`
+	ObjectRef                *ObjectRef      `json:"objectRef,omitempty"`
+	ResponseStatus           ResponseStatus  `json:"responseStatus"`
+	RequestObject            json.RawMessage `json:"requestObject,omitempty"`
+	ResponseObject           json.RawMessage `json:"responseObject,omitempty"`
+	RequestReceivedTimestamp time.Time       `json:"requestReceivedTimestamp"`
+	StageTimestamp           time.Time       `json:"stageTimestamp"`
+}
+
+// EventList is the body posted to webhook backends.
+type EventList struct {
+	Kind  string  `json:"kind"`
+	Items []Event `json:"items"`
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy decides the level each request is recorded at. Rules are checked in
+// order and the first match wins; requests matching no rule are not
+// recorded.
+//
+//	apiVersion: audit.k8s.io/v1
+//	kind: Policy
+//	rules:
+//	  - level: None
+//	    verbs: ["get", "list", "watch"]
+//	  - level: RequestResponse
+//	    resources: ["pods"]
+//	    verbs: ["delete"]
+//	  - level: Metadata
+type Policy struct {
+	APIVersion string       `yaml:"apiVersion"`
+	Kind       string       `yaml:"kind"`
+	Rules      []PolicyRule `yaml:"rules"`
+}
+
+// PolicyRule matches requests by user, group, verb, resource, namespace or
+// non-resource path. Empty fields match anything.
+type PolicyRule struct {
+	Level           Level    `yaml:"level"`
+	Users           []string `yaml:"users,omitempty"`
+	UserGroups      []string `yaml:"userGroups,omitempty"`
+	Verbs           []string `yaml:"verbs,omitempty"`
+	Resources       []string `yaml:"resources,omitempty"` // e.g. pods, pods/status
+	Namespaces      []string `yaml:"namespaces,omitempty"`
+	NonResourceURLs []string `yaml:"nonResourceURLs,omitempty"` // a trailing * matches a prefix
+}
+
+// DefaultPolicy records the metadata of every request.
+func DefaultPolicy() *Policy {
+	return &Policy{Rules: []PolicyRule{{Level: LevelMetadata}}}
+}
+
+// LoadPolicy reads a policy file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit policy: %v", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse audit policy: %v", err)
+	}
+	for i, rule := range policy.Rules {
+		if _, ok := levelOrder[rule.Level]; !ok {
+			return nil, fmt.Errorf("audit policy rule %d: unknown level %q", i, rule.Level)
+		}
+	}
+	return &policy, nil
+}
+
+// LevelFor returns the level a request is recorded at.
+func (p *Policy) LevelFor(attrs auth.Attributes) Level {
+	for _, rule := range p.Rules {
+		if rule.matches(attrs) {
+			return rule.Level
+		}
+	}
+	return LevelNone
+}
+
+func (r PolicyRule) matches(attrs auth.Attributes) bool {
+	if len(r.Users) > 0 && (attrs.User == nil || !contains(r.Users, attrs.User.Name)) {
+		return false
+	}
+	if len(r.UserGroups) > 0 {
+		if attrs.User == nil {
+			return false
+		}
+		found := false
+		for _, group := range r.UserGroups {
+			if attrs.User.InGroup(group) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.Verbs) > 0 && !contains(r.Verbs, attrs.Verb) {
+		return false
+	}
+
+	// A rule naming resources or paths only applies to that kind of request
+	if !attrs.IsResourceRequest {
+		if len(r.Resources) > 0 || len(r.Namespaces) > 0 {
+			return false
+		}
+		if len(r.NonResourceURLs) == 0 {
+			return true
+		}
+		for _, url := range r.NonResourceURLs {
+			if url == attrs.Path || strings.HasSuffix(url, "*") && strings.HasPrefix(attrs.Path, strings.TrimSuffix(url, "*")) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(r.NonResourceURLs) > 0 {
+		return false
+	}
+	if len(r.Resources) > 0 && !contains(r.Resources, attrs.Resource) && !contains(r.Resources, attrs.ResourceString()) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !contains(r.Namespaces, attrs.Namespace) {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == "*" || item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	AnonymousUser        = "system:anonymous"
+	UnauthenticatedGroup = "system:unauthenticated"
+	AuthenticatedGroup   = "system:authenticated"
+	NodesGroup           = "system:nodes"
+	NodeUserPrefix       = "node:"
+	// KubeletClientUser is the user the API server calls node servers as.
+	KubeletClientUser = "mykube-apiserver-kubelet-client"
+)
+
+// UserInfo describes who made a request.
+type UserInfo struct {
+	Name   string   `json:"username"`
+	UID    string   `json:"uid,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+func (u *UserInfo) InGroup(group string) bool {
+	for _, g := range u.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAnonymous reports whether the request carried no credentials at all.
+func (u *UserInfo) IsAnonymous() bool {
+	return u.Name == AnonymousUser
+}
+
+// NodeName returns the node a node identity ("node:<name>") belongs to.
+func (u *UserInfo) NodeName() (string, bool) {
+	if !strings.HasPrefix(u.Name, NodeUserPrefix) || !u.InGroup(NodesGroup) {
+		return "", false
+	}
+	return strings.TrimPrefix(u.Name, NodeUserPrefix), true
+}
+
+// Authenticator inspects a request and returns the user it was made by.
+// ok is false when the request carries no credentials this authenticator
+// understands; err is set when it carries credentials that are invalid.
+type Authenticator interface {
+	AuthenticateRequest(r *http.Request) (user *UserInfo, ok bool, err error)
+}
+
+// Union tries each authenticator in turn and returns the first match.
+type Union []Authenticator
+
+func (u Union) AuthenticateRequest(r *http.Request) (*UserInfo, bool, error) {
+	for _, a := range u {
+		user, ok, err := a.AuthenticateRequest(r)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			if !user.InGroup(AuthenticatedGroup) {
+				user.Groups = append(user.Groups, AuthenticatedGroup)
+			}
+			return user, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	return token, token != ""
+}
+
+type contextKey int
+
+const userKey contextKey = iota
+
+// WithUser returns a copy of ctx carrying user.
+func WithUser(ctx context.Context, user *UserInfo) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFrom returns the user stored in ctx by WithUser.
+func UserFrom(ctx context.Context) (*UserInfo, bool) {
+	user, ok := ctx.Value(userKey).(*UserInfo)
+	return user, ok
+}
@@ -0,0 +1,84 @@
+package auth
+
+import "fmt"
+
+// Decision is the outcome of a single authorizer.
+type Decision int
+
+const (
+	DecisionNoOpinion Decision = iota
+	DecisionAllow
+	DecisionDeny
+)
+
+// Attributes describe what a request is trying to do.
+type Attributes struct {
+	User              *UserInfo
+	Verb              string // get, list, watch, create, update, patch, delete, deletecollection
+	IsResourceRequest bool
+	Path              string // request path, used for non-resource requests
+
+	Namespace   string // empty for cluster scoped resources and cluster-wide lists
+	Resource    string // e.g. pods
+	Subresource string // e.g. status
+	Name        string
+}
+
+// ResourceString returns "pods" or "pods/status".
+func (a Attributes) ResourceString() string {
+	if a.Subresource != "" {
+		return a.Resource + "/" + a.Subresource
+	}
+	return a.Resource
+}
+
+// String renders the request for denial messages.
+func (a Attributes) String() string {
+	user := "<unknown>"
+	if a.User != nil {
+		user = a.User.Name
+	}
+	if !a.IsResourceRequest {
+		return fmt.Sprintf("user %q cannot %s path %q", user, a.Verb, a.Path)
+	}
+	msg := fmt.Sprintf("user %q cannot %s resource %q", user, a.Verb, a.ResourceString())
+	if a.Name != "" {
+		msg += fmt.Sprintf(" named %q", a.Name)
+	}
+	if a.Namespace != "" {
+		msg += fmt.Sprintf(" in namespace %q", a.Namespace)
+	} else {
+		msg += " at the cluster scope"
+	}
+	return msg
+}
+
+// Authorizer decides whether the request described by attrs may proceed.
+type Authorizer interface {
+	Authorize(attrs Attributes) (Decision, string, error)
+}
+
+// Chain asks each authorizer in turn; the first to allow or deny wins and
+// a request nobody has an opinion on is denied.
+type Chain []Authorizer
+
+func (c Chain) Authorize(attrs Attributes) (Decision, string, error) {
+	for _, a := range c {
+		decision, reason, err := a.Authorize(attrs)
+		if err != nil {
+			return DecisionDeny, "", err
+		}
+		if decision != DecisionNoOpinion {
+			return decision, reason, nil
+		}
+	}
+	return DecisionDeny, "no authorizer allowed the request", nil
+}
+
+// AlwaysAllow permits everything; it is the default until an authorization
+// mode is configured.
+type AlwaysAllow struct{}
+
+func (AlwaysAllow) Authorize(attrs Attributes) (Decision, string, error) {
+	return DecisionAllow, "", nil
+}
@@ -0,0 +1,41 @@
+package auth
+
+import "fmt"
+
+// Config selects which authenticators the API server enables.
+type Config struct {
+	TokenFile             string // static token CSV
+	ServiceAccountKeyFile string // RSA key used to verify service account tokens
+	ClientCAFile          string // enables client certificate authentication
+	AllowAnonymous        bool   // let requests without credentials read
+}
+
+// Enabled reports whether any authenticator is configured.
+func (c Config) Enabled() bool {
+	return c.TokenFile != "" || c.ServiceAccountKeyFile != "" || c.ClientCAFile != ""
+}
+
+// NewAuthenticator builds the union of all configured authenticators.
+func NewAuthenticator(c Config) (Authenticator, error) {
+	var union Union
+
+	if c.ClientCAFile != "" {
+		union = append(union, NewX509Authenticator())
+	}
+	if c.TokenFile != "" {
+		tokens, err := NewTokenFileAuthenticator(c.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		union = append(union, tokens)
+	}
+	if c.ServiceAccountKeyFile != "" {
+		key, err := LoadPublicKey(c.ServiceAccountKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load service account key: %v", err)
+		}
+		union = append(union, NewServiceAccountAuthenticator(key))
+	}
+
+	return union, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// PodGetter looks up a pod so the node authorizer can see where it is bound.
+type PodGetter interface {
+	GetPod(namespace, name string) (models.Pod, bool)
+}
+
+// NodeAuthorizer limits node identities ("node:<name>" in system:nodes) to
+// reading cluster state, registering and updating their own Node,
+// updating the status of and deleting pods that are bound to them and
+// recording events. Nodes cannot change a pod's spec, so they cannot move
+// a pod by changing spec.nodeName.
+type NodeAuthorizer struct {
+	pods PodGetter
+}
+
+func NewNodeAuthorizer(pods PodGetter) *NodeAuthorizer {
+	return &NodeAuthorizer{pods: pods}
+}
+
+func (a *NodeAuthorizer) Authorize(attrs Attributes) (Decision, string, error) {
+	if attrs.User == nil || !attrs.IsResourceRequest {
+		return DecisionNoOpinion, "", nil
+	}
+	nodeName, ok := attrs.User.NodeName()
+	if !ok {
+		return DecisionNoOpinion, "", nil
+	}
+
+	readOnly := attrs.Verb == "get" || attrs.Verb == "list" || attrs.Verb == "watch"
+
+	switch attrs.Resource {
+	case "nodes":
+		if readOnly {
+			return DecisionAllow, "", nil
+		}
+		switch attrs.Verb {
+		case "create":
+			// The node name is in the body, the API server checks it on create
+			return DecisionAllow, "", nil
+		case "update", "patch":
+			if attrs.Name == nodeName {
+				return DecisionAllow, "", nil
+			}
+		}
+		return DecisionDeny, fmt.Sprintf("node %q can only modify its own Node object", nodeName), nil
+
+	case "pods":
+		if readOnly {
+			// Subresources such as pods/log are left to the other
+			// authorizers
+			if attrs.Subresource != "" {
+				return DecisionNoOpinion, "", nil
+			}
+			return DecisionAllow, "", nil
+		}
+		switch {
+		case (attrs.Verb == "update" || attrs.Verb == "patch") && attrs.Subresource != "status":
+			return DecisionDeny, fmt.Sprintf("node %q can only update the status of pods", nodeName), nil
+		case attrs.Verb == "update", attrs.Verb == "patch", attrs.Verb == "delete":
+			pod, found := a.pods.GetPod(attrs.Namespace, attrs.Name)
+			if !found {
+				return DecisionDeny, fmt.Sprintf("pod %q not found", attrs.Name), nil
+			}
+			if pod.Spec.NodeName == nodeName {
+				return DecisionAllow, "", nil
+			}
+		}
+		return DecisionDeny, fmt.Sprintf("node %q can only modify pods bound to it", nodeName), nil
+
+	case "events":
+		// Nodes report what happens to their pods
+		switch attrs.Verb {
+		case "create", "update", "patch":
+			return DecisionAllow, "", nil
+		}
+
+	case "services":
+		if readOnly {
+			return DecisionAllow, "", nil
+		}
+	}
+
+	return DecisionNoOpinion, "", nil
+}
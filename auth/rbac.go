@@ -0,0 +1,345 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+const (
+	// MastersGroup bypasses RBAC entirely so the cluster can be bootstrapped.
+	MastersGroup = "system:masters"
+)
+
+// RBACStore is the storage the RBAC authorizer reads roles and bindings from.
+type RBACStore interface {
+	GetRole(namespace, name string) (models.Role, bool, error)
+	GetClusterRole(name string) (models.ClusterRole, bool, error)
+	ListRoleBindings(namespace string) ([]models.RoleBinding, error)
+	ListClusterRoleBindings() ([]models.ClusterRoleBinding, error)
+}
+
+// RBACAuthorizer allows a request when a role bound to the user has a rule
+// matching it. It never denies, leaving that to the end of the chain. Its
+// Confirm methods stop users from granting themselves more through roles
+// and bindings.
+type RBACAuthorizer struct {
+	store RBACStore
+}
+
+func NewRBACAuthorizer(store RBACStore) *RBACAuthorizer {
+	return &RBACAuthorizer{store: store}
+}
+
+func (a *RBACAuthorizer) Authorize(attrs Attributes) (Decision, string, error) {
+	if attrs.User == nil {
+		return DecisionNoOpinion, "", nil
+	}
+	if attrs.User.InGroup(MastersGroup) {
+		return DecisionAllow, "member of " + MastersGroup, nil
+	}
+
+	clusterBindings, err := a.store.ListClusterRoleBindings()
+	if err != nil {
+		return DecisionNoOpinion, "", fmt.Errorf("failed to list cluster role bindings: %v", err)
+	}
+	for _, binding := range clusterBindings {
+		if !subjectsMatch(binding.Subjects, attrs.User, "") || binding.RoleRef.Kind != "ClusterRole" {
+			continue
+		}
+		role, found, err := a.store.GetClusterRole(binding.RoleRef.Name)
+		if err != nil {
+			return DecisionNoOpinion, "", err
+		}
+		if found && rulesAllow(role.Rules, attrs) {
+			return DecisionAllow, fmt.Sprintf("allowed by ClusterRoleBinding %q", binding.Metadata.Name), nil
+		}
+	}
+
+	if attrs.Namespace == "" || !attrs.IsResourceRequest {
+		return DecisionNoOpinion, "", nil
+	}
+
+	bindings, err := a.store.ListRoleBindings(attrs.Namespace)
+	if err != nil {
+		return DecisionNoOpinion, "", fmt.Errorf("failed to list role bindings: %v", err)
+	}
+	for _, binding := range bindings {
+		if !subjectsMatch(binding.Subjects, attrs.User, binding.Metadata.Namespace) {
+			continue
+		}
+
+		rules, _, err := a.roleRules(binding.RoleRef.Kind, binding.Metadata.Namespace, binding.RoleRef.Name)
+		if err != nil {
+			return DecisionNoOpinion, "", err
+		}
+		if rulesAllow(rules, attrs) {
+			return DecisionAllow, fmt.Sprintf("allowed by RoleBinding %q in namespace %q",
+				binding.Metadata.Name, binding.Metadata.Namespace), nil
+		}
+	}
+
+	return DecisionNoOpinion, "", nil
+}
+
+// ConfirmRoleWrite returns an error unless user may store a role with
+// rules: resource is roles, with the role's namespace, or clusterroles. A
+// user can only grant what it already holds, unless it may escalate the
+// role.
+func (a *RBACAuthorizer) ConfirmRoleWrite(user *UserInfo, resource, namespace, name string, rules []models.PolicyRule) error {
+	allowed, err := a.allows(user, "escalate", resource, namespace, name)
+	if err != nil || allowed {
+		return err
+	}
+	return a.confirmHeld(user, namespace, rules)
+}
+
+// ConfirmBinding returns an error unless user may bind the role ref points
+// to in namespace, "" for a cluster role binding. A user can only bind a
+// role whose rules it already holds, unless it may bind the role.
+func (a *RBACAuthorizer) ConfirmBinding(user *UserInfo, namespace string, ref models.RoleRef) error {
+	resource, roleNamespace := "clusterroles", ""
+	if ref.Kind == "Role" {
+		resource, roleNamespace = "roles", namespace
+	}
+	allowed, err := a.allows(user, "bind", resource, namespace, ref.Name)
+	if err != nil || allowed {
+		return err
+	}
+	rules, found, err := a.roleRules(ref.Kind, roleNamespace, ref.Name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("user %q cannot bind %s %q, which does not exist", user.Name, ref.Kind, ref.Name)
+	}
+	return a.confirmHeld(user, namespace, rules)
+}
+
+// allows reports whether RBAC lets user use verb on the named object.
+func (a *RBACAuthorizer) allows(user *UserInfo, verb, resource, namespace, name string) (bool, error) {
+	decision, _, err := a.Authorize(Attributes{
+		User: user, Verb: verb, IsResourceRequest: true,
+		Namespace: namespace, Resource: resource, Name: name,
+	})
+	return decision == DecisionAllow, err
+}
+
+// confirmHeld returns an error naming the rules user does not hold in
+// namespace, or cluster-wide when namespace is empty.
+func (a *RBACAuthorizer) confirmHeld(user *UserInfo, namespace string, rules []models.PolicyRule) error {
+	if user.InGroup(MastersGroup) {
+		return nil
+	}
+	held, err := a.rulesFor(user, namespace)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, rule := range rules {
+		if !rulesCover(held, rule) {
+			missing = append(missing, fmt.Sprintf("%+v", rule))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("user %q is attempting to grant permissions it does not hold: %s",
+			user.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// rulesFor returns the rules the roles bound to user grant in namespace:
+// those of cluster role bindings and, if namespace is set, of the role
+// bindings there.
+func (a *RBACAuthorizer) rulesFor(user *UserInfo, namespace string) ([]models.PolicyRule, error) {
+	var rules []models.PolicyRule
+	clusterBindings, err := a.store.ListClusterRoleBindings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role bindings: %v", err)
+	}
+	for _, binding := range clusterBindings {
+		if !subjectsMatch(binding.Subjects, user, "") || binding.RoleRef.Kind != "ClusterRole" {
+			continue
+		}
+		roleRules, _, err := a.roleRules("ClusterRole", "", binding.RoleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, roleRules...)
+	}
+	if namespace == "" {
+		return rules, nil
+	}
+
+	bindings, err := a.store.ListRoleBindings(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %v", err)
+	}
+	for _, binding := range bindings {
+		if !subjectsMatch(binding.Subjects, user, binding.Metadata.Namespace) {
+			continue
+		}
+		roleRules, _, err := a.roleRules(binding.RoleRef.Kind, binding.Metadata.Namespace, binding.RoleRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, roleRules...)
+	}
+	return rules, nil
+}
+
+// roleRules loads the rules of a Role in namespace or of a ClusterRole.
+func (a *RBACAuthorizer) roleRules(kind, namespace, name string) ([]models.PolicyRule, bool, error) {
+	switch kind {
+	case "Role":
+		role, found, err := a.store.GetRole(namespace, name)
+		return role.Rules, found, err
+	case "ClusterRole":
+		role, found, err := a.store.GetClusterRole(name)
+		return role.Rules, found, err
+	}
+	return nil, false, nil
+}
+
+// rulesCover reports whether held grants everything requested does. Each
+// verb, resource and name, or URL, of requested may be granted by a
+// different held rule.
+func rulesCover(held []models.PolicyRule, requested models.PolicyRule) bool {
+	names := requested.ResourceNames
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	for _, verb := range requested.Verbs {
+		for _, resource := range requested.Resources {
+			for _, name := range names {
+				if !anyRule(held, func(rule models.PolicyRule) bool {
+					return matchesAny(rule.Verbs, verb) && resourceCovered(rule, resource, name)
+				}) {
+					return false
+				}
+			}
+		}
+		for _, url := range requested.NonResourceURLs {
+			if !anyRule(held, func(rule models.PolicyRule) bool {
+				return matchesAny(rule.Verbs, verb) && urlCovered(rule.NonResourceURLs, url)
+			}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func anyRule(rules []models.PolicyRule, match func(models.PolicyRule) bool) bool {
+	for _, rule := range rules {
+		if match(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceCovered reports whether rule grants resource, e.g. pods or
+// pods/log, named name, or with any name when name is empty.
+func resourceCovered(rule models.PolicyRule, resource, name string) bool {
+	covered := false
+	for _, r := range rule.Resources {
+		if r == "*" || r == resource ||
+			(strings.HasSuffix(r, "/*") && strings.HasPrefix(resource, strings.TrimSuffix(r, "*"))) {
+			covered = true
+			break
+		}
+	}
+	if !covered {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || (name != "" && matchesAny(rule.ResourceNames, name))
+}
+
+// urlCovered reports whether the URLs of a rule include url, which may
+// itself end in a wildcard.
+func urlCovered(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == "*" || u == url ||
+			(strings.HasSuffix(u, "*") && strings.HasPrefix(url, strings.TrimSuffix(u, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func subjectsMatch(subjects []models.Subject, user *UserInfo, bindingNamespace string) bool {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case models.SubjectUser:
+			if subject.Name == user.Name {
+				return true
+			}
+		case models.SubjectGroup:
+			if user.InGroup(subject.Name) {
+				return true
+			}
+		case models.SubjectServiceAccount:
+			namespace := subject.Namespace
+			if namespace == "" {
+				namespace = bindingNamespace
+			}
+			if user.Name == ServiceAccountUsername(namespace, subject.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func rulesAllow(rules []models.PolicyRule, attrs Attributes) bool {
+	for _, rule := range rules {
+		if ruleAllows(rule, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+func ruleAllows(rule models.PolicyRule, attrs Attributes) bool {
+	if !matchesAny(rule.Verbs, attrs.Verb) {
+		return false
+	}
+
+	if !attrs.IsResourceRequest {
+		for _, url := range rule.NonResourceURLs {
+			if url == "*" || url == attrs.Path ||
+				(strings.HasSuffix(url, "*") && strings.HasPrefix(attrs.Path, strings.TrimSuffix(url, "*"))) {
+				return true
+			}
+		}
+		return false
+	}
+
+	resourceMatched := false
+	for _, resource := range rule.Resources {
+		if resource == "*" || resource == attrs.ResourceString() ||
+			(attrs.Subresource != "" && resource == attrs.Resource+"/*") {
+			resourceMatched = true
+			break
+		}
+	}
+	if !resourceMatched {
+		return false
+	}
+
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	return attrs.Name != "" && matchesAny(rule.ResourceNames, attrs.Name)
+}
+
+func matchesAny(values []string, want string) bool {
+	for _, v := range values {
+		if v == "*" || v == want {
+			return true
+		}
+	}
+	return false
+}
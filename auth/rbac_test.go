@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// fakeRBACStore serves fixed roles and bindings.
+type fakeRBACStore struct {
+	roles               map[string]models.Role // by namespace/name
+	clusterRoles        map[string]models.ClusterRole
+	roleBindings        []models.RoleBinding
+	clusterRoleBindings []models.ClusterRoleBinding
+}
+
+func (s *fakeRBACStore) GetRole(namespace, name string) (models.Role, bool, error) {
+	role, found := s.roles[namespace+"/"+name]
+	return role, found, nil
+}
+
+func (s *fakeRBACStore) GetClusterRole(name string) (models.ClusterRole, bool, error) {
+	role, found := s.clusterRoles[name]
+	return role, found, nil
+}
+
+func (s *fakeRBACStore) ListRoleBindings(namespace string) ([]models.RoleBinding, error) {
+	var bindings []models.RoleBinding
+	for _, binding := range s.roleBindings {
+		if binding.Metadata.Namespace == namespace {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+func (s *fakeRBACStore) ListClusterRoleBindings() ([]models.ClusterRoleBinding, error) {
+	return s.clusterRoleBindings, nil
+}
+
+func escalationStore() *fakeRBACStore {
+	bindUser := func(name, namespace, kind, role string) models.RoleBinding {
+		return models.RoleBinding{
+			Metadata: models.Metadata{Name: name, Namespace: namespace},
+			Subjects: []models.Subject{{Kind: models.SubjectUser, Name: name}},
+			RoleRef:  models.RoleRef{Kind: kind, Name: role},
+		}
+	}
+	return &fakeRBACStore{
+		roles: map[string]models.Role{
+			"dev/pod-reader": {Rules: []models.PolicyRule{{Verbs: []string{"get", "list"}, Resources: []string{"pods"}}}},
+			"dev/rbac-admin": {Rules: []models.PolicyRule{{Verbs: []string{"get", "create", "update", "patch"}, Resources: []string{"roles", "rolebindings"}}}},
+		},
+		clusterRoles: map[string]models.ClusterRole{
+			"admin":    {Rules: []models.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"*"}}}},
+			"escalate": {Rules: []models.PolicyRule{{Verbs: []string{"escalate", "bind"}, Resources: []string{"roles"}}}},
+			"reader":   {Rules: []models.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods", "pods/*"}}}},
+		},
+		roleBindings: []models.RoleBinding{
+			bindUser("alice", "dev", "Role", "pod-reader"),
+			bindUser("alice", "dev", "Role", "rbac-admin"),
+			bindUser("bob", "dev", "Role", "rbac-admin"),
+			bindUser("bob", "dev", "ClusterRole", "escalate"),
+		},
+		clusterRoleBindings: []models.ClusterRoleBinding{{
+			Metadata: models.Metadata{Name: "carol"},
+			Subjects: []models.Subject{{Kind: models.SubjectUser, Name: "carol"}},
+			RoleRef:  models.RoleRef{Kind: "ClusterRole", Name: "reader"},
+		}},
+	}
+}
+
+func TestConfirmRoleWrite(t *testing.T) {
+	rbac := NewRBACAuthorizer(escalationStore())
+	readPods := []models.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods"}}}
+	deletePods := []models.PolicyRule{{Verbs: []string{"delete"}, Resources: []string{"pods"}}}
+
+	tests := []struct {
+		name      string
+		user      *UserInfo
+		resource  string
+		namespace string
+		rules     []models.PolicyRule
+		allowed   bool
+	}{
+		{"held rules", &UserInfo{Name: "alice"}, "roles", "dev", readPods, true},
+		{"rules not held", &UserInfo{Name: "alice"}, "roles", "dev", deletePods, false},
+		{"wildcard verb not held", &UserInfo{Name: "alice"}, "roles", "dev",
+			[]models.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"pods"}}}, false},
+		{"rules held in another namespace", &UserInfo{Name: "alice"}, "roles", "prod", readPods, false},
+		{"held across rules", &UserInfo{Name: "alice"}, "roles", "dev",
+			[]models.PolicyRule{{Verbs: []string{"get", "update"}, Resources: []string{"pods", "roles"}}}, false},
+		{"named subset of held rules", &UserInfo{Name: "alice"}, "roles", "dev",
+			[]models.PolicyRule{{Verbs: []string{"list"}, Resources: []string{"pods"}, ResourceNames: []string{"web"}}}, true},
+		{"subresource held through wildcard", &UserInfo{Name: "carol"}, "clusterroles", "",
+			[]models.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods/log"}}}, true},
+		{"cluster rules held in namespace", &UserInfo{Name: "carol"}, "roles", "dev", readPods, true},
+		{"escalate verb", &UserInfo{Name: "bob"}, "roles", "dev", deletePods, true},
+		{"escalate verb only for roles", &UserInfo{Name: "bob"}, "clusterroles", "", deletePods, false},
+		{"masters", &UserInfo{Name: "root", Groups: []string{MastersGroup}}, "clusterroles", "", deletePods, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rbac.ConfirmRoleWrite(tt.user, tt.resource, tt.namespace, "new-role", tt.rules)
+			if tt.allowed && err != nil {
+				t.Errorf("expected the role to be allowed, got %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("expected the role to be rejected")
+			}
+		})
+	}
+}
+
+func TestConfirmBinding(t *testing.T) {
+	rbac := NewRBACAuthorizer(escalationStore())
+
+	tests := []struct {
+		name      string
+		user      *UserInfo
+		namespace string
+		ref       models.RoleRef
+		allowed   bool
+	}{
+		{"role whose rules are held", &UserInfo{Name: "alice"}, "dev", models.RoleRef{Kind: "Role", Name: "pod-reader"}, true},
+		{"cluster role with a subresource rule not held", &UserInfo{Name: "alice"}, "dev",
+			models.RoleRef{Kind: "ClusterRole", Name: "reader"}, false},
+		{"cluster role not held", &UserInfo{Name: "alice"}, "dev", models.RoleRef{Kind: "ClusterRole", Name: "admin"}, false},
+		{"cluster binding of a held cluster role", &UserInfo{Name: "carol"}, "", models.RoleRef{Kind: "ClusterRole", Name: "reader"}, true},
+		{"cluster binding not held", &UserInfo{Name: "carol"}, "", models.RoleRef{Kind: "ClusterRole", Name: "admin"}, false},
+		{"bind verb", &UserInfo{Name: "bob"}, "dev", models.RoleRef{Kind: "Role", Name: "pod-reader"}, true},
+		{"missing role", &UserInfo{Name: "alice"}, "dev", models.RoleRef{Kind: "Role", Name: "missing"}, false},
+		{"masters", &UserInfo{Name: "root", Groups: []string{MastersGroup}}, "", models.RoleRef{Kind: "ClusterRole", Name: "admin"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rbac.ConfirmBinding(tt.user, tt.namespace, tt.ref)
+			if tt.allowed && err != nil {
+				t.Errorf("expected the binding to be allowed, got %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("expected the binding to be rejected")
+			}
+		})
+	}
+}
+
+func TestConfirmRoleWriteNamesMissingRules(t *testing.T) {
+	rbac := NewRBACAuthorizer(escalationStore())
+	err := rbac.ConfirmRoleWrite(&UserInfo{Name: "alice"}, "roles", "dev", "new-role",
+		[]models.PolicyRule{{Verbs: []string{"delete"}, Resources: []string{"secrets"}}})
+	if err == nil || !strings.Contains(err.Error(), "secrets") {
+		t.Fatalf("expected an error naming the secrets rule, got %v", err)
+	}
+}
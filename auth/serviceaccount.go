@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	ServiceAccountIssuer      = "mykube/serviceaccount"
+	ServiceAccountUserPrefix  = "system:serviceaccount:"
+	ServiceAccountsGroup      = "system:serviceaccounts"
+	serviceAccountGroupPrefix = "system:serviceaccounts:"
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type serviceAccountClaims struct {
+	Issuer         string `json:"iss"`
+	Subject        string `json:"sub"`
+	Namespace      string `json:"mykube.io/namespace"`
+	ServiceAccount string `json:"mykube.io/serviceaccount"`
+	IssuedAt       int64  `json:"iat"`
+	ExpiresAt      int64  `json:"exp,omitempty"`
+}
+
+// ServiceAccountUsername returns the user name a service account token
+// authenticates as.
+func ServiceAccountUsername(namespace, name string) string {
+	return ServiceAccountUserPrefix + namespace + ":" + name
+}
+
+// SignServiceAccountToken issues an RS256 JWT for namespace/name. A zero ttl
+// yields a token that never expires.
+func SignServiceAccountToken(key *rsa.PrivateKey, namespace, name string, ttl time.Duration) (string, error) {
+	if namespace == "" || name == "" {
+		return "", fmt.Errorf("service account namespace and name are required")
+	}
+
+	now := time.Now()
+	claims := serviceAccountClaims{
+		Issuer:         ServiceAccountIssuer,
+		Subject:        ServiceAccountUsername(namespace, name),
+		Namespace:      namespace,
+		ServiceAccount: name,
+		IssuedAt:       now.Unix(),
+	}
+	if ttl > 0 {
+		claims.ExpiresAt = now.Add(ttl).Unix()
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: "RS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ServiceAccountAuthenticator verifies RS256 service account tokens against
+// a public key.
+type ServiceAccountAuthenticator struct {
+	key *rsa.PublicKey
+	now func() time.Time
+}
+
+func NewServiceAccountAuthenticator(key *rsa.PublicKey) *ServiceAccountAuthenticator {
+	return &ServiceAccountAuthenticator{key: key, now: time.Now}
+}
+
+func (a *ServiceAccountAuthenticator) AuthenticateRequest(r *http.Request) (*UserInfo, bool, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, false, nil
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		// Not a JWT, so not ours to judge
+		return nil, false, nil
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, false, nil
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, false, nil
+	}
+	if header.Alg != "RS256" {
+		return nil, false, fmt.Errorf("unsupported token algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, false, fmt.Errorf("malformed token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(a.key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, false, fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, false, fmt.Errorf("malformed token payload")
+	}
+	var claims serviceAccountClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, false, fmt.Errorf("malformed token claims: %v", err)
+	}
+	if claims.Issuer != ServiceAccountIssuer {
+		return nil, false, fmt.Errorf("unexpected token issuer %q", claims.Issuer)
+	}
+	if claims.ExpiresAt != 0 && a.now().Unix() >= claims.ExpiresAt {
+		return nil, false, fmt.Errorf("token has expired")
+	}
+	if claims.Namespace == "" || claims.ServiceAccount == "" ||
+		claims.Subject != ServiceAccountUsername(claims.Namespace, claims.ServiceAccount) {
+		return nil, false, fmt.Errorf("token subject does not match service account")
+	}
+
+	return &UserInfo{
+		Name:   claims.Subject,
+		Groups: []string{ServiceAccountsGroup, serviceAccountGroupPrefix + claims.Namespace},
+	}, true, nil
+}
+
+// LoadPrivateKey reads a PEM encoded RSA private key (PKCS#1 or PKCS#8).
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("%s does not contain an RSA key", path)
+			}
+			return rsaKey, nil
+		}
+	}
+	return nil, fmt.Errorf("no RSA private key found in %s", path)
+}
+
+// LoadPublicKey reads a PEM encoded RSA public key. A private key file is
+// accepted too, so the API server can share the signing key file.
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := key.(*rsa.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("%s does not contain an RSA key", path)
+			}
+			return rsaKey, nil
+		case "RSA PUBLIC KEY":
+			return x509.ParsePKCS1PublicKey(block.Bytes)
+		case "RSA PRIVATE KEY", "PRIVATE KEY":
+			private, err := LoadPrivateKey(path)
+			if err != nil {
+				return nil, err
+			}
+			return &private.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("no RSA public key found in %s", path)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TokenFileAuthenticator authenticates bearer tokens listed in a static CSV
+// file with one "token,user,uid,\"group1,group2\"" entry per line.
+type TokenFileAuthenticator struct {
+	tokens map[string]*UserInfo
+}
+
+func NewTokenFileAuthenticator(path string) (*TokenFileAuthenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file: %v", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	tokens := make(map[string]*UserInfo)
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token file: %v", err)
+		}
+		if len(record) < 2 || record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("token file line %d: need at least token and user", line)
+		}
+
+		user := &UserInfo{Name: record[1]}
+		if len(record) > 2 {
+			user.UID = record[2]
+		}
+		if len(record) > 3 && record[3] != "" {
+			for _, group := range strings.Split(record[3], ",") {
+				user.Groups = append(user.Groups, strings.TrimSpace(group))
+			}
+		}
+		if _, exists := tokens[record[0]]; exists {
+			return nil, fmt.Errorf("token file line %d: duplicate token", line)
+		}
+		tokens[record[0]] = user
+	}
+
+	return &TokenFileAuthenticator{tokens: tokens}, nil
+}
+
+func (a *TokenFileAuthenticator) AuthenticateRequest(r *http.Request) (*UserInfo, bool, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return nil, false, nil
+	}
+	user, found := a.tokens[token]
+	if !found {
+		// Might be a service account token, let the next authenticator try
+		return nil, false, nil
+	}
+	copied := *user
+	copied.Groups = append([]string(nil), user.Groups...)
+	return &copied, true, nil
+}
@@ -16,15 +16,38 @@ import (
 type ClientConfig struct {
 	Host string
 	Port string
+
+	// Credentials; a bearer token, a client certificate, or both
+	Token    string
+	CertFile string
+	KeyFile  string
+	// CAFile is the bundle used to verify the API server's certificate.
+	// Setting it or a client certificate switches the client to https. Node
+	// servers are then expected to serve https from the same CA too.
+	CAFile string
+	// Scheme forces http or https instead of choosing from the above
+	Scheme string
+
+	// Kubeconfig is the config file read when Host is empty, with
+	// DefaultConfigPath as the default; Context picks a context in it
+	// other than the current one.
+	Kubeconfig string
+	Context    string
 }
 
 type Client struct {
 	baseURL      string
 	config       ClientConfig
+	httpClient   *http.Client
 	assignedPods map[string]int // Track assigned NodePorts for pods
 }
 
-func NewClient(config ClientConfig) *Client {
+func NewClient(config ClientConfig) (*Client, error) {
+	if config.Host == "" {
+		if err := config.fromKubeconfig(); err != nil {
+			return nil, fmt.Errorf("failed to load the kubeconfig: %v", err)
+		}
+	}
 	if config.Host == "" {
 		config.Host = "localhost"
 	}
@@ -32,9 +55,23 @@ func NewClient(config ClientConfig) *Client {
 		config.Port = "8080"
 	}
 
+	scheme := config.Scheme
+	if scheme == "" {
+		scheme = "http"
+		if config.CAFile != "" || config.CertFile != "" {
+			scheme = "https"
+		}
+	}
+
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client credentials: %v", err)
+	}
+
 	c := &Client{
-		baseURL: fmt.Sprintf("http://%s:%s", config.Host, config.Port),
-		config:  config,
+		baseURL:    fmt.Sprintf("%s://%s:%s", scheme, config.Host, config.Port),
+		config:     config,
+		httpClient: httpClient,
 	}
 
 	// Load existing node port assignments
@@ -43,40 +80,56 @@ func NewClient(config ClientConfig) *Client {
 		c.assignedPods = make(map[string]int)
 	}
 
-	return c
+	return c, nil
 }
 
-func (c *Client) ListPods(namespace string) ([]models.Pod, error) {
-	url := c.baseURL + "/api/v1/pods"
-	if namespace != "" {
-		url = fmt.Sprintf("%s/api/v1/namespaces/%s/pods", c.baseURL, namespace)
+// readError returns the error message in a failed response, falling back to
+// its status.
+func readError(resp *http.Response) string {
+	var body struct {
+		Error string `json:"error"`
 	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+		return body.Error
 	}
-	defer resp.Body.Close()
+	return resp.Status
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list pods: %s", resp.Status)
-	}
+func (c *Client) ListPods(namespace string) ([]models.Pod, error) {
+	return c.ListPodsWithOptions(namespace, ListOptions{})
+}
+
+// ListPodsWithOptions lists pods in namespace, or in all namespaces when it
+// is empty, that match opts. Pages of opts.Limit are fetched until the list
+// is complete.
+func (c *Client) ListPodsWithOptions(namespace string, opts ListOptions) ([]models.Pod, error) {
+	return listAll[models.Pod](c, podsPath(namespace), opts, "pods")
+}
+
+// ListPodsPage fetches a single page of pods; pass the returned
+// Metadata.Continue in opts.Continue for the next one.
+func (c *Client) ListPodsPage(namespace string, opts ListOptions) (*models.PodList, error) {
+	return listPage[models.Pod](c, podsPath(namespace), opts, "pods")
+}
 
-	var pods []models.Pod
-	if err := json.NewDecoder(resp.Body).Decode(&pods); err != nil {
-		return nil, err
+func podsPath(namespace string) string {
+	if namespace == "" {
+		return "/api/v1/pods"
 	}
-	return pods, nil
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods", namespace)
 }
 
 func (c *Client) CreatePod(pod models.Pod) error {
-	url := c.baseURL + "/api/v1/pods"
+	if pod.Metadata.Namespace == "" {
+		pod.Metadata.Namespace = "default"
+	}
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/pods", c.baseURL, pod.Metadata.Namespace)
 	data, err := json.Marshal(pod)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(data))
 	if err != nil {
 		return err
 	}
@@ -91,7 +144,7 @@ func (c *Client) CreatePod(pod models.Pod) error {
 // Add GetNode method if not already present
 func (c *Client) GetNode(name string) (*models.Node, error) {
 	url := fmt.Sprintf("%s/api/v1/nodes/%s", c.baseURL, name)
-	resp, err := http.Get(url)
+	resp, err := c.httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get node: %v", err)
 	}
@@ -143,7 +196,7 @@ func (c *Client) DeletePod(namespace, name string) error {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %v", err)
 	}
@@ -190,13 +243,13 @@ func (c *Client) cleanupPodFromNodes(podName string) error {
 }
 
 func (c *Client) cleanupPodFromNode(podName string, node *models.Node) error {
-	nodeURL := fmt.Sprintf("http://%s:8081/pods/%s", node.IP, podName)
+	nodeURL := fmt.Sprintf("%s://%s:8081/pods/%s", c.nodeScheme(), node.IP, podName)
 	req, err := http.NewRequest(http.MethodDelete, nodeURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create node request: %v", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to contact node server: %v", err)
 	}
@@ -215,7 +268,7 @@ func (c *Client) GetPod(name string) (*models.Pod, error) {
 	url := fmt.Sprintf("%s/api/v1/pods/%s", c.baseURL, name)
 	fmt.Printf("🔍 Getting pod details from: %s\n", url)
 
-	resp, err := http.Get(url)
+	resp, err := c.httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod: %v", err)
 	}
@@ -250,7 +303,7 @@ func (c *Client) UpdatePod(pod models.Pod) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -324,13 +377,16 @@ func (c *Client) CreateService(service models.Service) error {
 
 	// Create the service
 	fmt.Printf("📦 Creating service '%s'...\n", service.Metadata.Name)
-	url := fmt.Sprintf("%s/api/v1/services", c.baseURL)
+	if service.Metadata.Namespace == "" {
+		service.Metadata.Namespace = "default"
+	}
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/services", c.baseURL, service.Metadata.Namespace)
 	data, err := json.Marshal(service)
 	if err != nil {
 		return fmt.Errorf("failed to marshal service: %v", err)
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(data))
 	if err != nil {
 		return fmt.Errorf("failed to create service: %v", err)
 	}
@@ -361,27 +417,17 @@ func (c *Client) CreateService(service models.Service) error {
 }
 
 func (c *Client) ListServices(namespace string) ([]models.Service, error) {
-	url := fmt.Sprintf("%s/api/v1/services", c.baseURL)
-	if namespace != "" {
-		url = fmt.Sprintf("%s/api/v1/namespaces/%s/services", c.baseURL, namespace)
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get services: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get services: %s", resp.Status)
-	}
+	return c.ListServicesWithOptions(namespace, ListOptions{})
+}
 
-	var services []models.Service
-	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
-		return nil, fmt.Errorf("failed to decode services: %v", err)
+// ListServicesWithOptions lists services in namespace, or in all namespaces
+// when it is empty, that match opts.
+func (c *Client) ListServicesWithOptions(namespace string, opts ListOptions) ([]models.Service, error) {
+	path := "/api/v1/services"
+	if namespace != "" {
+		path = fmt.Sprintf("/api/v1/namespaces/%s/services", namespace)
 	}
-
-	return services, nil
+	return listAll[models.Service](c, path, opts, "services")
 }
 
 func (c *Client) RegisterNode(node models.Node) error {
@@ -392,7 +438,7 @@ func (c *Client) RegisterNode(node models.Node) error {
 		return fmt.Errorf("failed to marshal node: %v", err)
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(data))
 	if err != nil {
 		return fmt.Errorf("failed to register node: %v", err)
 	}
@@ -418,7 +464,7 @@ func (c *Client) UpdateNodeStatus(nodeName string, status models.NodeStatus) err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -431,23 +477,12 @@ func (c *Client) UpdateNodeStatus(nodeName string, status models.NodeStatus) err
 }
 
 func (c *Client) ListNodes() ([]models.Node, error) {
-	// Fix the URL path to match the API server routes
-	url := c.baseURL + "/api/v1/nodes"
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to API server: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list nodes: %s", resp.Status)
-	}
+	return c.ListNodesWithOptions(ListOptions{})
+}
 
-	var nodes []models.Node
-	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
-	return nodes, nil
+// ListNodesWithOptions lists the nodes that match opts.
+func (c *Client) ListNodesWithOptions(opts ListOptions) ([]models.Node, error) {
+	return listAll[models.Node](c, "/api/v1/nodes", opts, "nodes")
 }
 
 func (c *Client) GetConfig() ClientConfig {
@@ -483,7 +518,7 @@ func (c *Client) UpdatePodStatus(pod *models.Pod) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %v", err)
 	}
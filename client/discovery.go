@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/openapi"
+)
+
+// getJSON decodes the response to a GET of path into v.
+func (c *Client) getJSON(path, what string, v interface{}) error {
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %v", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: %s", what, readError(resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", what, err)
+	}
+	return nil
+}
+
+// ServerResources returns the resources of every group version the API
+// server serves, core group first.
+func (c *Client) ServerResources() ([]models.APIResourceList, error) {
+	var core models.APIResourceList
+	if err := c.getJSON("/api/v1", "core resources", &core); err != nil {
+		return nil, err
+	}
+	lists := []models.APIResourceList{core}
+
+	var groups models.APIGroupList
+	if err := c.getJSON("/apis", "API groups", &groups); err != nil {
+		return nil, err
+	}
+	for _, group := range groups.Groups {
+		var list models.APIResourceList
+		gv := group.PreferredVersion.GroupVersion
+		if err := c.getJSON("/apis/"+gv, gv+" resources", &list); err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+// OpenAPI fetches the API server's OpenAPI v3 document.
+func (c *Client) OpenAPI() (*openapi.Document, error) {
+	var doc openapi.Document
+	if err := c.getJSON("/openapi/v3", "OpenAPI document", &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
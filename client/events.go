@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// errEventNotFound is returned by UpdateEvent when the event expired or was
+// deleted.
+var errEventNotFound = errors.New("event not found")
+
+// eventRequestTimeout bounds each event request, so an API server that
+// stops answering cannot hold up the recorder for good.
+const eventRequestTimeout = 10 * time.Second
+
+func eventsPath(namespace string) string {
+	if namespace == "" {
+		return "/api/v1/events"
+	}
+	return fmt.Sprintf("/api/v1/namespaces/%s/events", namespace)
+}
+
+// CreateEvent stores a new event. The server names it when
+// Metadata.Name is empty; the stored event is returned.
+func (c *Client) CreateEvent(event models.Event) (*models.Event, error) {
+	event.Metadata.Namespace = namespaceOrDefault(event.Metadata.Namespace)
+	return c.sendEvent(http.MethodPost, eventsPath(event.Metadata.Namespace), event, http.StatusCreated)
+}
+
+// UpdateEvent replaces an existing event, usually to bump its count.
+func (c *Client) UpdateEvent(event models.Event) (*models.Event, error) {
+	path := eventsPath(namespaceOrDefault(event.Metadata.Namespace)) + "/" + event.Metadata.Name
+	return c.sendEvent(http.MethodPut, path, event, http.StatusOK)
+}
+
+func (c *Client) sendEvent(method, path string, event models.Event, want int) (*models.Event, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), eventRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send event: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodPut {
+		return nil, errEventNotFound
+	}
+	if resp.StatusCode != want {
+		return nil, fmt.Errorf("failed to send event: %s", readError(resp))
+	}
+
+	var stored models.Event
+	if err := json.NewDecoder(resp.Body).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %v", err)
+	}
+	return &stored, nil
+}
+
+// ListEvents lists events in namespace, or in all namespaces when it is
+// empty, that match opts. Use a field selector such as
+// "involvedObject.kind=Pod,involvedObject.name=web" for one object's events.
+func (c *Client) ListEvents(namespace string, opts ListOptions) ([]models.Event, error) {
+	return listAll[models.Event](c, eventsPath(namespace), opts, "events")
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+)
+
+// Exec runs a command in a pod's container with streams, returning when it
+// exits: nil if it succeeded, a *wsstream.ExitError if it exited non-zero.
+// opts say which of the streams are used.
+func (c *Client) Exec(ctx context.Context, namespace, name string, opts models.PodExecOptions, streams wsstream.ExecStreams) error {
+	path := podsPath(namespaceOrDefault(namespace)) + "/" + name + "/exec"
+	conn, err := c.openStream(ctx, path, opts.Query(), wsstream.ExecProtocol)
+	if err != nil {
+		return fmt.Errorf("failed to exec in pod %q: %v", name, err)
+	}
+	return wsstream.StreamExec(conn, streams)
+}
+
+// openStream upgrades a GET of path to a WebSocket speaking protocol, with
+// the client's credentials.
+func (c *Client) openStream(ctx context.Context, path string, query url.Values, protocol string) (*wsstream.Conn, error) {
+	target := "ws" + strings.TrimPrefix(c.baseURL, "http") + path
+	if encoded := query.Encode(); encoded != "" {
+		target += "?" + encoded
+	}
+
+	var tlsConfig *tls.Config
+	if c.config.CAFile != "" || c.config.CertFile != "" {
+		var err error
+		if tlsConfig, err = pki.ClientConfig(c.config.CAFile, c.config.CertFile, c.config.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+	header := http.Header{}
+	if c.config.Token != "" {
+		header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	conn, resp, err := wsstream.Dial(ctx, target, tlsConfig, header, protocol)
+	if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return nil, errors.New(readError(resp))
+	}
+	return conn, err
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a kubeconfig style file naming the clusters the CLI can talk
+// to, the credentials it can use and contexts pairing the two.
+type Config struct {
+	APIVersion     string         `yaml:"apiVersion"`
+	Kind           string         `yaml:"kind"`
+	CurrentContext string         `yaml:"current-context"`
+	Clusters       []NamedCluster `yaml:"clusters"`
+	Users          []NamedUser    `yaml:"users"`
+	Contexts       []NamedContext `yaml:"contexts"`
+}
+
+type NamedCluster struct {
+	Name    string  `yaml:"name"`
+	Cluster Cluster `yaml:"cluster"`
+}
+
+// Cluster is an API server and the CA bundle that verifies it.
+type Cluster struct {
+	// Server is the URL of the API server, e.g. https://10.0.0.1:8443
+	Server               string `yaml:"server"`
+	CertificateAuthority string `yaml:"certificate-authority,omitempty"`
+}
+
+type NamedUser struct {
+	Name string   `yaml:"name"`
+	User AuthInfo `yaml:"user"`
+}
+
+// AuthInfo holds the credentials of a user: a bearer token, a client
+// certificate, or both.
+type AuthInfo struct {
+	Token             string `yaml:"token,omitempty"`
+	ClientCertificate string `yaml:"client-certificate,omitempty"`
+	ClientKey         string `yaml:"client-key,omitempty"`
+}
+
+type NamedContext struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Context pairs a cluster with a user and the namespace commands default to.
+type Context struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// DefaultConfigPath is the file named by MYKUBE_CONFIG, or ~/.mykube/config.
+func DefaultConfigPath() string {
+	if path := os.Getenv("MYKUBE_CONFIG"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".mykube", "config")
+	}
+	return filepath.Join(home, ".mykube", "config")
+}
+
+// LoadConfig reads the config file at path, or at DefaultConfigPath when
+// path is empty. A missing file is an empty config.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	config := &Config{APIVersion: "v1", Kind: "Config"}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Save writes the config to path, or to DefaultConfigPath when path is
+// empty. The file holds credentials, so only its owner may read it.
+func (c *Config) Save(path string) error {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Context returns the context called name, or the current context when name
+// is empty. It returns nil, and no error, when name is empty and no current
+// context is set.
+func (c *Config) Context(name string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+		if name == "" {
+			return nil, nil
+		}
+	}
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i].Context, nil
+		}
+	}
+	return nil, fmt.Errorf("context %q not found", name)
+}
+
+// Cluster returns the cluster called name.
+func (c *Config) Cluster(name string) (*Cluster, error) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name == name {
+			return &c.Clusters[i].Cluster, nil
+		}
+	}
+	return nil, fmt.Errorf("cluster %q not found", name)
+}
+
+// User returns the credentials of the user called name.
+func (c *Config) User(name string) (*AuthInfo, error) {
+	for i := range c.Users {
+		if c.Users[i].Name == name {
+			return &c.Users[i].User, nil
+		}
+	}
+	return nil, fmt.Errorf("user %q not found", name)
+}
+
+// SetContext adds the context called name, or replaces it.
+func (c *Config) SetContext(name string, context Context) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			c.Contexts[i].Context = context
+			return
+		}
+	}
+	c.Contexts = append(c.Contexts, NamedContext{Name: name, Context: context})
+}
+
+// SetCluster adds the cluster called name, or replaces it.
+func (c *Config) SetCluster(name string, cluster Cluster) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name == name {
+			c.Clusters[i].Cluster = cluster
+			return
+		}
+	}
+	c.Clusters = append(c.Clusters, NamedCluster{Name: name, Cluster: cluster})
+}
+
+// SetUser adds the user called name, or replaces it.
+func (c *Config) SetUser(name string, user AuthInfo) {
+	for i := range c.Users {
+		if c.Users[i].Name == name {
+			c.Users[i].User = user
+			return
+		}
+	}
+	c.Users = append(c.Users, NamedUser{Name: name, User: user})
+}
+
+// fromKubeconfig fills in the server and the credentials config does not
+// set from a context of its kubeconfig file: Context, or the current one.
+func (config *ClientConfig) fromKubeconfig() error {
+	kubeconfig, err := LoadConfig(config.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	context, err := kubeconfig.Context(config.Context)
+	if err != nil || context == nil {
+		return err
+	}
+
+	cluster, err := kubeconfig.Cluster(context.Cluster)
+	if err != nil {
+		return err
+	}
+	server, err := url.Parse(cluster.Server)
+	if err != nil || server.Host == "" {
+		return fmt.Errorf("cluster %q: server %q is not a URL such as https://10.0.0.1:8443", context.Cluster, cluster.Server)
+	}
+	if server.Scheme != "http" && server.Scheme != "https" {
+		return fmt.Errorf("cluster %q: unsupported scheme %q", context.Cluster, server.Scheme)
+	}
+	config.Scheme = server.Scheme
+	config.Host = server.Hostname()
+	if config.Port == "" {
+		config.Port = server.Port()
+	}
+	if config.Port == "" {
+		config.Port = "80"
+		if server.Scheme == "https" {
+			config.Port = "443"
+		}
+	}
+	if config.CAFile == "" {
+		config.CAFile = cluster.CertificateAuthority
+	}
+
+	if context.User == "" {
+		return nil
+	}
+	user, err := kubeconfig.User(context.User)
+	if err != nil {
+		return err
+	}
+	if config.Token == "" {
+		config.Token = user.Token
+	}
+	if config.CertFile == "" && config.KeyFile == "" {
+		config.CertFile, config.KeyFile = user.ClientCertificate, user.ClientKey
+	}
+	return nil
+}
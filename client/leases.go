@@ -0,0 +1,72 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+func leasePath(namespace, name string) string {
+	path := fmt.Sprintf("/apis/coordination.k8s.io/v1/namespaces/%s/leases", namespaceOrDefault(namespace))
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
+
+// GetLease fetches a lease. Its error wraps ErrNotFound when the lease does
+// not exist.
+func (c *Client) GetLease(ctx context.Context, namespace, name string) (*models.Lease, error) {
+	return c.sendLease(ctx, http.MethodGet, leasePath(namespace, name), nil, http.StatusOK)
+}
+
+// CreateLease stores a new lease and returns it as stored. Its error
+// matches ErrConflict when the lease exists.
+func (c *Client) CreateLease(ctx context.Context, lease models.Lease) (*models.Lease, error) {
+	return c.sendLease(ctx, http.MethodPost, leasePath(lease.Metadata.Namespace, ""), &lease, http.StatusCreated)
+}
+
+// UpdateLease replaces a lease and returns it as stored. Its error matches
+// ErrConflict when the lease changed since its resourceVersion was read.
+func (c *Client) UpdateLease(ctx context.Context, lease models.Lease) (*models.Lease, error) {
+	return c.sendLease(ctx, http.MethodPut, leasePath(lease.Metadata.Namespace, lease.Metadata.Name), &lease, http.StatusOK)
+}
+
+func (c *Client) sendLease(ctx context.Context, method, path string, lease *models.Lease, want int) (*models.Lease, error) {
+	var body bytes.Buffer
+	if lease != nil {
+		if err := json.NewEncoder(&body).Encode(lease); err != nil {
+			return nil, fmt.Errorf("failed to marshal lease: %v", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("lease request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("lease %s: %w", path, ErrNotFound)
+	case resp.StatusCode == http.StatusConflict:
+		return nil, conflictError(readError(resp))
+	case resp.StatusCode != want:
+		return nil, fmt.Errorf("lease request failed: %s", readError(resp))
+	}
+
+	var stored models.Lease
+	if err := json.NewDecoder(resp.Body).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode lease: %v", err)
+	}
+	return &stored, nil
+}
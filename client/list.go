@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// ListOptions narrows a list request with label and field selectors, e.g.
+// LabelSelector "app=web,tier in (frontend)" or FieldSelector
+// "spec.nodeName=worker1", and pages through it with Limit and Continue.
+type ListOptions struct {
+	LabelSelector string
+	FieldSelector string
+	Limit         int64  // items per page, 0 for everything at once
+	Continue      string // token from the previous page's metadata.continue
+}
+
+// query renders the options as a query string, including the leading "?".
+func (o ListOptions) query() string {
+	values := url.Values{}
+	if o.LabelSelector != "" {
+		values.Set("labelSelector", o.LabelSelector)
+	}
+	if o.FieldSelector != "" {
+		values.Set("fieldSelector", o.FieldSelector)
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.FormatInt(o.Limit, 10))
+	}
+	if o.Continue != "" {
+		values.Set("continue", o.Continue)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// listPage fetches one page of the list at path. what names the resource in
+// errors.
+func listPage[T any](c *Client, path string, opts ListOptions, what string) (*models.List[T], error) {
+	resp, err := c.httpClient.Get(c.baseURL + path + opts.query())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list %s: %s", what, readError(resp))
+	}
+
+	var list models.List[T]
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", what, err)
+	}
+	return &list, nil
+}
+
+// listAll follows continue tokens until every page has been fetched.
+func listAll[T any](c *Client, path string, opts ListOptions, what string) ([]T, error) {
+	var items []T
+	for {
+		page, err := listPage[T](c, path, opts, what)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if page.Metadata.Continue == "" {
+			return items, nil
+		}
+		opts.Continue = page.Metadata.Continue
+	}
+}
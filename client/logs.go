@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// PodLogs opens the log of a pod's container. The stream ends with the log,
+// or for a followed log when the container stops or ctx is cancelled; the
+// caller closes it.
+func (c *Client) PodLogs(ctx context.Context, namespace, name string, opts models.PodLogOptions) (io.ReadCloser, error) {
+	url := c.baseURL + podsPath(namespaceOrDefault(namespace)) + "/" + name + "/log"
+	if query := opts.Query().Encode(); query != "" {
+		url += "?" + query
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the log of pod %q: %v", name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to get the log of pod %q: %s", name, readError(resp))
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// Patch content types understood by the API server.
+const (
+	MergePatchType          = "application/merge-patch+json"
+	JSONPatchType           = "application/json-patch+json"
+	StrategicMergePatchType = "application/strategic-merge-patch+json"
+)
+
+// PatchOptions control Patch.
+type PatchOptions struct {
+	DryRun bool // validate on the server without persisting
+}
+
+// Patch applies data, a patch of the given content type, to the named
+// object and returns the patched object as JSON.
+func (c *Client) Patch(res Resource, namespace, name, patchType string, data []byte, opts PatchOptions) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPatch, c.baseURL+res.Path(namespace, name)+dryRunQuery(opts.DryRun), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", patchType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s %q: %v", res.Singular, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("failed to patch %s %q: %w", res.Singular, name, conflictError(readError(resp)))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to patch %s %q: %s", res.Singular, name, readError(resp))
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// ApplyPatchType is the content type of server-side apply requests.
+const ApplyPatchType = "application/apply-patch+yaml"
+
+// ApplyOptions control a server-side apply.
+type ApplyOptions struct {
+	FieldManager string
+	Force        bool // take over fields owned by other managers
+	DryRun       bool // validate on the server without persisting
+}
+
+// Apply sends config, a YAML or JSON configuration, as a server-side apply
+// of the named object, creating it if it does not exist. It returns the
+// resulting object as JSON and whether it was created.
+func (c *Client) Apply(res Resource, namespace, name string, config []byte, opts ApplyOptions) ([]byte, bool, error) {
+	query := url.Values{}
+	query.Set("fieldManager", opts.FieldManager)
+	if opts.Force {
+		query.Set("force", "true")
+	}
+	if opts.DryRun {
+		query.Set("dryRun", "All")
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, c.baseURL+res.Path(namespace, name)+"?"+query.Encode(), bytes.NewReader(config))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", ApplyPatchType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to apply %s %q: %v", res.Singular, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, false, fmt.Errorf("failed to apply %s %q: %s", res.Singular, name, readError(resp))
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.StatusCode == http.StatusCreated, err
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+)
+
+// PortForward relays a connection to a port of a pod, returning when both
+// ends are done with it. stream is closed on return.
+func (c *Client) PortForward(ctx context.Context, namespace, name string, port int32, stream net.Conn) error {
+	path := podsPath(namespaceOrDefault(namespace)) + "/" + name + "/portforward"
+	opts := models.PodPortForwardOptions{Port: port}
+	conn, err := c.openStream(ctx, path, opts.Query(), wsstream.PortForwardProtocol)
+	if err != nil {
+		stream.Close()
+		return fmt.Errorf("failed to forward port %d of pod %q: %v", port, name, err)
+	}
+	return wsstream.Tunnel(conn, stream)
+}
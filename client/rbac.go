@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+const rbacPath = "/apis/rbac.authorization.k8s.io/v1"
+
+// createObject POSTs obj as JSON to path and expects 201 Created.
+func (c *Client) createObject(path, kind string, obj interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", kind, err)
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+path, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", kind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create %s: %s - %s", kind, resp.Status, string(body))
+	}
+	return nil
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+func (c *Client) CreateRole(role models.Role) error {
+	path := fmt.Sprintf("%s/namespaces/%s/roles", rbacPath, namespaceOrDefault(role.Metadata.Namespace))
+	return c.createObject(path, "role", role)
+}
+
+func (c *Client) CreateClusterRole(role models.ClusterRole) error {
+	return c.createObject(rbacPath+"/clusterroles", "cluster role", role)
+}
+
+func (c *Client) CreateRoleBinding(binding models.RoleBinding) error {
+	path := fmt.Sprintf("%s/namespaces/%s/rolebindings", rbacPath, namespaceOrDefault(binding.Metadata.Namespace))
+	return c.createObject(path, "role binding", binding)
+}
+
+func (c *Client) CreateClusterRoleBinding(binding models.ClusterRoleBinding) error {
+	return c.createObject(rbacPath+"/clusterrolebindings", "cluster role binding", binding)
+}
+
+// CheckAccess asks the API server whether the client's user may perform the
+// described action.
+func (c *Client) CheckAccess(spec models.SelfSubjectAccessReviewSpec) (*models.SubjectAccessReviewStatus, error) {
+	data, err := json.Marshal(models.SelfSubjectAccessReview{Spec: spec})
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.baseURL + "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to contact API server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("access review failed: %s - %s", resp.Status, string(body))
+	}
+
+	var review models.SelfSubjectAccessReview
+	if err := json.NewDecoder(resp.Body).Decode(&review); err != nil {
+		return nil, fmt.Errorf("failed to decode access review: %v", err)
+	}
+	return &review.Status, nil
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// Limits of the event recorder. Each involved object may record a burst of
+// eventBurst events, then one more every eventRefill, so a pod stuck in a
+// failure loop cannot flood the store. Up to eventQueueSize events wait to
+// be sent; more are dropped rather than holding up the caller.
+const (
+	eventBurst     = 25
+	eventRefill    = 5 * time.Minute
+	eventCacheSize = 4096
+	eventQueueSize = 1000
+)
+
+// EventRecorder records events for one component. Repeats of an event it
+// recorded before, same object, type, reason and message, update the
+// stored event's count and last timestamp rather than creating a new
+// one, and events beyond an object's rate limit are dropped. Recording
+// never blocks or fails the caller: events are sent in the background and
+// problems are logged.
+type EventRecorder struct {
+	client *Client
+	source models.EventSource
+	queue  chan pendingEvent
+
+	// Only used by the goroutine draining queue
+	seen    map[string]models.Event // last stored copy, by object, type, reason and message
+	order   []string                // keys of seen, oldest first
+	buckets map[string]*tokenBucket // by involved object
+}
+
+// pendingEvent is an event waiting in the recorder's queue.
+type pendingEvent struct {
+	object                     models.ObjectReference
+	eventType, reason, message string
+	time                       time.Time
+}
+
+// NewEventRecorder returns a recorder whose events name component, and for
+// node components host, as their source. It sends events from a goroutine
+// that lives as long as the process.
+func NewEventRecorder(c *Client, component, host string) *EventRecorder {
+	r := &EventRecorder{
+		client:  c,
+		source:  models.EventSource{Component: component, Host: host},
+		queue:   make(chan pendingEvent, eventQueueSize),
+		seen:    make(map[string]models.Event),
+		buckets: make(map[string]*tokenBucket),
+	}
+	go func() {
+		for e := range r.queue {
+			r.record(e.object, e.eventType, e.reason, e.message, e.time)
+		}
+	}()
+	return r
+}
+
+// Event records that reason happened to object. eventType is
+// models.EventTypeNormal or models.EventTypeWarning.
+func (r *EventRecorder) Event(object models.ObjectReference, eventType, reason, message string) {
+	if r == nil {
+		return
+	}
+	select {
+	case r.queue <- pendingEvent{object, eventType, reason, message, time.Now()}:
+	default:
+		fmt.Printf("⚠️ Dropping event %s for %s %s: too many events waiting to be sent\n", reason, object.Kind, object.Name)
+	}
+}
+
+// record sends an event that happened at now, deduplicated and rate
+// limited.
+func (r *EventRecorder) record(object models.ObjectReference, eventType, reason, message string, now time.Time) {
+	objectKey := fmt.Sprintf("%s/%s/%s", object.Kind, object.Namespace, object.Name)
+	bucket, ok := r.buckets[objectKey]
+	if !ok {
+		r.pruneBuckets(now)
+		bucket = &tokenBucket{tokens: eventBurst, last: now}
+		r.buckets[objectKey] = bucket
+	}
+	if !bucket.take(now) {
+		fmt.Printf("⚠️ Dropping event %s for %s %s: rate limited\n", reason, object.Kind, object.Name)
+		return
+	}
+
+	key := objectKey + "/" + eventType + "/" + reason + "/" + message
+	if previous, ok := r.seen[key]; ok {
+		previous.Count++
+		previous.LastTimestamp = now
+		stored, err := r.client.UpdateEvent(previous)
+		if errors.Is(err, errEventNotFound) {
+			// Expired since; start a new event that keeps the count
+			previous.Metadata = models.Metadata{Namespace: previous.Metadata.Namespace}
+			stored, err = r.client.CreateEvent(previous)
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Failed to record event %s for %s %s: %v\n", reason, object.Kind, object.Name, err)
+			return
+		}
+		r.seen[key] = *stored
+		return
+	}
+
+	namespace := object.Namespace
+	if namespace == "" {
+		// Cluster scoped objects such as nodes
+		namespace = "default"
+	}
+	stored, err := r.client.CreateEvent(models.Event{
+		APIVersion:     "v1",
+		Kind:           "Event",
+		Metadata:       models.Metadata{Namespace: namespace},
+		InvolvedObject: object,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source:         r.source,
+	})
+	if err != nil {
+		fmt.Printf("⚠️ Failed to record event %s for %s %s: %v\n", reason, object.Kind, object.Name, err)
+		return
+	}
+	r.remember(key, *stored)
+}
+
+// Eventf is Event with a formatted message.
+func (r *EventRecorder) Eventf(object models.ObjectReference, eventType, reason, format string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(format, args...))
+}
+
+// remember caches an event for deduplication, forgetting the oldest one
+// when the cache is full.
+func (r *EventRecorder) remember(key string, event models.Event) {
+	if len(r.order) >= eventCacheSize {
+		delete(r.seen, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.seen[key] = event
+	r.order = append(r.order, key)
+}
+
+// pruneBuckets drops buckets that have refilled, which behave like new
+// ones, once there are too many to keep.
+func (r *EventRecorder) pruneBuckets(now time.Time) {
+	if len(r.buckets) < eventCacheSize {
+		return
+	}
+	for key, bucket := range r.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= eventBurst {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// tokenBucket allows eventBurst events at once and refills one token every
+// eventRefill.
+type tokenBucket struct {
+	tokens int
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if gained := int(now.Sub(b.last) / eventRefill); gained > 0 {
+		b.tokens += gained
+		if b.tokens > eventBurst {
+			b.tokens = eventBurst
+		}
+		b.last = b.last.Add(time.Duration(gained) * eventRefill)
+	}
+	if b.tokens == eventBurst {
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.refill(now)
+	if b.tokens == 0 {
+		return false
+	}
+	b.tokens--
+	return true
+}
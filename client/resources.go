@@ -0,0 +1,205 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Resource describes an API resource the CLI can address by name.
+type Resource struct {
+	Name       string // plural, as used in URLs
+	Singular   string
+	Kind       string
+	Group      string // "" for the core /api/v1 group
+	Namespaced bool
+	ShortNames []string
+}
+
+// Resources is the registry of resources the CLI knows, the same the API
+// server lists in discovery.
+var Resources = []Resource{
+	{Name: "pods", Singular: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+	{Name: "services", Singular: "service", Kind: "Service", Namespaced: true, ShortNames: []string{"svc"}},
+	{Name: "nodes", Singular: "node", Kind: "Node", ShortNames: []string{"no"}},
+	{Name: "namespaces", Singular: "namespace", Kind: "Namespace", ShortNames: []string{"ns"}},
+	{Name: "events", Singular: "event", Kind: "Event", Namespaced: true, ShortNames: []string{"ev"}},
+	{Name: "deployments", Singular: "deployment", Kind: "Deployment", Group: "apps", Namespaced: true, ShortNames: []string{"deploy"}},
+	{Name: "replicasets", Singular: "replicaset", Kind: "ReplicaSet", Group: "apps", Namespaced: true, ShortNames: []string{"rs"}},
+	{Name: "roles", Singular: "role", Kind: "Role", Group: "rbac.authorization.k8s.io", Namespaced: true},
+	{Name: "rolebindings", Singular: "rolebinding", Kind: "RoleBinding", Group: "rbac.authorization.k8s.io", Namespaced: true},
+	{Name: "clusterroles", Singular: "clusterrole", Kind: "ClusterRole", Group: "rbac.authorization.k8s.io"},
+	{Name: "clusterrolebindings", Singular: "clusterrolebinding", Kind: "ClusterRoleBinding", Group: "rbac.authorization.k8s.io"},
+	{Name: "leases", Singular: "lease", Kind: "Lease", Group: "coordination.k8s.io", Namespaced: true},
+}
+
+// LookupResource finds a resource by its plural, singular, kind or short
+// name, ignoring case. The name may be qualified by the group, e.g.
+// roles.rbac.authorization.k8s.io.
+func LookupResource(name string) (Resource, bool) {
+	name = strings.ToLower(name)
+	for _, r := range Resources {
+		if r.matches(name) {
+			return r, true
+		}
+		if r.Group != "" && strings.HasSuffix(name, "."+r.Group) && r.matches(strings.TrimSuffix(name, "."+r.Group)) {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+func (r Resource) matches(name string) bool {
+	if name == r.Name || name == r.Singular || name == strings.ToLower(r.Kind) {
+		return true
+	}
+	for _, short := range r.ShortNames {
+		if name == short {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupKind finds the resource of an object's apiVersion and kind, e.g.
+// apps/v1 and Deployment. An empty apiVersion matches any group.
+func LookupKind(apiVersion, kind string) (Resource, bool) {
+	group := ""
+	if i := strings.Index(apiVersion, "/"); i >= 0 {
+		group = apiVersion[:i]
+	}
+	for _, r := range Resources {
+		if r.Kind == kind && (apiVersion == "" || r.Group == group) {
+			return r, true
+		}
+	}
+	return Resource{}, false
+}
+
+// Path returns the URL path of the named object, or of the collection when
+// name is empty. Namespaced resources default to the default namespace.
+func (r Resource) Path(namespace, name string) string {
+	path := "/api/v1"
+	if r.Group != "" {
+		path = "/apis/" + r.Group + "/v1"
+	}
+	if r.Namespaced {
+		path += "/namespaces/" + namespaceOrDefault(namespace)
+	}
+	path += "/" + r.Name
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
+
+// ListPath returns the URL path of the collection in namespace, or across
+// all namespaces when it is empty.
+func (r Resource) ListPath(namespace string) string {
+	if r.Namespaced && namespace == "" {
+		r.Namespaced = false
+	}
+	return r.Path(namespace, "")
+}
+
+func (r Resource) String() string {
+	if r.Group == "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s.%s", r.Name, r.Group)
+}
+
+// ErrNotFound is wrapped by the errors of Get when the object does not
+// exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is matched by the errors of writes the API server rejected
+// because the object changed since it was read.
+var ErrConflict = errors.New("conflict")
+
+// conflictError carries the API server's message for a conflict.
+type conflictError string
+
+func (e conflictError) Error() string        { return string(e) }
+func (e conflictError) Is(target error) bool { return target == ErrConflict }
+
+// Get fetches the named object into out, which points to its model type.
+func (c *Client) Get(res Resource, namespace, name string, out interface{}) error {
+	resp, err := c.httpClient.Get(c.baseURL + res.Path(namespace, name))
+	if err != nil {
+		return fmt.Errorf("failed to get %s %q: %v", res.Singular, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("failed to get %s %q: %w", res.Singular, name, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s %q: %s", res.Singular, name, readError(resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", res.Singular, err)
+	}
+	return nil
+}
+
+// List fetches the objects of a resource in namespace, or in all
+// namespaces when it is empty, undecoded.
+func (c *Client) List(res Resource, namespace string, opts ListOptions) ([]json.RawMessage, error) {
+	return listAll[json.RawMessage](c, res.ListPath(namespace), opts, res.Name)
+}
+
+// CreateOptions control Create.
+type CreateOptions struct {
+	DryRun bool // validate on the server without persisting
+}
+
+// Create creates an object from data, its JSON, and returns the created
+// object as JSON.
+func (c *Client) Create(res Resource, namespace string, data []byte, opts CreateOptions) ([]byte, error) {
+	resp, err := c.httpClient.Post(c.baseURL+res.Path(namespace, "")+dryRunQuery(opts.DryRun), "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", res.Singular, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to create %s: %s", res.Singular, readError(resp))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// DeleteOptions control Delete.
+type DeleteOptions struct {
+	DryRun bool // check on the server that the object exists, without deleting it
+}
+
+// Delete deletes the named object.
+func (c *Client) Delete(res Resource, namespace, name string, opts DeleteOptions) error {
+	req, err := http.NewRequest(http.MethodDelete, c.baseURL+res.Path(namespace, name)+dryRunQuery(opts.DryRun), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %q: %v", res.Singular, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete %s %q: %s", res.Singular, name, readError(resp))
+	}
+	return nil
+}
+
+// dryRunQuery is the query string of a write request that is a dry run.
+func dryRunQuery(dryRun bool) string {
+	if dryRun {
+		return "?dryRun=All"
+	}
+	return ""
+}
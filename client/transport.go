@@ -0,0 +1,114 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
+)
+
+// bearerTransport adds the configured token to every request.
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// newHTTPClient builds an http.Client that presents the credentials in
+// config and trusts its CA bundle.
+func newHTTPClient(config ClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.CAFile != "" || config.CertFile != "" {
+		tlsConfig, err := pki.ClientConfig(config.CAFile, config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var rt http.RoundTripper = transport
+	if config.Token != "" {
+		rt = &bearerTransport{token: config.Token, base: transport}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// nodeScheme is the scheme node servers are reached on. A client set up to
+// verify a cluster CA expects node servers to be secured the same way.
+func (c *Client) nodeScheme() string {
+	if c.config.CAFile != "" {
+		return "https"
+	}
+	return "http"
+}
+
+// Ping checks that the API server is up and healthy, using the client's
+// transport settings.
+func (c *Client) Ping(timeout time.Duration) error {
+	return c.probe("/healthz", timeout)
+}
+
+// Ready checks that the API server is ready to serve requests: storage is
+// reachable and its startup work is done.
+func (c *Client) Ready(timeout time.Duration) error {
+	return c.probe("/readyz", timeout)
+}
+
+// probe GETs a health endpoint and returns the failed checks it lists, if
+// any.
+func (c *Client) probe(path string, timeout time.Duration) error {
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	resp, err := httpClient.Get(c.baseURL + path + "?verbose")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var failed []string
+	for _, line := range strings.Split(string(body), "\n") {
+		if strings.HasPrefix(line, "[-]") {
+			failed = append(failed, strings.TrimPrefix(line, "[-]"))
+		}
+	}
+	if len(failed) == 0 {
+		return fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	return fmt.Errorf("%s: %s", path, strings.Join(failed, "; "))
+}
+
+// WhoAmI asks the API server which user the client's credentials map to.
+func (c *Client) WhoAmI() (*auth.UserInfo, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/whoami")
+	if err != nil {
+		return nil, fmt.Errorf("failed to contact API server: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get user info: %s", resp.Status)
+	}
+
+	var user auth.UserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user info: %v", err)
+	}
+	return &user, nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiResourcesNamespaced bool
+	apiResourcesGroup      string
+	apiResourcesOutput     string
+)
+
+var apiResourcesCmd = &cobra.Command{
+	Use:   "api-resources",
+	Short: "List the resources the API server serves",
+	Example: `  mykube api-resources
+  mykube api-resources --namespaced=false
+  mykube api-resources --api-group rbac.authorization.k8s.io -o wide`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if apiResourcesOutput != "" && apiResourcesOutput != "wide" && apiResourcesOutput != "name" {
+			fmt.Printf("❌ --output must be wide or name\n")
+			return
+		}
+
+		lists, err := getClient().ServerResources()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		switch apiResourcesOutput {
+		case "":
+			fmt.Fprintln(w, "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND")
+		case "wide":
+			fmt.Fprintln(w, "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND\tVERBS")
+		}
+		for _, list := range lists {
+			group := ""
+			if i := strings.Index(list.GroupVersion, "/"); i >= 0 {
+				group = list.GroupVersion[:i]
+			}
+			if cmd.Flags().Changed("api-group") && group != apiResourcesGroup {
+				continue
+			}
+
+			resources := list.Resources
+			sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+			for _, r := range resources {
+				// Subresources are not listed
+				if strings.Contains(r.Name, "/") {
+					continue
+				}
+				if cmd.Flags().Changed("namespaced") && r.Namespaced != apiResourcesNamespaced {
+					continue
+				}
+
+				switch apiResourcesOutput {
+				case "name":
+					if group == "" {
+						fmt.Fprintln(w, r.Name)
+					} else {
+						fmt.Fprintf(w, "%s.%s\n", r.Name, group)
+					}
+				case "wide":
+					fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n", r.Name, strings.Join(r.ShortNames, ","),
+						list.GroupVersion, r.Namespaced, r.Kind, strings.Join(r.Verbs, ","))
+				default:
+					fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.Name, strings.Join(r.ShortNames, ","),
+						list.GroupVersion, r.Namespaced, r.Kind)
+				}
+			}
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	apiResourcesCmd.Flags().BoolVar(&apiResourcesNamespaced, "namespaced", true, "Only list namespaced resources, or with =false only cluster scoped ones")
+	apiResourcesCmd.Flags().StringVar(&apiResourcesGroup, "api-group", "", "Only list resources of this group; \"\" is the core group")
+	apiResourcesCmd.Flags().StringVarP(&apiResourcesOutput, "output", "o", "", "Output format: wide or name")
+	rootCmd.AddCommand(apiResourcesCmd)
+}
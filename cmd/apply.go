@@ -1,75 +1,215 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"time"
+	"reflect"
+	"strings"
 
-	"github.com/google/uuid"
 	"github.com/selimhanmrl/Own-Kubernetes/client"
-	"github.com/selimhanmrl/Own-Kubernetes/models"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
-var file string
+var (
+	files          []string
+	recursive      bool
+	fieldManager   string
+	forceConflicts bool
+)
 
 var applyCmd = &cobra.Command{
-	Use:   "apply",
-	Short: "Apply a YAML resource definition",
+	Use:   "apply -f FILENAME",
+	Short: "Apply resource definitions from files, directories, stdin or URLs",
+	Long: `Apply resource definitions with server-side apply.
+
+Each object is created if it does not exist and updated to match the file if
+it does, so applying the same file twice changes nothing. Fields set by the
+previous apply but missing from the file are removed. Fields another manager
+set to a different value are reported as conflicts unless --force-conflicts
+is given.
+
+Files may hold several YAML documents separated by ---, or JSON. -f takes
+files, directories (their .yaml, .yml and .json files, and those of their
+subdirectories with -R), - for stdin and http(s):// or file:// URLs. Objects
+are applied in dependency order: namespaces, nodes, RBAC, services, then
+workloads. Nodes have no managed fields and are merged into instead.
+
+--dry-run=client only reports whether each object would be created or
+configured; --dry-run=server has the API server validate the apply and
+report its outcome without persisting anything.`,
+	Example: `  mykube apply -f pod.yaml
+  mykube apply -f manifests/ -R
+  cat web.yaml | mykube apply -f -`,
 	Run: func(cmd *cobra.Command, args []string) {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			fmt.Printf("❌ Error reading file: %v\n", err)
+		if err := checkDryRun(); err != nil {
+			fmt.Printf("❌ %v\n", err)
 			return
 		}
-
-		// First, unmarshal to get the resource kind
-		var resource struct {
-			Kind string `yaml:"kind"`
-		}
-		if err := yaml.Unmarshal(data, &resource); err != nil {
-			fmt.Printf("❌ Error parsing YAML: %v\n", err)
-			return
+		flagNamespace := ""
+		if cmd.Flags().Changed("namespace") {
+			flagNamespace = namespace
 		}
 
-		// Create client
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
-
-		switch resource.Kind {
-		case "Pod":
-			var pod models.Pod
-			if err := yaml.Unmarshal(data, &pod); err != nil {
-				fmt.Printf("❌ Error parsing Pod YAML: %v\n", err)
-				return
+		var manifests []manifest
+		failed := 0
+		for _, path := range files {
+			found, err := readManifests(path, recursive)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed++
+				continue
 			}
-			originalName := pod.Metadata.Name
-			// Set initial pod status
-			pod.Metadata.UID = uuid.New().String()
-			pod.Status = models.PodStatus{
-				Phase:     "Pending",
-				StartTime: time.Now().Format(time.RFC3339),
+			manifests = append(manifests, found...)
+		}
+		sortManifests(manifests)
+
+		c := getClient()
+		counts := map[string]int{}
+		for _, m := range manifests {
+			ns, err := manifestNamespace(m, flagNamespace)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed++
+				continue
 			}
-			pod.Metadata.Name = fmt.Sprintf("%s-%s-%s", originalName, pod.Metadata.UID[:4], pod.Metadata.UID[4:8])
-			if err := c.CreatePod(pod); err != nil {
-				fmt.Printf("❌ Error creating pod: %v\n", err)
-				return
+			result, err := applyManifest(c, m, ns)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed++
+				continue
 			}
-			fmt.Printf("✅ Pod '%s' created successfully\n", pod.Metadata.Name)
+			counts[result]++
+			fmt.Printf("✅ %s/%s %s%s\n", resourcePrefix(m.res), m.name, result, dryRunSuffix())
+		}
 
-			// Run scheduler immediately after pod creation
-			//scheduler := exec.Command("go", "run", ".", "scheduler")
-			//scheduler.Run()
-		default:
-			fmt.Printf("❌ Unsupported resource kind: %s\n", resource.Kind)
+		if len(manifests) == 0 && failed == 0 {
+			fmt.Println("No objects found to apply.")
+			return
 		}
+		var summary []string
+		for _, result := range []string{"created", "configured", "unchanged"} {
+			if counts[result] > 0 {
+				summary = append(summary, fmt.Sprintf("%d %s", counts[result], result))
+			}
+		}
+		if failed > 0 {
+			summary = append(summary, fmt.Sprintf("%d failed", failed))
+		}
+		fmt.Println(strings.Join(summary, ", ") + dryRunSuffix())
 	},
 }
 
+// applyManifest applies one object and returns whether it was created,
+// configured or unchanged. A client dry run only looks up whether the
+// object exists.
+func applyManifest(c *client.Client, m manifest, namespace string) (string, error) {
+	if m.res.Kind == "Node" {
+		return applyNode(c, m)
+	}
+
+	var live json.RawMessage
+	exists := true
+	if err := c.Get(m.res, namespace, m.name, &live); errors.Is(err, client.ErrNotFound) {
+		exists = false
+	} else if err != nil {
+		return "", err
+	}
+	if dryRun == "client" {
+		if exists {
+			return "configured", nil
+		}
+		return "created", nil
+	}
+
+	applied, created, err := c.Apply(m.res, namespace, m.name, m.data, client.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        forceConflicts,
+		DryRun:       dryRun == "server",
+	})
+	if err != nil {
+		return "", err
+	}
+	if created {
+		return "created", nil
+	}
+	if sameObject(live, applied) {
+		return "unchanged", nil
+	}
+	return "configured", nil
+}
+
+// applyNode registers a node from a file, or merges the file into the node
+// when it exists: nodes have no metadata to record managed fields in, so
+// the API server does not take apply patches for them.
+func applyNode(c *client.Client, m manifest) (string, error) {
+	data, err := nodeJSON(m)
+	if err != nil {
+		return "", err
+	}
+
+	var live json.RawMessage
+	if err := c.Get(m.res, "", m.name, &live); errors.Is(err, client.ErrNotFound) {
+		if dryRun != "client" {
+			if _, err := c.Create(m.res, "", data, client.CreateOptions{DryRun: dryRun == "server"}); err != nil {
+				return "", err
+			}
+		}
+		return "created", nil
+	} else if err != nil {
+		return "", err
+	}
+	if dryRun == "client" {
+		return "configured", nil
+	}
+
+	patched, err := c.Patch(m.res, "", m.name, client.MergePatchType, data, client.PatchOptions{DryRun: dryRun == "server"})
+	if err != nil {
+		return "", err
+	}
+	if sameObject(live, patched) {
+		return "unchanged", nil
+	}
+	return "configured", nil
+}
+
+// nodeJSON returns a node from a file as the API server stores it, without
+// the apiVersion and kind nodes do not have.
+func nodeJSON(m manifest) ([]byte, error) {
+	data, err := m.json()
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("%s: %v", m.source, err)
+	}
+	delete(object, "apiVersion")
+	delete(object, "kind")
+	return json.Marshal(object)
+}
+
+// sameObject reports whether two versions of an object are the same apart
+// from their managed fields, which record the time of every apply.
+func sameObject(a, b []byte) bool {
+	var x, y map[string]interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	for _, obj := range []map[string]interface{}{x, y} {
+		if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+			delete(meta, "managedFields")
+		}
+	}
+	return reflect.DeepEqual(x, y)
+}
+
 func init() {
-	applyCmd.Flags().StringVarP(&file, "filename", "f", "", "YAML file containing the resource definition")
+	applyCmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Files, directories, URLs or - for stdin containing the resource definitions")
+	applyCmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Read the directories given with -f recursively")
+	applyCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the resources, if the files do not set one")
+	applyCmd.Flags().StringVar(&fieldManager, "field-manager", "mykube", "Name of the manager that owns the applied fields")
+	applyCmd.Flags().BoolVar(&forceConflicts, "force-conflicts", false, "Take over fields other managers set to different values")
+	addDryRunFlag(applyCmd)
 	applyCmd.MarkFlagRequired("filename")
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	canISubresource string
+	canIQuiet       bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect authentication and authorization",
+}
+
+var canICmd = &cobra.Command{
+	Use:   "can-i VERB [RESOURCE[/NAME] | NONRESOURCEURL]",
+	Short: "Check whether an action is allowed",
+	Long: `Check whether the current user may perform an action, e.g.
+
+    mykube auth can-i create pods -n team-a
+    mykube auth can-i update nodes/worker1 --subresource status
+    mykube auth can-i get /api/v1/whoami`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		verb, target := args[0], args[1]
+
+		var spec models.SelfSubjectAccessReviewSpec
+		if strings.HasPrefix(target, "/") {
+			spec.NonResourceAttributes = &models.NonResourceAttributes{Path: target, Verb: verb}
+		} else {
+			resource, name := target, ""
+			if parts := strings.SplitN(target, "/", 2); len(parts) == 2 {
+				resource, name = parts[0], parts[1]
+			}
+			ns := namespace
+			if allNamespaces || isClusterScoped(resource) {
+				ns = ""
+			}
+			spec.ResourceAttributes = &models.ResourceAttributes{
+				Namespace:   ns,
+				Verb:        verb,
+				Resource:    resource,
+				Subresource: canISubresource,
+				Name:        name,
+			}
+		}
+
+		status, err := getClient().CheckAccess(spec)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		if !canIQuiet {
+			if status.Allowed {
+				fmt.Println("yes")
+			} else {
+				fmt.Println("no")
+			}
+		}
+		if !status.Allowed {
+			os.Exit(1)
+		}
+	},
+}
+
+var whoAmICmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the user the API server authenticates you as",
+	Run: func(cmd *cobra.Command, args []string) {
+		user, err := getClient().WhoAmI()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("Username: %s\n", user.Name)
+		if user.UID != "" {
+			fmt.Printf("UID:      %s\n", user.UID)
+		}
+		fmt.Printf("Groups:   %s\n", strings.Join(user.Groups, ", "))
+	},
+}
+
+// isClusterScoped reports whether a resource lives outside namespaces.
+func isClusterScoped(resource string) bool {
+	switch resource {
+	case "nodes", "clusterroles", "clusterrolebindings":
+		return true
+	}
+	return false
+}
+
+func init() {
+	canICmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace to check in")
+	canICmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Check across all namespaces")
+	canICmd.Flags().StringVar(&canISubresource, "subresource", "", "Subresource such as status")
+	canICmd.Flags().BoolVarP(&canIQuiet, "quiet", "q", false, "Only set the exit code")
+
+	authCmd.AddCommand(canICmd)
+	authCmd.AddCommand(whoAmICmd)
+	rootCmd.AddCommand(authCmd)
+}
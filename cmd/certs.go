@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
+	"github.com/spf13/cobra"
+)
+
+var (
+	certsDir       string
+	certsForce     bool
+	apiServerHosts []string
+	certNodes      []string
+	certGroups     []string
+	certHosts      []string
+	certServer     bool
+	certValidity   time.Duration
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage the cluster's built-in certificate authority",
+}
+
+var generateCertsCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Create a CA and the certificates the cluster components need",
+	Long: `Create a CA (reused if it already exists) and issue:
+
+  apiserver.crt          API server serving certificate
+  apiserver-kubelet.crt  client certificate the API server uses to call node servers
+  admin.crt              client certificate in group system:masters
+  node-<name>.crt        serving and client certificate for each --node
+  sa.key                 service account token signing key
+
+Existing files are kept unless --force is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.MkdirAll(certsDir, 0755); err != nil {
+			fmt.Printf("❌ Failed to create %s: %v\n", certsDir, err)
+			return
+		}
+
+		ca, err := loadOrCreateCA(certsDir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		requests := map[string]pki.CertRequest{
+			"apiserver": {
+				CommonName: "mykube-apiserver",
+				Hosts:      append([]string{"localhost", "127.0.0.1"}, apiServerHosts...),
+				Server:     true,
+			},
+			"apiserver-kubelet": {
+				CommonName:    auth.KubeletClientUser,
+				Organizations: []string{auth.MastersGroup},
+				Client:        true,
+			},
+			"admin": {
+				CommonName:    "admin",
+				Organizations: []string{auth.MastersGroup},
+				Client:        true,
+			},
+		}
+		for _, node := range certNodes {
+			name, ip, _ := strings.Cut(node, "=")
+			hosts := []string{name}
+			if ip != "" {
+				hosts = append(hosts, ip)
+			}
+			requests["node-"+name] = pki.CertRequest{
+				CommonName:    auth.NodeUserPrefix + name,
+				Organizations: []string{auth.NodesGroup},
+				Hosts:         hosts,
+				Server:        true,
+				Client:        true,
+			}
+		}
+
+		for name, req := range requests {
+			req.Validity = certValidity
+			if err := issueCert(ca, name, req); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+		}
+
+		saKey := filepath.Join(certsDir, "sa.key")
+		if _, err := os.Stat(saKey); err == nil && !certsForce {
+			fmt.Printf("⏭️  %s exists, skipping\n", saKey)
+		} else if err := pki.GenerateRSAKey(saKey); err != nil {
+			fmt.Printf("❌ Failed to write %s: %v\n", saKey, err)
+			return
+		} else {
+			fmt.Printf("✅ Wrote %s\n", saKey)
+		}
+	},
+}
+
+var issueCertCmd = &cobra.Command{
+	Use:   "issue NAME",
+	Short: "Issue a client (or, with --server, serving) certificate from the CA",
+	Long: `Issue a certificate signed by the CA in --dir and write it to NAME.crt and
+NAME.key. NAME is the user name; --group adds groups. Re-issuing over an
+existing certificate rotates it, running components pick up the new files
+without a restart.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ca, err := pki.LoadCA(filepath.Join(certsDir, "ca.crt"), filepath.Join(certsDir, "ca.key"))
+		if err != nil {
+			fmt.Printf("❌ Failed to load CA: %v\n", err)
+			return
+		}
+
+		certsForce = true
+		req := pki.CertRequest{
+			CommonName:    args[0],
+			Organizations: certGroups,
+			Hosts:         certHosts,
+			Server:        certServer,
+			Client:        true,
+			Validity:      certValidity,
+		}
+		if err := issueCert(ca, args[0], req); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		}
+	},
+}
+
+func loadOrCreateCA(dir string) (*pki.CA, error) {
+	certFile := filepath.Join(dir, "ca.crt")
+	keyFile := filepath.Join(dir, "ca.key")
+
+	if _, err := os.Stat(certFile); err == nil {
+		ca, err := pki.LoadCA(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing CA: %v", err)
+		}
+		fmt.Printf("⏭️  Using existing CA %s\n", certFile)
+		return ca, nil
+	}
+
+	ca, err := pki.NewCA("mykube-ca")
+	if err != nil {
+		return nil, err
+	}
+	if err := ca.WriteFiles(certFile, keyFile); err != nil {
+		return nil, err
+	}
+	fmt.Printf("✅ Wrote %s\n", certFile)
+	return ca, nil
+}
+
+// issueCert writes name.crt and name.key into certsDir, leaving an existing
+// pair alone unless --force is set.
+func issueCert(ca *pki.CA, name string, req pki.CertRequest) error {
+	certFile := filepath.Join(certsDir, name+".crt")
+	keyFile := filepath.Join(certsDir, name+".key")
+
+	if _, err := os.Stat(certFile); err == nil && !certsForce {
+		fmt.Printf("⏭️  %s exists, skipping\n", certFile)
+		return nil
+	}
+
+	certPEM, keyPEM, err := ca.Issue(req)
+	if err != nil {
+		return fmt.Errorf("failed to issue %s: %v", name, err)
+	}
+	if err := pki.WriteKeyPair(certFile, keyFile, certPEM, keyPEM); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Wrote %s\n", certFile)
+	return nil
+}
+
+func init() {
+	certsCmd.PersistentFlags().StringVar(&certsDir, "dir", "pki", "Directory holding ca.crt/ca.key and the issued certificates")
+	certsCmd.PersistentFlags().DurationVar(&certValidity, "validity", 365*24*time.Hour, "Lifetime of issued certificates")
+
+	generateCertsCmd.Flags().StringSliceVar(&apiServerHosts, "api-server-hosts", nil, "Extra DNS names and IPs for the API server certificate")
+	generateCertsCmd.Flags().StringArrayVar(&certNodes, "node", nil, "Issue a node certificate, as name=ip (repeatable)")
+	generateCertsCmd.Flags().BoolVar(&certsForce, "force", false, "Overwrite existing certificates")
+
+	issueCertCmd.Flags().StringSliceVar(&certGroups, "group", nil, "Groups (certificate organizations) of the user")
+	issueCertCmd.Flags().StringSliceVar(&certHosts, "host", nil, "DNS names and IPs, for serving certificates")
+	issueCertCmd.Flags().BoolVar(&certServer, "server", false, "Allow the certificate to be used for serving")
+
+	certsCmd.AddCommand(generateCertsCmd)
+	certsCmd.AddCommand(issueCertCmd)
+	rootCmd.AddCommand(certsCmd)
+}
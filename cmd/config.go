@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configRaw    bool
+	configMinify bool
+
+	setContextCluster   string
+	setContextUser      string
+	setContextNamespace string
+	setContextCurrent   bool
+
+	clusterServer string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and modify the config file",
+	Long: `View and modify the config file, ~/.mykube/config unless --kubeconfig or
+MYKUBE_CONFIG name another.
+
+The file lists clusters (API server URLs and the CA bundles that verify
+them), users (tokens and client certificates) and contexts pairing a
+cluster with a user and a default namespace. Commands talk to the cluster
+of the current context unless --context picks another or --api-host is
+given.`,
+	Example: `  mykube config set-cluster prod --server https://10.0.0.1:8443 --certificate-authority ca.crt
+  mykube config set-credentials admin --client-certificate admin.crt --client-key admin.key
+  mykube config set-context prod --cluster prod --user admin --namespace team-a
+  mykube config use-context prod`,
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the config file",
+	Long:  `Show the config file, with tokens redacted unless --raw is given.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := client.LoadConfig(kubeconfig)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if configMinify {
+			if file, err = minifyConfig(file, contextName); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				return
+			}
+		}
+		if !configRaw {
+			for i := range file.Users {
+				if file.Users[i].User.Token != "" {
+					file.Users[i].User.Token = "REDACTED"
+				}
+			}
+		}
+
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(file); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		}
+		enc.Close()
+	},
+}
+
+var useContextCmd = &cobra.Command{
+	Use:   "use-context NAME",
+	Short: "Set the current context",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := updateConfig(func(file *client.Config) error {
+			if _, err := file.Context(args[0]); err != nil {
+				return err
+			}
+			file.CurrentContext = args[0]
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Switched to context %q\n", args[0])
+	},
+}
+
+var setContextCmd = &cobra.Command{
+	Use:   "set-context [NAME | --current]",
+	Short: "Add a context or change one",
+	Long:  `Add a context or change one. Only the fields given as flags are changed.`,
+	Example: `  mykube config set-context dev --cluster dev --user alice
+  mykube config set-context --current --namespace team-a`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var name string
+		err := updateConfig(func(file *client.Config) error {
+			switch {
+			case setContextCurrent && len(args) == 0:
+				if file.CurrentContext == "" {
+					return fmt.Errorf("no current context is set")
+				}
+				name = file.CurrentContext
+			case !setContextCurrent && len(args) == 1:
+				name = args[0]
+			default:
+				return fmt.Errorf("give either a context name or --current")
+			}
+
+			var context client.Context
+			if existing, err := file.Context(name); err == nil {
+				context = *existing
+			}
+			flags := cmd.Flags()
+			if flags.Changed("cluster") {
+				context.Cluster = setContextCluster
+			}
+			if flags.Changed("user") {
+				context.User = setContextUser
+			}
+			if flags.Changed("namespace") {
+				context.Namespace = setContextNamespace
+			}
+			file.SetContext(name, context)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Context %q set\n", name)
+	},
+}
+
+var setClusterCmd = &cobra.Command{
+	Use:   "set-cluster NAME",
+	Short: "Add a cluster or change one",
+	Long: `Add a cluster or change one. Only the fields given as flags are changed;
+the CA bundle is given with the global --certificate-authority flag and is
+stored as an absolute path.`,
+	Example: `  mykube config set-cluster prod --server https://10.0.0.1:8443 --certificate-authority pki/ca.crt`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := updateConfig(func(file *client.Config) error {
+			var cluster client.Cluster
+			if existing, err := file.Cluster(args[0]); err == nil {
+				cluster = *existing
+			}
+			flags := cmd.Flags()
+			if flags.Changed("server") {
+				cluster.Server = clusterServer
+			}
+			if flags.Changed("certificate-authority") {
+				path, err := absPath(certificateAuthority)
+				if err != nil {
+					return err
+				}
+				cluster.CertificateAuthority = path
+			}
+			if cluster.Server == "" {
+				return fmt.Errorf("--server is required for a new cluster")
+			}
+			file.SetCluster(args[0], cluster)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Cluster %q set\n", args[0])
+	},
+}
+
+var setCredentialsCmd = &cobra.Command{
+	Use:   "set-credentials NAME",
+	Short: "Add a user or change one",
+	Long: `Add a user or change one. The credentials are given with the global
+--token, --client-certificate and --client-key flags; only those given are
+changed. Certificate and key paths are stored as absolute paths.`,
+	Example: `  mykube config set-credentials alice --token "$(cat alice.token)"
+  mykube config set-credentials admin --client-certificate admin.crt --client-key admin.key`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := updateConfig(func(file *client.Config) error {
+			var user client.AuthInfo
+			if existing, err := file.User(args[0]); err == nil {
+				user = *existing
+			}
+			flags := cmd.Flags()
+			if flags.Changed("token") {
+				user.Token = token
+			}
+			for flag, field := range map[string]struct {
+				dst   *string
+				value string
+			}{
+				"client-certificate": {&user.ClientCertificate, clientCertificate},
+				"client-key":         {&user.ClientKey, clientKey},
+			} {
+				if !flags.Changed(flag) {
+					continue
+				}
+				path, err := absPath(field.value)
+				if err != nil {
+					return err
+				}
+				*field.dst = path
+			}
+			if (user.ClientCertificate == "") != (user.ClientKey == "") {
+				return fmt.Errorf("a client certificate and its key must be given together")
+			}
+			file.SetUser(args[0], user)
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ User %q set\n", args[0])
+	},
+}
+
+// updateConfig loads the config file, lets update change it and saves it.
+func updateConfig(update func(file *client.Config) error) error {
+	file, err := client.LoadConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	if err := update(file); err != nil {
+		return err
+	}
+	return file.Save(kubeconfig)
+}
+
+// minifyConfig keeps only the context called name, or the current one, and
+// the cluster and user it refers to.
+func minifyConfig(file *client.Config, name string) (*client.Config, error) {
+	if name == "" {
+		name = file.CurrentContext
+	}
+	context, err := file.Context(name)
+	if err != nil {
+		return nil, err
+	}
+	if context == nil {
+		return nil, fmt.Errorf("no current context is set")
+	}
+
+	minified := &client.Config{APIVersion: file.APIVersion, Kind: file.Kind, CurrentContext: name}
+	minified.SetContext(name, *context)
+	if cluster, err := file.Cluster(context.Cluster); err == nil {
+		minified.SetCluster(context.Cluster, *cluster)
+	}
+	if user, err := file.User(context.User); err == nil {
+		minified.SetUser(context.User, *user)
+	}
+	return minified, nil
+}
+
+// absPath makes a file path absolute, so that the config file works from
+// any directory. An empty path stays empty and unsets the field.
+func absPath(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	return filepath.Abs(path)
+}
+
+func init() {
+	configViewCmd.Flags().BoolVar(&configRaw, "raw", false, "Show tokens instead of redacting them")
+	configViewCmd.Flags().BoolVar(&configMinify, "minify", false, "Show only the context in use and the cluster and user it refers to")
+
+	setContextCmd.Flags().StringVar(&setContextCluster, "cluster", "", "Cluster of the context")
+	setContextCmd.Flags().StringVar(&setContextUser, "user", "", "User of the context")
+	setContextCmd.Flags().StringVar(&setContextNamespace, "namespace", "", "Namespace commands default to in the context")
+	setContextCmd.Flags().BoolVar(&setContextCurrent, "current", false, "Change the current context")
+
+	setClusterCmd.Flags().StringVar(&clusterServer, "server", "", "URL of the API server, e.g. https://10.0.0.1:8443")
+
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(useContextCmd)
+	configCmd.AddCommand(setContextCmd)
+	configCmd.AddCommand(setClusterCmd)
+	configCmd.AddCommand(setCredentialsCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+	"github.com/spf13/cobra"
+)
+
+var cpContainer string
+
+var cpCmd = &cobra.Command{
+	Use:   "cp SOURCE DEST",
+	Short: "Copy files and directories to and from a pod",
+	Long: `Copy files and directories to and from a pod's container. Pod paths are
+written [NAMESPACE/]POD:PATH and exactly one of SOURCE and DEST must be one.
+
+The copy is a tar archive streamed over an exec session running tar in the
+container, so the container's image must have tar. File modes and
+modification times are kept; files written in the pod belong to the user
+the container runs as. DEST is the path the copy is written to, unless it
+ends in / or is an existing local directory, which the copy is then written
+into.`,
+	Example: `  mykube cp web:/tmp/heap.hprof ./heap.hprof
+  mykube cp ./fixtures web:/srv/fixtures -c app
+  mykube cp team-a/web:/var/log/app.log /tmp/`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		src, srcInPod := parsePodPath(args[0])
+		dest, destInPod := parsePodPath(args[1])
+		if srcInPod == destInPod {
+			fmt.Println("❌ exactly one of SOURCE and DEST must be a pod path, POD:PATH")
+			os.Exit(1)
+		}
+
+		c := getClient()
+		var err error
+		if srcInPod {
+			err = copyFromPod(c, src, dest.path)
+		} else {
+			err = copyToPod(c, src.path, dest)
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Copied %s to %s\n", args[0], args[1])
+	},
+}
+
+// podPath is a path in a pod's container, or a local path when pod is
+// empty.
+type podPath struct {
+	namespace string
+	pod       string
+	path      string
+}
+
+// parsePodPath splits [NAMESPACE/]POD:PATH, reporting whether arg is one.
+// Anything else, such as ./a:b, is a local path.
+func parsePodPath(arg string) (podPath, bool) {
+	pod, p, found := strings.Cut(arg, ":")
+	if !found || pod == "" || strings.HasPrefix(pod, ".") || strings.HasPrefix(pod, "/") || strings.Count(pod, "/") > 1 {
+		return podPath{path: arg}, false
+	}
+	ns := namespace
+	if podNamespace, name, found := strings.Cut(pod, "/"); found {
+		ns, pod = podNamespace, name
+	}
+	if ns == "" {
+		ns = "default"
+	}
+	return podPath{namespace: ns, pod: pod, path: p}, true
+}
+
+// copyFromPod copies src out of a pod to the local path dest, by extracting
+// the archive tar writes in the container.
+func copyFromPod(c *client.Client, src podPath, dest string) error {
+	if src.path == "" {
+		return fmt.Errorf("a path in pod %q is required", src.pod)
+	}
+	dir, base := path.Split(path.Clean(src.path))
+	if dir == "" {
+		dir = "."
+	}
+	if base == "" {
+		// The root directory
+		base = "."
+	}
+	if info, err := os.Stat(dest); strings.HasSuffix(dest, "/") || (err == nil && info.IsDir()) {
+		dest = filepath.Join(dest, path.Base(path.Clean(src.path)))
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := podTar(c, src, []string{"tar", "-cf", "-", "-C", dir, base}, nil, writer)
+		writer.CloseWithError(err)
+		done <- err
+	}()
+
+	err := extractTar(reader, base, dest)
+	if err != nil {
+		reader.CloseWithError(err)
+	} else {
+		// tar pads the archive past the end marker
+		io.Copy(io.Discard, reader)
+	}
+	if tarErr := <-done; tarErr != nil {
+		return tarErr
+	}
+	return err
+}
+
+// copyToPod copies the local path src into a pod, by running tar in the
+// container on an archive of it.
+func copyToPod(c *client.Client, src string, dest podPath) error {
+	if dest.path == "" {
+		return fmt.Errorf("a path in pod %q is required", dest.pod)
+	}
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+	target := dest.path
+	if strings.HasSuffix(target, "/") {
+		target += filepath.Base(filepath.Clean(src))
+	}
+	dir, base := path.Split(path.Clean(target))
+	if dir == "" {
+		dir = "."
+	}
+	if base == "" {
+		return fmt.Errorf("cannot copy over the root directory of pod %q", dest.pod)
+	}
+
+	reader, writer := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := writeTar(writer, src, base)
+		writer.CloseWithError(err)
+		written <- err
+	}()
+	err := podTar(c, dest, []string{"tar", "-xf", "-", "-C", dir}, reader, io.Discard)
+	reader.Close()
+	// A local failure explains the archive tar found truncated
+	if writeErr := <-written; writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+		return writeErr
+	}
+	return err
+}
+
+// podTar runs a tar command in a pod's container, turning its failures
+// into errors carrying what tar printed.
+func podTar(c *client.Client, target podPath, command []string, stdin io.Reader, stdout io.Writer) error {
+	opts := models.PodExecOptions{
+		Container: cpContainer,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+	}
+	var stderr bytes.Buffer
+	streams := wsstream.ExecStreams{Stdin: stdin, Stdout: stdout, Stderr: &stderr}
+	err := c.Exec(context.Background(), target.namespace, target.pod, opts, streams)
+	var exitErr *wsstream.ExitError
+	if errors.As(err, &exitErr) && stderr.Len() > 0 {
+		return fmt.Errorf("%s in pod %q: %s", command[0], target.pod, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// writeTar writes an archive of the file or directory src, with its
+// entries under prefix. Modes and modification times are kept; owners are
+// left to whoever extracts it.
+func writeTar(w io.Writer, src, prefix string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "⚠️ Skipping %s, which is not a file, directory or symlink\n", file)
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractTar writes the entries of an archive under base to dest, so that
+// base itself becomes dest. Modes and modification times are restored.
+// Entries outside base, and symlinks leading out of dest, are skipped.
+func extractTar(r io.Reader, base, dest string) error {
+	type dirAttrs struct {
+		path    string
+		mode    os.FileMode
+		modTime time.Time
+	}
+	// Directories get their modes last, so read-only ones can be filled
+	var dirs []dirAttrs
+	found := false
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		var rel string
+		switch {
+		case base == ".":
+			rel = name
+		case name == base:
+			rel = "."
+		case strings.HasPrefix(name, base+"/"):
+			rel = strings.TrimPrefix(name, base+"/")
+		default:
+			continue
+		}
+		if rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+			fmt.Fprintf(os.Stderr, "⚠️ Skipping %s, which is outside the copied path\n", header.Name)
+			continue
+		}
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		found = true
+		mode := header.FileInfo().Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirAttrs{target, mode, header.ModTime})
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr, mode, header.ModTime); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(header.Linkname)
+			resolved := filepath.Join(filepath.Dir(target), link)
+			if filepath.IsAbs(link) || !withinDir(dest, resolved) {
+				fmt.Fprintf(os.Stderr, "⚠️ Skipping symlink %s -> %s, which leads outside %s\n", header.Name, header.Linkname, dest)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "⚠️ Skipping %s, which is not a file, directory or symlink\n", header.Name)
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s not found in the archive", base)
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+		os.Chtimes(dirs[i].path, dirs[i].modTime, dirs[i].modTime)
+	}
+	return nil
+}
+
+// writeFile writes a regular file with a mode and modification time.
+func writeFile(file string, r io.Reader, mode os.FileMode, modTime time.Time) error {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// Set after writing, since the umask applies at creation
+	if err := os.Chmod(file, mode); err != nil {
+		return err
+	}
+	return os.Chtimes(file, modTime, modTime)
+}
+
+// withinDir reports whether file is dir or inside it.
+func withinDir(dir, file string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func init() {
+	cpCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of pods not given one as NAMESPACE/POD")
+	cpCmd.Flags().StringVarP(&cpContainer, "container", "c", "", "Container to copy to or from; defaults to the pod's first container")
+	rootCmd.AddCommand(cpCmd)
+}
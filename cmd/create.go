@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/spf13/cobra"
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create -f FILENAME",
+	Short: "Create resources from files, directories, stdin or URLs",
+	Long: `Create the objects in YAML or JSON files, read as apply reads them. Unlike
+apply, objects that already exist are reported as errors and left as they are.
+
+--dry-run=client only prints what would be created; --dry-run=server has the
+API server validate and default each object without persisting it.`,
+	Example: `  mykube create -f pod.yaml
+  mykube create -f manifests/ -R --dry-run=server`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkDryRun(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		flagNamespace := ""
+		if cmd.Flags().Changed("namespace") {
+			flagNamespace = namespace
+		}
+
+		var manifests []manifest
+		for _, path := range files {
+			found, err := readManifests(path, recursive)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			manifests = append(manifests, found...)
+		}
+		sortManifests(manifests)
+
+		c := getClient()
+		for _, m := range manifests {
+			ns, err := manifestNamespace(m, flagNamespace)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			if err := createManifest(c, m, ns); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			fmt.Printf("✅ %s/%s created%s\n", resourcePrefix(m.res), m.name, dryRunSuffix())
+		}
+	},
+}
+
+// createManifest creates one object, failing if it exists.
+func createManifest(c *client.Client, m manifest, namespace string) error {
+	data, err := m.json()
+	if m.res.Kind == "Node" {
+		data, err = nodeJSON(m)
+	}
+	if err != nil {
+		return err
+	}
+	if dryRun == "client" {
+		return nil
+	}
+	// Nodes re-register themselves with POST, which saves over the node
+	if m.res.Kind == "Node" {
+		var live models.Node
+		if err := c.Get(m.res, "", m.name, &live); err == nil {
+			return fmt.Errorf("%s %q already exists", m.res.Singular, m.name)
+		} else if !errors.Is(err, client.ErrNotFound) {
+			return err
+		}
+	}
+	_, err = c.Create(m.res, namespace, data, client.CreateOptions{DryRun: dryRun == "server"})
+	return err
+}
+
+func init() {
+	createCmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Files, directories, URLs or - for stdin containing the resource definitions")
+	createCmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Read the directories given with -f recursively")
+	createCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the resources, if the files do not set one")
+	addDryRunFlag(createCmd)
+	createCmd.MarkFlagRequired("filename")
+	rootCmd.AddCommand(createCmd)
+}
@@ -1,46 +1,156 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/selimhanmrl/Own-Kubernetes/client"
 	"github.com/spf13/cobra"
 )
 
-func getClient() *client.Client {
-	return client.NewClient(client.ClientConfig{
-		Host: apiHost,
-		Port: apiPort,
-	})
-}
+var (
+	deleteFiles []string // Files whose objects to delete
+	deleteAll   bool     // Delete every object of the type
+)
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete [resource] [name]",
-	Short: "Delete a resource",
-	Args:  cobra.ExactArgs(2),
+	Use:   "delete (TYPE [NAME...] | TYPE/NAME... | -f FILE)",
+	Short: "Delete resources by name, by label selector or from a file",
+	Long: `Delete resources by name, every resource of a type that matches a label
+selector (or --all of them), or the objects in YAML or JSON files, read as
+apply reads them.
+
+TYPE is a plural, singular, kind or short name such as pods, pod or po.`,
+	Example: `  mykube delete pod web
+  mykube delete svc/web deploy/web
+  mykube delete pods -l app=web
+  mykube delete rs --all -n team-a
+  mykube delete -f web.yaml
+  mykube delete pods -l app=web --dry-run=server`,
 	Run: func(cmd *cobra.Command, args []string) {
-		resourceType := args[0]
-		name := args[1]
+		if err := checkDryRun(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		c := getClient()
 
-		client := getClient()
+		if len(deleteFiles) > 0 {
+			if len(args) > 0 {
+				fmt.Println("❌ Resources cannot be named together with -f")
+				return
+			}
+			flagNamespace := ""
+			if cmd.Flags().Changed("namespace") {
+				flagNamespace = namespace
+			}
+			var manifests []manifest
+			for _, path := range deleteFiles {
+				found, err := readManifests(path, recursive)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					continue
+				}
+				manifests = append(manifests, found...)
+			}
+			// The reverse of the apply order: workloads before the
+			// namespaces they live in
+			sortManifests(manifests)
+			for i := len(manifests) - 1; i >= 0; i-- {
+				m := manifests[i]
+				ns, err := manifestNamespace(m, flagNamespace)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					continue
+				}
+				deleteObject(c, m.res, ns, m.name)
+			}
+			return
+		}
 
-		switch resourceType {
-		case "pod":
-			if err := client.DeletePod("default", name); err != nil {
-				fmt.Printf("❌ Failed to delete pod: %v\n", err)
+		targets, err := parseResourceArgs(args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		ns := namespace
+		if ns == "" {
+			ns = "default"
+		}
+		for _, target := range targets {
+			if len(target.names) > 0 {
+				for _, name := range target.names {
+					deleteObject(c, target.res, ns, name)
+				}
+				continue
+			}
+
+			if labelSelector == "" && !deleteAll {
+				fmt.Printf("❌ Name the %s to delete, or select them with -l or --all\n", target.res.Name)
 				return
 			}
-			fmt.Printf("✅ Pod '%s' deleted successfully\n", name)
-		default:
-			fmt.Printf("❌ Unknown resource type: %s\n", resourceType)
+			listNamespace := ns
+			if allNamespaces {
+				listNamespace = ""
+			}
+			objects, err := c.List(target.res, listNamespace, client.ListOptions{LabelSelector: labelSelector})
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			if len(objects) == 0 {
+				fmt.Printf("No %s found.\n", target.res.Name)
+				continue
+			}
+			for _, obj := range objects {
+				name, objNamespace, err := objectKey(obj)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					continue
+				}
+				deleteObject(c, target.res, objNamespace, name)
+			}
 		}
 	},
 }
 
+// deleteObject deletes one object and reports the outcome. A client dry
+// run deletes nothing and sends nothing.
+func deleteObject(c *client.Client, res client.Resource, namespace, name string) {
+	if dryRun != "client" {
+		if err := c.Delete(res, namespace, name, client.DeleteOptions{DryRun: dryRun == "server"}); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+	}
+	fmt.Printf("✅ %s/%s deleted%s\n", resourcePrefix(res), name, dryRunSuffix())
+}
+
+// objectKey reads the name and namespace of a listed object. Nodes keep
+// their name at the top level rather than in metadata.
+func objectKey(obj json.RawMessage) (name, namespace string, err error) {
+	var object struct {
+		Name     string `json:"name"`
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(obj, &object); err != nil {
+		return "", "", fmt.Errorf("failed to decode object: %v", err)
+	}
+	if object.Metadata.Name == "" {
+		return object.Name, "", nil
+	}
+	return object.Metadata.Name, object.Metadata.Namespace, nil
+}
+
 func init() {
-	// Add api-host and api-port flags
-	deleteCmd.Flags().StringVar(&apiHost, "api-host", "localhost", "API server hostname")
-	deleteCmd.Flags().StringVar(&apiPort, "api-port", "8080", "API server port")
-	deleteCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pod")
+	deleteCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the resources")
+	deleteCmd.Flags().StringSliceVarP(&deleteFiles, "filename", "f", nil, "Files, directories, URLs or - for stdin containing the objects to delete")
+	deleteCmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Read the directories given with -f recursively")
+	deleteCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Delete the resources matching a label selector, e.g. app=web")
+	deleteCmd.Flags().BoolVar(&deleteAll, "all", false, "Delete every resource of the type in the namespace")
+	deleteCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "With -l or --all, delete across all namespaces")
+	addDryRunFlag(deleteCmd)
 	rootCmd.AddCommand(deleteCmd)
 }
@@ -0,0 +1,590 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe (TYPE [NAME...] | TYPE/NAME...)",
+	Short: "Show details of resources, with their recent events",
+	Long: `Show details of the named resources, or of every resource of a type.
+
+Pods, services and nodes have their own view; other resources show their
+metadata and fields. TYPE is a plural, singular, kind or short name.`,
+	Example: `  mykube describe pod web
+  mykube describe svc/web -n team-a
+  mykube describe node worker1
+  mykube describe deploy -l app=web`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targets, err := parseResourceArgs(args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		c := getClient()
+		first := true
+		for _, target := range targets {
+			objects, err := fetchObjects(c, target)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			for _, obj := range objects {
+				if !first {
+					fmt.Println()
+				}
+				first = false
+
+				d := newDescriber(os.Stdout)
+				if err := describeObject(d, c, target.res, obj); err != nil {
+					fmt.Printf("❌ %v\n", err)
+					continue
+				}
+				d.Flush()
+			}
+		}
+	},
+}
+
+// fetchObjects gets the named objects of a target, or lists them all with
+// the label selector when it names none. Objects that cannot be fetched
+// are reported and skipped.
+func fetchObjects(c *client.Client, target resourceTarget) ([]json.RawMessage, error) {
+	ns := namespace
+	if ns == "" {
+		ns = "default"
+	}
+	if len(target.names) == 0 {
+		return c.List(target.res, ns, client.ListOptions{LabelSelector: labelSelector})
+	}
+	var objects []json.RawMessage
+	for _, name := range target.names {
+		var obj json.RawMessage
+		if err := c.Get(target.res, ns, name, &obj); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// describeObject describes one object as fetched from the API server.
+func describeObject(d *describer, c *client.Client, res client.Resource, obj json.RawMessage) error {
+	switch res.Kind {
+	case "Pod":
+		var pod models.Pod
+		if err := json.Unmarshal(obj, &pod); err != nil {
+			return err
+		}
+		describePod(d, c, pod)
+	case "Service":
+		var service models.Service
+		if err := json.Unmarshal(obj, &service); err != nil {
+			return err
+		}
+		describeService(d, c, service)
+	case "Node":
+		var node models.Node
+		if err := json.Unmarshal(obj, &node); err != nil {
+			return err
+		}
+		describeNode(d, c, node)
+	default:
+		return describeGeneric(d, c, res, obj)
+	}
+	return nil
+}
+
+// describer writes "Label:<tab>value" lines, indented two spaces per
+// level. Top-level lines are aligned with each other, and so are the lines
+// of each nested block.
+type describer struct {
+	*tabwriter.Writer
+	nested bool
+}
+
+func newDescriber(out io.Writer) *describer {
+	return &describer{Writer: tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)}
+}
+
+func (d *describer) line(level int, format string, args ...interface{}) {
+	if nested := level > 0; nested != d.nested {
+		d.Flush()
+		d.nested = nested
+	}
+	fmt.Fprintf(d, strings.Repeat("  ", level)+format+"\n", args...)
+}
+
+// labels writes a map as one key=value per line, sorted, or <none>.
+func (d *describer) labels(level int, title string, labels map[string]string) {
+	if len(labels) == 0 {
+		d.line(level, "%s:\t<none>", title)
+		return
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			d.line(level, "%s:\t%s=%s", title, key, labels[key])
+		} else {
+			d.line(level, "\t%s=%s", key, labels[key])
+		}
+	}
+}
+
+// resources writes a resource list as one "name: quantity" per line.
+func (d *describer) resources(level int, title string, list models.ResourceList) {
+	if len(list) == 0 {
+		return
+	}
+	d.line(level, "%s:", title)
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d.line(level+1, "%s:\t%s", name, list[name].String())
+	}
+}
+
+// events lists the events about one object, oldest first.
+func (d *describer) events(c *client.Client, ref models.ObjectReference) {
+	selector := fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", ref.Kind, ref.Name)
+	if ref.Namespace != "" {
+		selector += ",involvedObject.namespace=" + ref.Namespace
+	}
+	events, err := c.ListEvents(ref.Namespace, client.ListOptions{FieldSelector: selector})
+	if err != nil {
+		d.line(0, "Events:\t<unable to list: %v>", err)
+		return
+	}
+	if len(events) == 0 {
+		d.line(0, "Events:\t<none>")
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+	})
+	d.line(0, "Events:")
+	d.line(1, "Type\tReason\tAge\tFrom\tMessage")
+	d.line(1, "----\t------\t----\t----\t-------")
+	for _, event := range events {
+		from := event.Source.Component
+		if event.Source.Host != "" {
+			from += ", " + event.Source.Host
+		}
+		d.line(1, "%s\t%s\t%s\t%s\t%s", event.Type, event.Reason, lastSeen(event), from, event.Message)
+	}
+}
+
+func describePod(d *describer, c *client.Client, pod models.Pod) {
+	d.line(0, "Name:\t%s", pod.Metadata.Name)
+	d.line(0, "Namespace:\t%s", pod.Metadata.Namespace)
+	node := valueOrNone(pod.Spec.NodeName)
+	if pod.Spec.NodeName != "" && pod.Status.HostIP != "" {
+		node += "/" + pod.Status.HostIP
+	}
+	d.line(0, "Node:\t%s", node)
+	d.line(0, "Start Time:\t%s", valueOrNone(pod.Status.StartTime))
+	d.labels(0, "Labels", pod.Metadata.Labels)
+	d.line(0, "Status:\t%s", pod.Status.Phase)
+	d.line(0, "IP:\t%s", valueOrNone(pod.Status.PodIP))
+	d.line(0, "Host IP:\t%s", valueOrNone(pod.Status.HostIP))
+	for _, owner := range pod.Metadata.OwnerReferences {
+		if owner.Controller {
+			d.line(0, "Controlled By:\t%s/%s", owner.Kind, owner.Name)
+		} else {
+			d.line(0, "Owned By:\t%s/%s", owner.Kind, owner.Name)
+		}
+	}
+
+	d.line(0, "Containers:")
+	for _, container := range pod.Spec.Containers {
+		d.line(1, "%s:", container.Name)
+		d.line(2, "Image:\t%s", container.Image)
+		if len(container.Cmd) > 0 {
+			d.line(2, "Command:\t%s", strings.Join(container.Cmd, " "))
+		}
+		ports := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.ContainerPort, protocolOrTCP(port.Protocol)))
+		}
+		d.line(2, "Ports:\t%s", valueOrNone(strings.Join(ports, ", ")))
+		if pod.Status.AssignedPort > 0 {
+			d.line(2, "Host Port:\t%d", pod.Status.AssignedPort)
+		}
+		// Nodes report the pod's phase rather than each container's
+		state, reason := containerState(pod)
+		d.line(2, "State:\t%s", state)
+		if reason != "" {
+			d.line(3, "Reason:\t%s", reason)
+		}
+		if state == "Running" && pod.Status.StartTime != "" {
+			d.line(3, "Started:\t%s", pod.Status.StartTime)
+		}
+		d.line(2, "Ready:\t%t", pod.Status.Phase == "Running")
+		d.resources(2, "Requests", container.Resources.Requests)
+		d.resources(2, "Limits", container.Resources.Limits)
+		if len(container.VolumeMounts) == 0 {
+			d.line(2, "Mounts:\t<none>")
+		} else {
+			d.line(2, "Mounts:")
+			for _, mount := range container.VolumeMounts {
+				mode := "rw"
+				if mount.ReadOnly {
+					mode = "ro"
+				}
+				d.line(3, "%s from %s (%s)", mount.MountPath, mount.Name, mode)
+			}
+		}
+	}
+
+	d.line(0, "Conditions:")
+	d.line(1, "Type\tStatus")
+	d.line(1, "PodScheduled\t%s", conditionStatus(pod.Spec.NodeName != ""))
+	d.line(1, "Ready\t%s", conditionStatus(pod.Status.Phase == "Running"))
+
+	if len(pod.Spec.Volumes) == 0 {
+		d.line(0, "Volumes:\t<none>")
+	} else {
+		d.line(0, "Volumes:")
+		for _, volume := range pod.Spec.Volumes {
+			d.line(1, "%s:", volume.Name)
+			switch {
+			case volume.HostPath != nil:
+				d.line(2, "Type:\tHostPath (bare host directory volume)")
+				d.line(2, "Path:\t%s", volume.HostPath.Path)
+			case volume.EmptyDir != nil:
+				d.line(2, "Type:\tEmptyDir (a temporary directory that shares a pod's lifetime)")
+			default:
+				d.line(2, "Type:\t<unknown>")
+			}
+		}
+	}
+
+	d.events(c, models.PodReference(pod))
+}
+
+// containerState derives a container's state and its reason from the pod
+// phase.
+func containerState(pod models.Pod) (string, string) {
+	switch pod.Status.Phase {
+	case "Running":
+		return "Running", ""
+	case "Succeeded":
+		return "Terminated", "Completed"
+	case "Failed":
+		return "Terminated", "Error"
+	}
+	if pod.Spec.NodeName == "" {
+		return "Waiting", ""
+	}
+	return "Waiting", "ContainerCreating"
+}
+
+func describeService(d *describer, c *client.Client, service models.Service) {
+	d.line(0, "Name:\t%s", service.Metadata.Name)
+	d.line(0, "Namespace:\t%s", service.Metadata.Namespace)
+	d.labels(0, "Labels", service.Metadata.Labels)
+	d.labels(0, "Annotations", service.Metadata.Annotations)
+	d.line(0, "Selector:\t%s", valueOrNone(selectorString(service.Spec.Selector)))
+	d.line(0, "Type:\t%s", valueOrNone(service.Spec.Type))
+
+	// Endpoints are the running pods the proxy sends the service's traffic
+	// to
+	var pods []models.Pod
+	var podsErr error
+	if len(service.Spec.Selector) > 0 {
+		pods, podsErr = c.ListPodsWithOptions(service.Metadata.Namespace,
+			client.ListOptions{LabelSelector: selectorString(service.Spec.Selector)})
+	}
+
+	for _, port := range service.Spec.Ports {
+		d.line(0, "Port:\t%d/TCP", port.Port)
+		d.line(0, "TargetPort:\t%d/TCP", port.TargetPort)
+		if port.NodePort > 0 {
+			d.line(0, "NodePort:\t%d/TCP", port.NodePort)
+		}
+
+		var endpoints []string
+		for _, pod := range pods {
+			if pod.Status.Phase != "Running" {
+				continue
+			}
+			if pod.Status.AssignedPort > 0 {
+				endpoints = append(endpoints, fmt.Sprintf("%s:%d", pod.Status.HostIP, pod.Status.AssignedPort))
+			} else if ip := pod.Status.PodIP; ip != "" {
+				endpoints = append(endpoints, fmt.Sprintf("%s:%d", ip, port.TargetPort))
+			}
+		}
+		if podsErr != nil {
+			d.line(0, "Endpoints:\t<unable to list pods: %v>", podsErr)
+		} else {
+			d.line(0, "Endpoints:\t%s", valueOrNone(strings.Join(endpoints, ",")))
+		}
+	}
+
+	d.events(c, models.ObjectReference{Kind: "Service", Namespace: service.Metadata.Namespace, Name: service.Metadata.Name})
+}
+
+func describeNode(d *describer, c *client.Client, node models.Node) {
+	d.line(0, "Name:\t%s", node.Name)
+	d.labels(0, "Labels", node.Labels)
+	d.line(0, "Addresses:")
+	d.line(1, "InternalIP:\t%s", valueOrNone(node.IP))
+	d.line(0, "Status:\t%s", node.Status.Phase)
+	if node.Status.LastHeartbeat.IsZero() {
+		d.line(0, "Last Heartbeat:\t<none>")
+	} else {
+		d.line(0, "Last Heartbeat:\t%s (%s ago)",
+			node.Status.LastHeartbeat.Format(time.RFC1123Z), formatAge(time.Since(node.Status.LastHeartbeat)))
+	}
+
+	d.line(0, "Conditions:")
+	d.line(1, "Type\tStatus\tLastUpdateTime")
+	for _, condition := range node.Status.Conditions {
+		d.line(1, "%s\t%s\t%s", condition.Type, condition.Status, condition.LastUpdateTime.Format(time.RFC1123Z))
+	}
+	d.resources(0, "Capacity", node.Status.Capacity)
+	d.resources(0, "Allocatable", node.Status.Allocatable)
+
+	pods, err := c.ListPodsWithOptions("", client.ListOptions{FieldSelector: "spec.nodeName=" + node.Name})
+	if err != nil {
+		d.line(0, "Non-terminated Pods:\t<unable to list: %v>", err)
+		d.events(c, models.NodeReference(node.Name))
+		return
+	}
+	active := pods[:0]
+	for _, pod := range pods {
+		if pod.Status.Phase != "Failed" && pod.Status.Phase != "Succeeded" {
+			active = append(active, pod)
+		}
+	}
+
+	d.line(0, "Non-terminated Pods:\t(%d in total)", len(active))
+	if len(active) > 0 {
+		d.line(1, "Namespace\tName\tCPU Requests\tCPU Limits\tMemory Requests\tMemory Limits")
+		d.line(1, "---------\t----\t------------\t----------\t---------------\t-------------")
+	}
+	allocatable := node.Status.Allocatable
+	if len(allocatable) == 0 {
+		allocatable = node.Status.Capacity
+	}
+	requests, limits := models.ResourceList{}, models.ResourceList{}
+	for _, pod := range active {
+		podRequests, podLimits := pod.ResourceRequests(), pod.ResourceLimits()
+		requests.Add(podRequests)
+		limits.Add(podLimits)
+		d.line(1, "%s\t%s\t%s\t%s\t%s\t%s", pod.Metadata.Namespace, pod.Metadata.Name,
+			allocated(podRequests.Cpu(), allocatable.Cpu()), allocated(podLimits.Cpu(), allocatable.Cpu()),
+			allocated(podRequests.Memory(), allocatable.Memory()), allocated(podLimits.Memory(), allocatable.Memory()))
+	}
+
+	d.line(0, "Allocated resources:")
+	d.line(1, "Resource\tRequests\tLimits")
+	d.line(1, "--------\t--------\t------")
+	d.line(1, "cpu\t%s\t%s", allocated(requests.Cpu(), allocatable.Cpu()), allocated(limits.Cpu(), allocatable.Cpu()))
+	d.line(1, "memory\t%s\t%s", allocated(requests.Memory(), allocatable.Memory()), allocated(limits.Memory(), allocatable.Memory()))
+	d.line(1, "pods\t%d (%s)", len(active), percent(int64(len(active))*1000, allocatable.Pods().MilliValue()))
+
+	d.events(c, models.NodeReference(node.Name))
+}
+
+// allocated renders a quantity with its share of the node's allocatable
+// amount, e.g. "250m (12%)".
+func allocated(q, allocatable models.Quantity) string {
+	return fmt.Sprintf("%s (%s)", q.String(), percent(q.MilliValue(), allocatable.MilliValue()))
+}
+
+func percent(part, whole int64) string {
+	if whole <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%d%%", part*100/whole)
+}
+
+// selectorString renders a label map as a selector, e.g. "app=web,tier=db".
+func selectorString(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for key, value := range selector {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func conditionStatus(ok bool) string {
+	if ok {
+		return "True"
+	}
+	return "False"
+}
+
+func protocolOrTCP(protocol string) string {
+	if protocol == "" {
+		return "TCP"
+	}
+	return protocol
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// describeGeneric describes an object of a kind without a view of its
+// own: its metadata, then its other fields in the order the API server sent
+// them.
+func describeGeneric(d *describer, c *client.Client, res client.Resource, obj json.RawMessage) error {
+	var object struct {
+		Metadata models.Metadata `json:"metadata"`
+	}
+	if err := json.Unmarshal(obj, &object); err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(obj, &doc); err != nil {
+		return err
+	}
+
+	meta := object.Metadata
+	d.line(0, "Name:\t%s", meta.Name)
+	if res.Namespaced {
+		d.line(0, "Namespace:\t%s", meta.Namespace)
+	}
+	d.labels(0, "Labels", meta.Labels)
+	d.line(0, "Created:\t%s", valueOrNone(meta.CreationTimestamp))
+	for _, owner := range meta.OwnerReferences {
+		if owner.Controller {
+			d.line(0, "Controlled By:\t%s/%s", owner.Kind, owner.Name)
+		} else {
+			d.line(0, "Owned By:\t%s/%s", owner.Kind, owner.Name)
+		}
+	}
+
+	if len(doc.Content) == 1 && doc.Content[0].Kind == yaml.MappingNode {
+		fields := doc.Content[0].Content
+		for i := 0; i+1 < len(fields); i += 2 {
+			switch fields[i].Value {
+			case "apiVersion", "kind", "metadata":
+				continue
+			}
+			d.field(0, fieldTitle(fields[i].Value), fields[i].Value, fields[i+1])
+		}
+	}
+
+	d.events(c, models.ObjectReference{Kind: res.Kind, Namespace: meta.Namespace, Name: meta.Name})
+	return nil
+}
+
+// field writes the decoded value of the JSON field key under title: scalars
+// and lists of scalars on one line, objects as nested fields and lists of
+// objects as one nested block per item, titled by the item's name. The keys
+// of label maps are written as they are.
+func (d *describer) field(level int, title, key string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value := node.Value
+		if node.Tag == "!!null" {
+			value = ""
+		}
+		d.line(level, "%s:\t%s", title, valueOrNone(value))
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			d.line(level, "%s:\t<none>", title)
+			return
+		}
+		d.line(level, "%s:", title)
+		lower := strings.ToLower(key)
+		verbatim := strings.HasSuffix(lower, "labels") || lower == "annotations" || lower == "nodeselector"
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childKey := node.Content[i].Value
+			childTitle := fieldTitle(childKey)
+			if verbatim {
+				childTitle = childKey
+			}
+			d.field(level+1, childTitle, childKey, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		var scalars []string
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				scalars = nil
+				break
+			}
+			scalars = append(scalars, item.Value)
+		}
+		if len(scalars) == len(node.Content) {
+			d.line(level, "%s:\t%s", title, valueOrNone(strings.Join(scalars, ", ")))
+			return
+		}
+		d.line(level, "%s:", title)
+		for i, item := range node.Content {
+			d.field(level+1, itemTitle(item, i), "", item)
+		}
+	}
+}
+
+// itemTitle names a list item by its name field, or its index.
+func itemTitle(item *yaml.Node, index int) string {
+	if item.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if item.Content[i].Value == "name" && item.Content[i+1].Kind == yaml.ScalarNode {
+				return item.Content[i+1].Value
+			}
+		}
+	}
+	return fmt.Sprintf("[%d]", index)
+}
+
+// fieldTitle turns a JSON field name into a title, e.g. matchLabels into
+// Match Labels, podIP into Pod IP and hostIPs into Host IPs.
+func fieldTitle(name string) string {
+	var words []string
+	start := 0
+	for i := 1; i < len(name); i++ {
+		upper := unicode.IsUpper(rune(name[i]))
+		prevUpper := unicode.IsUpper(rune(name[i-1]))
+		// a lower-case letter after capitals starts a new word, unless it is
+		// the s of a plural such as IPs
+		nextLower := i+1 < len(name) && unicode.IsLower(rune(name[i+1])) &&
+			!(name[i+1] == 's' && (i+2 == len(name) || unicode.IsUpper(rune(name[i+2]))))
+		if upper && (!prevUpper || nextLower) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func init() {
+	describeCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the resource")
+	describeCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Label selector, when describing every resource of a type, e.g. app=web")
+	rootCmd.AddCommand(describeCmd)
+}
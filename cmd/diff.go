@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/diff"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff -f FILENAME",
+	Short: "Show how applying files would change the live objects",
+	Long: `Show, as a unified diff, how applying files would change the live objects.
+
+Each object is applied with a server dry run, which merges it into the live
+object as apply would, and the result is compared with the live object.
+Status and fields the API server sets, such as uid, resourceVersion,
+creationTimestamp and managedFields, are left out. Objects that do not exist yet show up as
+entirely added.
+
+The exit status is 0 when nothing would change, 1 when something would and
+2 when an object could not be compared.`,
+	Example: `  mykube diff -f pod.yaml
+  mykube diff -f manifests/ -R`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flagNamespace := ""
+		if cmd.Flags().Changed("namespace") {
+			flagNamespace = namespace
+		}
+
+		var manifests []manifest
+		failed := false
+		for _, path := range files {
+			found, err := readManifests(path, recursive)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				failed = true
+				continue
+			}
+			manifests = append(manifests, found...)
+		}
+		sortManifests(manifests)
+
+		c := getClient()
+		changed := false
+		for _, m := range manifests {
+			ns, err := manifestNamespace(m, flagNamespace)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				failed = true
+				continue
+			}
+			out, err := diffManifest(c, m, ns)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				failed = true
+				continue
+			}
+			if out != "" {
+				changed = true
+				fmt.Print(out)
+			}
+		}
+
+		if failed {
+			os.Exit(2)
+		}
+		if changed {
+			os.Exit(1)
+		}
+	},
+}
+
+// diffManifest returns the diff between the live object and the object
+// applying m would leave, or "" when they are the same.
+func diffManifest(c *client.Client, m manifest, namespace string) (string, error) {
+	var live json.RawMessage
+	if err := c.Get(m.res, namespace, m.name, &live); errors.Is(err, client.ErrNotFound) {
+		live = nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var merged []byte
+	if m.res.Kind == "Node" {
+		// Nodes are merged into by apply; the API server cannot dry-run
+		// an apply of them
+		data, err := nodeJSON(m)
+		if err != nil {
+			return "", err
+		}
+		merged = data
+		if live != nil {
+			if merged, err = patch.Merge(live, data); err != nil {
+				return "", fmt.Errorf("%s: %v", m.source, err)
+			}
+		}
+	} else {
+		var err error
+		merged, _, err = c.Apply(m.res, namespace, m.name, m.data, client.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        forceConflicts,
+			DryRun:       true,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	from, err := diffText(live)
+	if err != nil {
+		return "", err
+	}
+	to, err := diffText(merged)
+	if err != nil {
+		return "", err
+	}
+	path := resourcePrefix(m.res) + "/" + m.name
+	if m.res.Namespaced {
+		path = namespace + "/" + path
+	}
+	return diff.Unified("live/"+path, "merged/"+path, from, to), nil
+}
+
+// diffText renders an object as YAML for diffing, without the fields the
+// API server and the nodes set. A missing object renders as nothing.
+func diffText(obj []byte) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(obj, &object); err != nil {
+		return "", err
+	}
+	delete(object, "status")
+	delete(object, "pods") // the pods on a node
+	if meta, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"uid", "resourceVersion", "creationTimestamp", "managedFields"} {
+			delete(meta, field)
+		}
+	}
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func init() {
+	diffCmd.Flags().StringSliceVarP(&files, "filename", "f", nil, "Files, directories, URLs or - for stdin containing the resource definitions")
+	diffCmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "Read the directories given with -f recursively")
+	diffCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the resources, if the files do not set one")
+	diffCmd.Flags().StringVar(&fieldManager, "field-manager", "mykube", "Name of the manager that owns the applied fields")
+	diffCmd.Flags().BoolVar(&forceConflicts, "force-conflicts", false, "Show the diff even where other managers own the fields")
+	diffCmd.MarkFlagRequired("filename")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// dryRun is the --dry-run flag of the commands that write objects: none,
+// client to only print what would be sent, or server to have the API server
+// validate the change without persisting it.
+var dryRun string
+
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", `Must be "none", "client" or "server". client only prints what would be sent; server submits it for validation without persisting it`)
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = "client"
+}
+
+// checkDryRun validates --dry-run.
+func checkDryRun() error {
+	switch dryRun {
+	case "none", "client", "server":
+		return nil
+	}
+	return fmt.Errorf(`invalid --dry-run %q, must be "none", "client" or "server"`, dryRun)
+}
+
+// dryRunSuffix is appended to the outcome a dry run reports.
+func dryRunSuffix() string {
+	switch dryRun {
+	case "client":
+		return " (dry run)"
+	case "server":
+		return " (server dry run)"
+	}
+	return ""
+}
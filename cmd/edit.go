@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit (TYPE NAME | TYPE/NAME)",
+	Short: "Edit a resource in your editor",
+	Long: `Edit a resource as YAML in the editor named by MYKUBE_EDITOR or EDITOR,
+falling back to vi.
+
+When the editor exits the changes are sent as a merge patch. If the file
+cannot be parsed or the API server rejects the change, the file is reopened
+with the error at the top; save it unchanged or empty it to give up. The
+patch carries the resourceVersion the object was read at, so changes made
+by others in the meantime are not overwritten: the edit then fails and a
+copy of it is kept in a temporary file.`,
+	Example: `  mykube edit pod web
+  mykube edit deploy/web -n team-a
+  EDITOR="code --wait" mykube edit svc/web`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		res, name, err := resourceAndName(args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		ns := namespace
+		if ns == "" {
+			ns = "default"
+		}
+		if err := runEdit(getClient(), res, ns, name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+		}
+	},
+}
+
+const editHeader = `# Please edit the object below. Lines beginning with a '#' will be ignored,
+# and an empty file will abort the edit. If an error occurs while saving this
+# file will be reopened with the relevant failures.
+#
+`
+
+func runEdit(c *client.Client, res client.Resource, namespace, name string) error {
+	var live json.RawMessage
+	if err := c.Get(res, namespace, name, &live); err != nil {
+		return err
+	}
+	rendered, err := editableYAML(live)
+	if err != nil {
+		return err
+	}
+	original, err := yamlToJSON(rendered)
+	if err != nil {
+		return err
+	}
+	originalName, originalNamespace, _ := objectKey(original)
+
+	content, lastAttempt, problem := rendered, "", ""
+	for {
+		var text strings.Builder
+		text.WriteString(editHeader)
+		if problem != "" {
+			fmt.Fprintf(&text, "# %s %q was not valid:\n", res.Singular, name)
+			for _, line := range strings.Split(problem, "\n") {
+				fmt.Fprintf(&text, "# %s\n", line)
+			}
+			text.WriteString("#\n")
+		}
+		text.WriteString(content)
+
+		edited, err := openEditor(text.String())
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(edited) == "" || edited == rendered {
+			fmt.Println("Edit cancelled, no changes made.")
+			return nil
+		}
+		if edited == lastAttempt {
+			return fmt.Errorf("edit cancelled, no valid changes were saved")
+		}
+		content, lastAttempt = edited, edited
+
+		modified, err := yamlToJSON(edited)
+		if err != nil {
+			problem = err.Error()
+			continue
+		}
+		if problem = checkEdit(original, modified, originalName, originalNamespace); problem != "" {
+			continue
+		}
+
+		data, err := editPatch(original, modified)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			fmt.Println("Edit cancelled, no changes made.")
+			return nil
+		}
+
+		_, err = c.Patch(res, namespace, name, client.MergePatchType, data, client.PatchOptions{})
+		if errors.Is(err, client.ErrConflict) {
+			path, saveErr := saveEdit(edited)
+			if saveErr != nil {
+				return fmt.Errorf("%v\nyour changes could not be kept: %v", err, saveErr)
+			}
+			return fmt.Errorf("%v\nA copy of your changes has been stored to %q", err, path)
+		} else if err != nil {
+			problem = err.Error()
+			continue
+		}
+		fmt.Printf("✅ %s/%s edited\n", resourcePrefix(res), name)
+		return nil
+	}
+}
+
+// editableYAML renders an object as YAML in the field order the API server
+// sent, without the managed fields nobody should edit by hand.
+func editableYAML(obj []byte) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(obj, &doc); err != nil {
+		return "", err
+	}
+	if len(doc.Content) == 1 {
+		root := doc.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == "metadata" {
+				removeKey(root.Content[i+1], "managedFields")
+			}
+		}
+	}
+	// JSON decodes as flow style with quoted strings; print block style
+	resetStyle(&doc)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func removeKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// yamlToJSON converts an edited object, without its comment lines, to JSON.
+func yamlToJSON(text string) ([]byte, error) {
+	var object interface{}
+	if err := yaml.Unmarshal([]byte(text), &object); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+	if _, ok := object.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("the edited file must hold one object")
+	}
+	return json.Marshal(object)
+}
+
+// checkEdit rejects edits that turn the object into another one.
+func checkEdit(original, modified []byte, name, namespace string) string {
+	var before, after struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	json.Unmarshal(original, &before)
+	json.Unmarshal(modified, &after)
+	if before.APIVersion != after.APIVersion || before.Kind != after.Kind {
+		return "apiVersion and kind cannot be changed"
+	}
+	newName, newNamespace, err := objectKey(modified)
+	if err != nil {
+		return err.Error()
+	}
+	if newName != name {
+		return "the name cannot be changed"
+	}
+	if newNamespace != namespace {
+		return "the namespace cannot be changed"
+	}
+	return ""
+}
+
+// editPatch returns the merge patch of an edit, or nil when nothing
+// changed. It carries the resourceVersion the object was read at so that
+// the API server rejects it if the object changed since.
+func editPatch(original, modified []byte) ([]byte, error) {
+	data, err := patch.CreateMergePatch(original, modified)
+	if err != nil {
+		return nil, err
+	}
+	var p map[string]interface{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	var object struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(original, &object)
+	if rv := object.Metadata.ResourceVersion; rv != "" {
+		meta, _ := p["metadata"].(map[string]interface{})
+		if meta == nil {
+			meta = map[string]interface{}{}
+			p["metadata"] = meta
+		}
+		meta["resourceVersion"] = rv
+	}
+	return json.Marshal(p)
+}
+
+// openEditor lets the user edit text in a temporary file and returns the
+// result without its comment lines.
+func openEditor(text string) (string, error) {
+	file, err := os.CreateTemp("", "mykube-edit-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("MYKUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %v", editor, err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// saveEdit keeps an edit that could not be saved in a temporary file.
+func saveEdit(text string) (string, error) {
+	file, err := os.CreateTemp("", "mykube-edit-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(text); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the resource")
+	rootCmd.AddCommand(editCmd)
+}
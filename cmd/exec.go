@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	execContainer string
+	execStdin     bool
+	execTTY       bool
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec (POD | pod/POD) [-c CONTAINER] [-i] [-t] -- COMMAND [args...]",
+	Short: "Run a command in a pod's container",
+	Long: `Run a command in a pod's container. The session goes through the API
+server to the node server running the pod, over a WebSocket carrying stdin,
+stdout, stderr and terminal resizes.
+
+-i passes this terminal's input to the command and -t runs it in a
+terminal, which is what interactive shells need; -t is ignored when input is
+not a terminal. mykube exits with the command's exit code.`,
+	Example: `  mykube exec web -- ls /usr/share/nginx/html
+  mykube exec -it pod/web -c app -- sh
+  echo hello | mykube exec -i web -- cat`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.ArgsLenAtDash() != 1 {
+			fmt.Println("❌ give the pod, then -- and the command, e.g. mykube exec web -- ls")
+			os.Exit(1)
+		}
+		name, err := podArg(args[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		ns := namespace
+		if ns == "" {
+			ns = "default"
+		}
+
+		stdinFd := int(os.Stdin.Fd())
+		tty := execTTY
+		if tty && !term.IsTerminal(stdinFd) {
+			fmt.Fprintln(os.Stderr, "⚠️ Unable to use a TTY: input is not a terminal")
+			tty = false
+		}
+		opts := models.PodExecOptions{
+			Container: execContainer,
+			Command:   args[1:],
+			Stdin:     execStdin,
+			Stdout:    true,
+			Stderr:    !tty,
+			TTY:       tty,
+		}
+		streams := wsstream.ExecStreams{Stdout: os.Stdout, Stderr: os.Stderr}
+		if execStdin {
+			streams.Stdin = os.Stdin
+		}
+
+		restore := func() {}
+		if tty {
+			sizes := make(chan wsstream.TerminalSize, 1)
+			streams.Resize = sizes
+			stdoutFd := int(os.Stdout.Fd())
+			sendTerminalSize(stdoutFd, sizes)
+			stop := notifyResize(stdoutFd, sizes)
+			restore = stop
+			if execStdin {
+				state, err := term.MakeRaw(stdinFd)
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					os.Exit(1)
+				}
+				restore = func() {
+					stop()
+					term.Restore(stdinFd, state)
+				}
+			}
+		}
+
+		err = getClient().Exec(context.Background(), ns, name, opts, streams)
+		restore()
+		var exitErr *wsstream.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// sendTerminalSize queues the size of the terminal fd for sizes, replacing
+// a size not sent yet.
+func sendTerminalSize(fd int, sizes chan wsstream.TerminalSize) {
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		return
+	}
+	select {
+	case <-sizes:
+	default:
+	}
+	sizes <- wsstream.TerminalSize{Width: uint16(width), Height: uint16(height)}
+}
+
+func init() {
+	execCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pod")
+	execCmd.Flags().StringVarP(&execContainer, "container", "c", "", "Container to run in; defaults to the pod's first container")
+	execCmd.Flags().BoolVarP(&execStdin, "stdin", "i", false, "Pass this terminal's input to the command")
+	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Run the command in a terminal")
+	rootCmd.AddCommand(execCmd)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/openapi"
+	"github.com/spf13/cobra"
+)
+
+var explainRecursive bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain RESOURCE[.FIELD...]",
+	Short: "Describe the fields of a resource",
+	Long: `Describe a resource or one of its fields, using the OpenAPI document the
+API server publishes. Fields are given as a dotted path; lists are walked
+into automatically.`,
+	Example: `  mykube explain pods
+  mykube explain pod.spec.containers
+  mykube explain svc.spec.ports.nodePort
+  mykube explain pods --recursive`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := strings.Split(args[0], ".")
+
+		c := getClient()
+		lists, err := c.ServerResources()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		groupVersion, resource, ok := findResource(lists, path[0])
+		if !ok {
+			fmt.Printf("❌ the server doesn't have a resource type %q\n", path[0])
+			return
+		}
+		group := ""
+		if i := strings.Index(groupVersion, "/"); i >= 0 {
+			group = groupVersion[:i]
+		}
+
+		doc, err := c.OpenAPI()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		_, schema, ok := doc.SchemaForKind(group, resource.Kind)
+		if !ok {
+			fmt.Printf("❌ no schema for %s\n", resource.Kind)
+			return
+		}
+
+		field, fieldName := doc.Resolve(schema), ""
+		for _, name := range path[1:] {
+			next, ok := explainElem(doc, field).Properties[name]
+			if !ok {
+				fmt.Printf("❌ field %q does not exist\n", name)
+				return
+			}
+			field, fieldName = next, name
+		}
+
+		fmt.Printf("KIND:     %s\n", resource.Kind)
+		fmt.Printf("VERSION:  %s\n\n", groupVersion)
+		if fieldName != "" {
+			fmt.Printf("FIELD:    %s <%s>\n\n", fieldName, doc.TypeString(field))
+		}
+
+		resolved := doc.Resolve(field)
+		description := resolved.Description
+		if description == "" {
+			// Fall back to the description of the list item or map value type
+			description = explainElem(doc, resolved).Description
+		}
+		fmt.Println("DESCRIPTION:")
+		printWrapped(description, "     ")
+
+		properties := explainElem(doc, resolved).Properties
+		if len(properties) == 0 {
+			return
+		}
+		fmt.Println("\nFIELDS:")
+		if explainRecursive {
+			printFieldTree(doc, properties, "   ", map[*openapi.Schema]bool{})
+			return
+		}
+		for _, name := range sortedFields(properties) {
+			fmt.Printf("   %s\t<%s>\n", name, doc.TypeString(properties[name]))
+			printWrapped(doc.Resolve(properties[name]).Description, "     ")
+			fmt.Println()
+		}
+	},
+}
+
+// findResource matches name against the plural, singular, kind and short
+// names the server reports.
+func findResource(lists []models.APIResourceList, name string) (string, models.APIResource, bool) {
+	name = strings.ToLower(name)
+	for _, list := range lists {
+		for _, r := range list.Resources {
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			if name == r.Name || name == r.SingularName || name == strings.ToLower(r.Kind) || isShortName(r, name) {
+				return list.GroupVersion, r, true
+			}
+		}
+	}
+	return "", models.APIResource{}, false
+}
+
+func isShortName(r models.APIResource, name string) bool {
+	for _, short := range r.ShortNames {
+		if short == name {
+			return true
+		}
+	}
+	return false
+}
+
+// explainElem steps into list items and map values, so that the fields of
+// []Container are those of Container.
+func explainElem(doc *openapi.Document, s *openapi.Schema) *openapi.Schema {
+	for {
+		s = doc.Resolve(s)
+		switch {
+		case s == nil:
+			return &openapi.Schema{}
+		case s.Items != nil:
+			s = s.Items
+		case s.AdditionalProperties != nil:
+			s = s.AdditionalProperties
+		default:
+			return s
+		}
+	}
+}
+
+func printFieldTree(doc *openapi.Document, properties map[string]*openapi.Schema, indent string, seen map[*openapi.Schema]bool) {
+	for _, name := range sortedFields(properties) {
+		fmt.Printf("%s%s\t<%s>\n", indent, name, doc.TypeString(properties[name]))
+		elem := explainElem(doc, properties[name])
+		if seen[elem] {
+			continue
+		}
+		seen[elem] = true
+		printFieldTree(doc, elem.Properties, indent+"  ", seen)
+		delete(seen, elem)
+	}
+}
+
+func sortedFields(properties map[string]*openapi.Schema) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printWrapped prints text wrapped at 80 columns.
+func printWrapped(text, indent string) {
+	if text == "" {
+		fmt.Println(indent + "<empty>")
+		return
+	}
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if len(line)+len(word)+1 > 80 && line != indent {
+			fmt.Println(line)
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += word
+	}
+	fmt.Println(line)
+}
+
+func init() {
+	explainCmd.Flags().BoolVar(&explainRecursive, "recursive", false, "List the names and types of all nested fields")
+	rootCmd.AddCommand(explainCmd)
+}
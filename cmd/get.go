@@ -1,130 +1,188 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
-	"text/tabwriter"
+	"strings"
 	"time"
 
 	"github.com/selimhanmrl/Own-Kubernetes/client"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
 	"github.com/spf13/cobra"
 )
 
 var namespace string   // Namespace flag
 var allNamespaces bool // Add -A flag
 
+// Selector and paging flags shared by the list commands
+var labelSelector string
+var fieldSelector string
+var chunkSize int64
+
+func listOptions() client.ListOptions {
+	return client.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector, Limit: chunkSize}
+}
+
+func addListFlags(cmd *cobra.Command, labelExample, fieldExample string) {
+	cmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Label selector, e.g. "+labelExample)
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Field selector, e.g. "+fieldExample)
+	cmd.Flags().Int64Var(&chunkSize, "chunk-size", 500, "Fetch large lists in pages of this size, 0 for a single request")
+}
+
 var getCmd = &cobra.Command{
-	Use:   "get pods",
-	Short: "Get a list of pods in a namespace or all namespaces",
+	Use:   "get (TYPE[,TYPE...] [NAME...] | TYPE/NAME...)",
+	Short: "List resources, or show the named ones",
+	Long: `List resources of any type the API server serves, or show the named ones.
+
+TYPE is a plural, singular, kind or short name, e.g. pods, pod, Pod or po;
+run api-resources to see them all. Without names, every object of the type
+in the namespace is listed.`,
+	Example: `  mykube get pods
+  mykube get po web -o yaml
+  mykube get svc/web deploy/web
+  mykube get pods,services -A -l app=web
+  mykube get nodes -o wide`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
-
-		var pods []models.Pod
-		var err error
+		runGet(args)
+	},
+}
 
-		if allNamespaces {
-			pods, err = c.ListPods("")
-		} else {
-			if namespace == "" {
-				namespace = "default"
-			}
-			pods, err = c.ListPods(namespace)
+func runGet(args []string) {
+	targets, err := parseResourceArgs(args)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	c := getClient()
+	tables := resourceTables(c)
+	for i, target := range targets {
+		if i > 0 && printOptions.IsTable() {
+			fmt.Println()
 		}
-
-		if err != nil {
-			fmt.Printf("Failed to list pods: %v\n", err)
-			return
+		if err := getResource(c, tables, target); err != nil {
+			fmt.Printf("❌ %v\n", err)
 		}
+	}
+}
 
-		if len(pods) == 0 {
-			if allNamespaces {
-				fmt.Println("No pods found in any namespace.")
-			} else {
-				fmt.Printf("No pods found in namespace '%s'.\n", namespace)
-			}
-			return
+// getResource prints the named objects of one resource, or all of them.
+func getResource(c *client.Client, tables map[string]resourceTable, target resourceTarget) error {
+	res := target.res
+	ns := namespace
+	if ns == "" {
+		ns = "default"
+	}
+
+	var objects []json.RawMessage
+	if len(target.names) == 0 {
+		listNamespace := ns
+		if allNamespaces || !res.Namespaced {
+			listNamespace = ""
 		}
-
-		// Sort pods by namespace if listing all namespaces
-		if allNamespaces {
-			sort.Slice(pods, func(i, j int) bool {
-				return pods[i].Metadata.Namespace < pods[j].Metadata.Namespace
-			})
+		var err error
+		if objects, err = c.List(res, listNamespace, listOptions()); err != nil {
+			return err
 		}
-
-		// Create a tabular writer for output
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		if allNamespaces {
-			fmt.Fprintln(w, "NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODEPORT\tRESOURCES")
-		} else {
-			fmt.Fprintln(w, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODEPORT\tRESOURCES")
+	} else {
+		for _, name := range target.names {
+			var obj json.RawMessage
+			if err := c.Get(res, ns, name, &obj); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				continue
+			}
+			objects = append(objects, obj)
 		}
+		if len(objects) == 0 {
+			return nil
+		}
+	}
+
+	empty := fmt.Sprintf("No %s found in namespace '%s'.", res.Name, ns)
+	if !res.Namespaced {
+		empty = fmt.Sprintf("No %s found.", res.Name)
+	} else if allNamespaces {
+		empty = fmt.Sprintf("No %s found in any namespace.", res.Name)
+	}
+
+	print, ok := tables[res.Name]
+	if !ok {
+		print = table(objectColumns, objectRow, nil)
+	}
+	printOptions.Single = len(target.names) == 1
+	return print(res, objects, empty)
+}
 
-		for _, pod := range pods {
-			ready := fmt.Sprintf("%d/%d", len(pod.Spec.Containers), len(pod.Spec.Containers))
-			restarts := "0"
-
-			age := "unknown"
-			if pod.Status.StartTime != "" {
-				if t, err := time.Parse(time.RFC3339, pod.Status.StartTime); err == nil {
-					duration := time.Since(t).Round(time.Second)
-					age = duration.String()
-				}
-			}
+// sortPods lists pods by namespace when listing all namespaces.
+func sortPods(pods []models.Pod) {
+	if allNamespaces {
+		sort.SliceStable(pods, func(i, j int) bool {
+			return pods[i].Metadata.Namespace < pods[j].Metadata.Namespace
+		})
+	}
+}
 
-			// Get NodePort if assigned
-			nodePort := "-"
-			if port, exists := c.GetAssignedNodePort(pod.Metadata.Name); exists {
-				nodePort = fmt.Sprintf("%d", port)
-			}
+var podColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "READY"},
+	{Name: "STATUS"},
+	{Name: "RESTARTS"},
+	{Name: "AGE"},
+	{Name: "NODEPORT"},
+	{Name: "IP", Wide: true},
+	{Name: "NODE", Wide: true},
+	{Name: "RESOURCES", Wide: true},
+}
 
-			resourceInfo := ""
-			for _, container := range pod.Spec.Containers {
-				resourceInfo += fmt.Sprintf("[%s: Requests(cpu=%s, mem=%s), Limits(cpu=%s, mem=%s)] ",
-					container.Name,
-					container.Resources.Requests["cpu"],
-					container.Resources.Requests["memory"],
-					container.Resources.Limits["cpu"],
-					container.Resources.Limits["memory"],
-				)
-			}
+func podRow(c *client.Client, pod models.Pod) []string {
+	ready := fmt.Sprintf("%d/%d", len(pod.Spec.Containers), len(pod.Spec.Containers))
+	restarts := "0"
 
-			if allNamespaces {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-					pod.Metadata.Namespace,
-					pod.Metadata.Name,
-					ready,
-					pod.Status.Phase,
-					restarts,
-					age,
-					nodePort,
-					resourceInfo,
-				)
-			} else {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-					pod.Metadata.Name,
-					ready,
-					pod.Status.Phase,
-					restarts,
-					age,
-					nodePort,
-					resourceInfo,
-				)
-			}
+	age := "unknown"
+	if pod.Status.StartTime != "" {
+		if t, err := time.Parse(time.RFC3339, pod.Status.StartTime); err == nil {
+			duration := time.Since(t).Round(time.Second)
+			age = duration.String()
 		}
-
-		w.Flush()
-	},
+	}
+
+	// Get NodePort if assigned
+	nodePort := "-"
+	if port, exists := c.GetAssignedNodePort(pod.Metadata.Name); exists {
+		nodePort = fmt.Sprintf("%d", port)
+	}
+
+	resourceInfo := ""
+	for _, container := range pod.Spec.Containers {
+		resourceInfo += fmt.Sprintf("[%s: Requests(cpu=%s, mem=%s), Limits(cpu=%s, mem=%s)] ",
+			container.Name,
+			container.Resources.Requests["cpu"],
+			container.Resources.Requests["memory"],
+			container.Resources.Limits["cpu"],
+			container.Resources.Limits["memory"],
+		)
+	}
+
+	return []string{
+		pod.Metadata.Name,
+		ready,
+		pod.Status.Phase,
+		restarts,
+		age,
+		nodePort,
+		valueOrNone(pod.Status.PodIP),
+		valueOrNone(pod.Spec.NodeName),
+		strings.TrimSpace(resourceInfo),
+	}
 }
 
 func init() {
 	// Add namespace and all-namespaces flags to the get command
-	getCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace to filter pods")
-	getCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List pods across all namespaces")
+	getCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the resources")
+	getCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List resources across all namespaces")
+	addListFlags(getCmd, "app=web,tier in (frontend,cache)", "spec.nodeName=worker1,status.phase=Running")
+	addPrintFlags(getCmd)
 	rootCmd.AddCommand(getCmd)
 }
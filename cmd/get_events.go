@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
+)
+
+// sortEvents lists events oldest first.
+func sortEvents(events []models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+	})
+}
+
+var eventColumns = []printers.Column{
+	{Name: "LAST SEEN"},
+	{Name: "TYPE"},
+	{Name: "REASON"},
+	{Name: "OBJECT"},
+	{Name: "SOURCE", Wide: true},
+	{Name: "MESSAGE"},
+	{Name: "FIRST SEEN", Wide: true},
+	{Name: "COUNT", Wide: true},
+	{Name: "NAME", Wide: true},
+}
+
+func eventRow(event models.Event) []string {
+	source := event.Source.Component
+	if event.Source.Host != "" {
+		source += ", " + event.Source.Host
+	}
+
+	return []string{
+		lastSeen(event),
+		event.Type,
+		event.Reason,
+		event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		source,
+		event.Message,
+		formatAge(time.Since(event.FirstTimestamp)),
+		fmt.Sprintf("%d", event.Count),
+		event.Metadata.Name,
+	}
+}
+
+// lastSeen is how long ago an event last happened, with how often and
+// since when for repeated events, e.g. "2m (x4 over 10m)".
+func lastSeen(event models.Event) string {
+	seen := formatAge(time.Since(event.LastTimestamp))
+	if event.Count > 1 {
+		seen += fmt.Sprintf(" (x%d over %s)", event.Count, formatAge(time.Since(event.FirstTimestamp)))
+	}
+	return seen
+}
+
+// formatAge renders a duration in its largest whole unit, e.g. 45s, 3m,
+// 5h or 2d.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
+)
+
+var deploymentColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "READY"},
+	{Name: "UP-TO-DATE"},
+	{Name: "AVAILABLE"},
+	{Name: "AGE"},
+	{Name: "CONTAINERS", Wide: true},
+	{Name: "IMAGES", Wide: true},
+	{Name: "SELECTOR", Wide: true},
+}
+
+func deploymentRow(deployment models.Deployment) []string {
+	containers, images := templateContainers(deployment.Spec.Template)
+	return []string{
+		deployment.Metadata.Name,
+		fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, deployment.Spec.Replicas),
+		fmt.Sprintf("%d", deployment.Status.UpdatedReplicas),
+		fmt.Sprintf("%d", deployment.Status.AvailableReplicas),
+		ageSince(deployment.Metadata.CreationTimestamp),
+		containers,
+		images,
+		valueOrNone(selectorString(deployment.Spec.Selector.MatchLabels)),
+	}
+}
+
+var replicaSetColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "DESIRED"},
+	{Name: "CURRENT"},
+	{Name: "READY"},
+	{Name: "AGE"},
+	{Name: "CONTAINERS", Wide: true},
+	{Name: "IMAGES", Wide: true},
+	{Name: "SELECTOR", Wide: true},
+}
+
+func replicaSetRow(rs models.ReplicaSet) []string {
+	containers, images := templateContainers(rs.Spec.Template)
+	return []string{
+		rs.Metadata.Name,
+		fmt.Sprintf("%d", rs.Spec.Replicas),
+		fmt.Sprintf("%d", rs.Status.Replicas),
+		fmt.Sprintf("%d", rs.Status.ReadyReplicas),
+		ageSince(rs.Metadata.CreationTimestamp),
+		containers,
+		images,
+		valueOrNone(selectorString(rs.Spec.Selector.MatchLabels)),
+	}
+}
+
+// templateContainers lists the container names and images of a pod
+// template, comma separated.
+func templateContainers(template models.PodTemplate) (string, string) {
+	var names, images []string
+	for _, container := range template.Spec.Containers {
+		names = append(names, container.Name)
+		images = append(images, container.Image)
+	}
+	return strings.Join(names, ","), strings.Join(images, ",")
+}
+
+var namespaceColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "STATUS"},
+	{Name: "AGE"},
+}
+
+func namespaceRow(ns models.Namespace) []string {
+	return []string{ns.Metadata.Name, ns.Status.Phase, ageSince(ns.Metadata.CreationTimestamp)}
+}
+
+var leaseColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "HOLDER"},
+	{Name: "AGE"},
+}
+
+func leaseRow(lease models.Lease) []string {
+	return []string{lease.Metadata.Name, lease.Spec.HolderIdentity, ageSince(lease.Metadata.CreationTimestamp)}
+}
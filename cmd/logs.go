@@ -1,59 +1,243 @@
 package cmd
 
 import (
-    "fmt"
-    "os/exec"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-    "github.com/selimhanmrl/Own-Kubernetes/models"
-    "github.com/selimhanmrl/Own-Kubernetes/store"
-    "github.com/spf13/cobra"
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logContainer   string
+	logFollow      bool
+	logTail        int64
+	logSince       time.Duration
+	logSinceTime   string
+	logTimestamps  bool
+	logPrevious    bool
+	logPrefix      bool
+	logMaxRequests int
 )
 
 var logsCmd = &cobra.Command{
-    Use:   "logs [pod-name]",
-    Short: "Fetch logs for a specific pod",
-    Args:  cobra.ExactArgs(1), // Ensure exactly one argument is provided
-    Run: func(cmd *cobra.Command, args []string) {
-        podName := args[0]
-
-        if namespace == "" {
-            namespace = "default" // Default to 'default' namespace
-        }
-
-        // Find the pod by name in the specified namespace
-        pods := store.ListPods(namespace)
-        var podFound bool
-        var pod models.Pod
-        for _, p := range pods {
-            if p.Metadata.Name == podName {
-                pod = p
-                podFound = true
-                break
-            }
-        }
-
-        if !podFound {
-            fmt.Printf("❌ Pod with name '%s' not found in namespace '%s'.\n", podName, namespace)
-            return
-        }
-
-        // Generate the container name
-        containerName := fmt.Sprintf("%s-%s-%s", pod.Metadata.Name, pod.Spec.Containers[0].Name, pod.Metadata.UID[:8])
-
-        // Fetch logs using `docker logs`
-        out, err := exec.Command("docker", "logs", containerName).CombinedOutput()
-        if err != nil {
-            fmt.Printf("❌ Failed to fetch logs for pod '%s': %v\n", podName, err)
-            return
-        }
-
-        fmt.Printf("📄 Logs for pod '%s' in namespace '%s':\n", podName, namespace)
-        fmt.Println(string(out))
-    },
+	Use:   "logs (POD | pod/POD | -l SELECTOR)",
+	Short: "Print the logs of a pod's container",
+	Long: `Print the logs of a pod's container, streamed by the node server running
+the pod through the API server.
+
+With -l the logs of every matching pod are printed, one pod after another,
+or interleaved line by line when following; each line is then prefixed with
+its pod and container unless --prefix=false is given. Pods are not
+restarted, so --previous shows the container once it has exited.`,
+	Example: `  mykube logs web
+  mykube logs pod/web -c app --tail 20 --timestamps
+  mykube logs web -f --since 10m
+  mykube logs -l app=web -f`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, err := logOptions()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		ns := namespace
+		if ns == "" {
+			ns = "default"
+		}
+
+		c := getClient()
+		pods, err := logPods(c, args, ns)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if opts.Follow && len(pods) > logMaxRequests {
+			fmt.Printf("❌ following %d log streams, but at most %d are allowed; use --max-log-requests to raise the limit\n",
+				len(pods), logMaxRequests)
+			return
+		}
+		prefix := logPrefix
+		if !cmd.Flags().Changed("prefix") {
+			prefix = labelSelector != ""
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if !streamLogs(ctx, c, pods, opts, prefix) {
+			os.Exit(1)
+		}
+	},
+}
+
+// logOptions builds the options of the log requests from the flags.
+func logOptions() (models.PodLogOptions, error) {
+	opts := models.PodLogOptions{
+		Container:  logContainer,
+		Follow:     logFollow,
+		Previous:   logPrevious,
+		Timestamps: logTimestamps,
+	}
+	if logTail >= 0 {
+		opts.TailLines = &logTail
+	}
+	if logSince != 0 && logSinceTime != "" {
+		return opts, fmt.Errorf("use only one of --since and --since-time")
+	}
+	if logSince < 0 {
+		return opts, fmt.Errorf("--since must be positive")
+	}
+	if logSince > 0 {
+		seconds := int64(math.Ceil(logSince.Seconds()))
+		opts.SinceSeconds = &seconds
+	}
+	if logSinceTime != "" {
+		t, err := time.Parse(time.RFC3339, logSinceTime)
+		if err != nil {
+			return opts, fmt.Errorf("--since-time %q is not an RFC 3339 time such as 2024-05-01T10:00:00Z", logSinceTime)
+		}
+		opts.SinceTime = &t
+	}
+	return opts, nil
+}
+
+// logPods returns the pod named in args, or the pods -l selects, by name.
+func logPods(c *client.Client, args []string, namespace string) ([]models.Pod, error) {
+	switch {
+	case len(args) == 1 && labelSelector != "":
+		return nil, fmt.Errorf("give either a pod name or -l, not both")
+	case len(args) == 1:
+		name, err := podArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		var pod models.Pod
+		if err := c.Get(podResource, namespace, name, &pod); err != nil {
+			return nil, err
+		}
+		return []models.Pod{pod}, nil
+	case labelSelector != "":
+		pods, err := c.ListPodsWithOptions(namespace, client.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, err
+		}
+		if len(pods) == 0 {
+			return nil, fmt.Errorf("no pods in namespace %s match %q", namespace, labelSelector)
+		}
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Metadata.Name < pods[j].Metadata.Name })
+		return pods, nil
+	}
+	return nil, fmt.Errorf("a pod name or -l is required")
+}
+
+var podResource, _ = client.LookupResource("pods")
+
+// podArg returns the pod name in an argument that is a name or a
+// TYPE/NAME of a pod.
+func podArg(arg string) (string, error) {
+	typeName, name, ok := strings.Cut(arg, "/")
+	if !ok {
+		return arg, nil
+	}
+	if res, found := client.LookupResource(typeName); !found || res.Name != "pods" {
+		return "", fmt.Errorf("%q is not a pod", arg)
+	}
+	if name == "" {
+		return "", fmt.Errorf("a pod name is required")
+	}
+	return name, nil
+}
+
+// streamLogs prints the logs of pods to stdout, one pod after another or,
+// when following, all at once. Lines are written whole so those of
+// different pods never mix. It reports whether every log could be read.
+func streamLogs(ctx context.Context, c *client.Client, pods []models.Pod, opts models.PodLogOptions, prefix bool) bool {
+	var mu sync.Mutex
+	ok := true
+	read := func(pod models.Pod) {
+		if err := printLog(ctx, c, pod, opts, prefix, &mu); err != nil {
+			mu.Lock()
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			ok = false
+			mu.Unlock()
+		}
+	}
+
+	if !opts.Follow {
+		for _, pod := range pods {
+			read(pod)
+		}
+		return ok
+	}
+	var wg sync.WaitGroup
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod models.Pod) {
+			defer wg.Done()
+			read(pod)
+		}(pod)
+	}
+	wg.Wait()
+	return ok
+}
+
+func printLog(ctx context.Context, c *client.Client, pod models.Pod, opts models.PodLogOptions, prefix bool, mu *sync.Mutex) error {
+	stream, err := c.PodLogs(ctx, pod.Metadata.Namespace, pod.Metadata.Name, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	label := ""
+	if prefix {
+		container := opts.Container
+		if container == "" && len(pod.Spec.Containers) > 0 {
+			container = pod.Spec.Containers[0].Name
+		}
+		label = fmt.Sprintf("[pod/%s/%s] ", pod.Metadata.Name, container)
+	}
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if prefix && !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			mu.Lock()
+			fmt.Print(label + line)
+			mu.Unlock()
+		}
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("log of pod %q ended early: %v", pod.Metadata.Name, err)
+		}
+	}
 }
 
 func init() {
-    // Add namespace flag to the logs command
-    logsCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pod")
-    rootCmd.AddCommand(logsCmd)
-}
\ No newline at end of file
+	logsCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pods")
+	logsCmd.Flags().StringVarP(&logContainer, "container", "c", "", "Container to print the logs of; defaults to the pod's first container")
+	logsCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Keep streaming the logs as they are written")
+	logsCmd.Flags().Int64Var(&logTail, "tail", -1, "Number of recent lines to print, -1 for all")
+	logsCmd.Flags().DurationVar(&logSince, "since", 0, "Only print lines newer than a duration, e.g. 5s, 2m or 3h")
+	logsCmd.Flags().StringVar(&logSinceTime, "since-time", "", "Only print lines written after an RFC 3339 time")
+	logsCmd.Flags().BoolVar(&logTimestamps, "timestamps", false, "Prefix each line with the time it was written")
+	logsCmd.Flags().BoolVarP(&logPrevious, "previous", "p", false, "Print the logs of the last terminated instance of the container")
+	logsCmd.Flags().StringVarP(&labelSelector, "selector", "l", "", "Print the logs of every pod matching a label selector, e.g. app=web")
+	logsCmd.Flags().BoolVar(&logPrefix, "prefix", false, "Prefix each line with its pod and container (default true with -l)")
+	logsCmd.Flags().IntVar(&logMaxRequests, "max-log-requests", 5, "Maximum number of logs to follow at once with -l")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is one object read from a file, with the resource its kind
+// belongs to.
+type manifest struct {
+	res       client.Resource
+	name      string
+	namespace string
+	data      []byte // the document as YAML
+	source    string // file the object came from
+}
+
+// readManifests reads every object in a YAML or JSON source: a file, - for
+// stdin, an http(s):// or file:// URL, or a directory, whose .yaml, .yml and
+// .json files are read in name order, descending into subdirectories when
+// recursive is set. YAML sources may hold several documents separated by ---.
+func readManifests(path string, recursive bool) ([]manifest, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %v", err)
+		}
+		return decodeManifests(data, "stdin")
+	}
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return readManifestURL(path)
+	}
+	if strings.HasPrefix(path, "file://") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %s: %v", path, err)
+		}
+		path = u.Path
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		return decodeManifests(data, path)
+	}
+
+	var manifests []manifest
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		found, err := decodeManifests(data, file)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return manifests, nil
+}
+
+func readManifestURL(rawURL string) ([]manifest, error) {
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %v", rawURL, err)
+	}
+	return decodeManifests(data, rawURL)
+}
+
+func decodeManifests(data []byte, source string) ([]manifest, error) {
+	var manifests []manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for i := 1; ; i++ {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return manifests, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", source, i, err)
+		}
+
+		var object struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Name       string `yaml:"name"` // nodes have no metadata
+			Metadata   struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := doc.Decode(&object); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", source, i, err)
+		}
+		if object.Metadata.Name == "" {
+			object.Metadata.Name = object.Name
+		}
+		if object.Kind == "" && object.Metadata.Name == "" {
+			continue // an empty document, e.g. after a trailing ---
+		}
+
+		res, ok := client.LookupKind(object.APIVersion, object.Kind)
+		if !ok {
+			return nil, fmt.Errorf("%s: document %d: unsupported kind %q", source, i, object.Kind)
+		}
+		if object.Metadata.Name == "" {
+			return nil, fmt.Errorf("%s: document %d: metadata.name is required", source, i)
+		}
+		out, err := yaml.Marshal(&doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", source, i, err)
+		}
+		manifests = append(manifests, manifest{
+			res:       res,
+			name:      object.Metadata.Name,
+			namespace: object.Metadata.Namespace,
+			data:      out,
+			source:    source,
+		})
+	}
+}
+
+// json returns the object as JSON.
+func (m manifest) json() ([]byte, error) {
+	var object interface{}
+	if err := yaml.Unmarshal(m.data, &object); err != nil {
+		return nil, fmt.Errorf("%s: %v", m.source, err)
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", m.source, err)
+	}
+	return data, nil
+}
+
+// applyOrder is the order kinds are applied in, so that objects exist before
+// the objects that refer to them: namespaces before what lives in them, roles
+// before their bindings, and services before the workloads behind them.
+// Other kinds come last.
+var applyOrder = []string{
+	"namespaces", "nodes",
+	"clusterroles", "clusterrolebindings", "roles", "rolebindings",
+	"services", "deployments", "replicasets", "pods",
+}
+
+// sortManifests sorts manifests into applyOrder, keeping the order of the
+// files within a kind.
+func sortManifests(manifests []manifest) {
+	rank := func(m manifest) int {
+		for i, name := range applyOrder {
+			if m.res.Name == name {
+				return i
+			}
+		}
+		return len(applyOrder)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return rank(manifests[i]) < rank(manifests[j])
+	})
+}
+
+// manifestNamespace is the namespace an object from a file goes to: the
+// one in the file, which must agree with flagNamespace, the --namespace
+// given on the command line, when both are set. Objects without either go
+// to the namespace of the context in use.
+func manifestNamespace(m manifest, flagNamespace string) (string, error) {
+	if !m.res.Namespaced {
+		return "", nil
+	}
+	if flagNamespace != "" {
+		if m.namespace != "" && m.namespace != flagNamespace {
+			return "", fmt.Errorf("the namespace of %s/%s (%s) does not match --namespace (%s)",
+				m.res.Singular, m.name, m.namespace, flagNamespace)
+		}
+		return flagNamespace, nil
+	}
+	if m.namespace == "" {
+		return contextNamespace, nil
+	}
+	return m.namespace, nil
+}
@@ -1,38 +1,39 @@
 package main
 
 import (
-	"log"
+	"context"
 	"os"
 
 	"github.com/selimhanmrl/Own-Kubernetes/agent"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/config"
+	"github.com/spf13/pflag"
 )
 
 func main() {
-	nodeName := os.Getenv("NODE_NAME")
-	if nodeName == "" {
-		log.Fatal("NODE_NAME environment variable is required")
-	}
-
-	nodeIP := os.Getenv("NODE_IP")
-	if nodeIP == "" {
-		log.Fatal("NODE_IP environment variable is required")
-	}
+	component.Exit("node agent", run())
+}
 
-	apiHost := os.Getenv("API_HOST")
-	if apiHost == "" {
-		apiHost = "localhost"
+// run starts the agent from NODE_NAME, NODE_IP, the API_* variables and an
+// optional NodeAgentConfiguration file, and runs it until SIGTERM.
+func run() error {
+	cfg := config.DefaultNodeAgentConfiguration()
+	fs := pflag.NewFlagSet("node-agent", pflag.ContinueOnError)
+	config.AddFlags(fs)
+	if help, err := config.ParseFlags(fs, os.Args[1:]); help || err != nil {
+		return err
 	}
-
-	apiPort := os.Getenv("API_PORT")
-	if apiPort == "" {
-		apiPort = "8080"
+	if printed, err := config.Load(fs, cfg, os.Stdout); printed || err != nil {
+		return err
 	}
 
-	nodeAgent := agent.NewNodeAgent(nodeName, nodeIP, apiHost, apiPort)
-	if err := nodeAgent.Start(); err != nil {
-		log.Fatalf("Failed to start node agent: %v", err)
+	// Credentials should identify the agent as node:<NODE_NAME>
+	nodeAgent, err := agent.NewNodeAgent(cfg.NodeName, cfg.NodeIP, cfg.ClientConnection.ClientConfig(),
+		agent.Intervals{Heartbeat: cfg.HeartbeatInterval, PodSync: cfg.PodSyncInterval})
+	if err != nil {
+		return err
 	}
-
-	// Keep the agent running
-	select {}
+	runner := component.NewRunner("node agent", cfg.Serving.ShutdownTimeout)
+	runner.Go("node agent", nodeAgent.Run)
+	return runner.Run(context.Background())
 }
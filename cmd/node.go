@@ -2,13 +2,11 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strings"
-	"text/tabwriter"
 	"time"
 
-	"github.com/selimhanmrl/Own-Kubernetes/client"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
 	"github.com/spf13/cobra"
 )
 
@@ -31,10 +29,7 @@ var registerNodeCmd = &cobra.Command{
 		nodeName := args[0]
 
 		// Create client
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
+		c := getClient()
 
 		// Create node object
 		node := models.Node{
@@ -65,44 +60,48 @@ var registerNodeCmd = &cobra.Command{
 
 var getNodesCmd = &cobra.Command{
 	Use:   "get nodes",
-	Short: "List all nodes",
+	Short: "List all nodes (same as get nodes)",
 	Run: func(cmd *cobra.Command, args []string) {
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
-
-		nodes, err := c.ListNodes()
-		if err != nil {
-			fmt.Printf("Failed to list nodes: %v\n", err)
-			return
-		}
-
-		if len(nodes) == 0 {
-			fmt.Println("No nodes found.")
-			return
+		if len(args) > 0 && args[0] == "nodes" {
+			args = args[1:]
 		}
+		runGet(append([]string{"nodes"}, args...))
+	},
+}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "NAME\tSTATUS\tIP\tPODS\tAGE")
-
-		for _, node := range nodes {
-			age := "unknown"
-			if !node.Status.LastHeartbeat.IsZero() {
-				age = time.Since(node.Status.LastHeartbeat).Round(time.Second).String()
-			}
+var nodeColumns = []printers.Column{
+	{Name: "NAME"},
+	{Name: "STATUS"},
+	{Name: "IP"},
+	{Name: "PODS"},
+	{Name: "AGE"},
+	{Name: "CPU", Wide: true},
+	{Name: "MEMORY", Wide: true},
+}
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-				node.Name,
-				node.Status.Phase,
-				node.IP,
-				len(node.Pods),
-				age,
-			)
-		}
+func nodeRow(node models.Node) []string {
+	age := "unknown"
+	if !node.Status.LastHeartbeat.IsZero() {
+		age = time.Since(node.Status.LastHeartbeat).Round(time.Second).String()
+	}
+
+	return []string{
+		node.Name,
+		node.Status.Phase,
+		node.IP,
+		fmt.Sprintf("%d", len(node.Pods)),
+		age,
+		capacity(node, "cpu"),
+		capacity(node, "memory"),
+	}
+}
 
-		w.Flush()
-	},
+// capacity is the node's reported capacity of a resource, or <none>.
+func capacity(node models.Node, name string) string {
+	if q, ok := node.Status.Capacity[name]; ok {
+		return q.String()
+	}
+	return "<none>"
 }
 
 var createNodeCmd = &cobra.Command{
@@ -113,10 +112,7 @@ var createNodeCmd = &cobra.Command{
 		nodeName := args[0]
 
 		// Create client
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
+		c := getClient()
 
 		// Create and register node
 		node := models.Node{
@@ -155,8 +151,9 @@ var createNodeCmd = &cobra.Command{
 
 		fmt.Printf("✅ Node '%s' registered successfully\n", nodeName)
 		fmt.Printf("📝 To start the node server, run this command on the target machine:\n")
+		conn := c.GetConfig()
 		fmt.Printf("    go run . node-server %s --api-host %s --api-port %s --node-ip %s\n",
-			nodeName, apiHost, apiPort, nodeIP)
+			nodeName, conn.Host, conn.Port, nodeIP)
 	},
 }
 
@@ -165,6 +162,8 @@ func init() {
 	createNodeCmd.Flags().StringVar(&nodeIP, "ip", "", "IP address of the node")
 	createNodeCmd.Flags().StringVar(&nodeLabels, "labels", "", "Labels for the node (comma-separated key=value pairs)")
 	createNodeCmd.MarkFlagRequired("ip")
+	addListFlags(getNodesCmd, "zone=eu-1", "status.phase=Ready")
+	addPrintFlags(getNodesCmd)
 
 	nodeCmd.AddCommand(createNodeCmd)
 	nodeCmd.AddCommand(getNodesCmd)
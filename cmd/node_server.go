@@ -2,38 +2,40 @@ package cmd
 
 import (
     "fmt"
+    "os"
     "github.com/spf13/cobra"
+    "github.com/selimhanmrl/Own-Kubernetes/config"
     "github.com/selimhanmrl/Own-Kubernetes/server"
 )
 
-var (
-    nodePort string
-)
+var nodeConfig = config.DefaultNodeAgentConfiguration()
 
 var nodeServerCmd = &cobra.Command{
     Use:   "node-server [node-name]",
     Short: "Start a node server",
     Long:  `Start a node server that manages containers on this machine`,
-    Args:  cobra.ExactArgs(1),
+    Args:  cobra.MaximumNArgs(1),
     RunE: func(cmd *cobra.Command, args []string) error {
-        nodeName := args[0]
-        
-        // Create node server with all parameters
-        nodeServer := server.NewNodeServer(
-            nodeName,
-            nodePort,
-            nodeIP,
-            apiHost,
-            apiPort,
-        )
+        printed, err := config.Load(cmd.Flags(), nodeConfig, os.Stdout, func() {
+            // The argument wins over the file and NODE_NAME
+            if len(args) == 1 {
+                nodeConfig.NodeName = args[0]
+            }
+            applyClientFlags(cmd, &nodeConfig.ClientConnection)
+        })
+        if printed || err != nil {
+            return err
+        }
         
-        fmt.Printf("Starting node server %s on %s:%s\n", nodeName, nodeIP, nodePort)
-        return nodeServer.Start()
+        nodeServer, err := server.NewNodeServer(nodeConfig)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Starting node server %s on %s\n", nodeConfig.NodeName, nodeConfig.NodeIP)
+        return nodeServer.Run(cmd.Context())
     },
 }
 
 func init() {
-    nodeServerCmd.Flags().StringVar(&nodePort, "port", "8081", "Port for the node server")
-    nodeServerCmd.Flags().StringVar(&nodeIP, "node-ip", "", "IP address of this node")
-    nodeServerCmd.MarkFlagRequired("node-ip")
-}
\ No newline at end of file
+    nodeConfig.AddFlags(nodeServerCmd.Flags())
+}
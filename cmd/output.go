@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
+	"github.com/spf13/cobra"
+)
+
+// Output flags shared by the get commands
+var printOptions printers.Options
+
+func addPrintFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&printOptions.Output, "output", "o", "", "Output format: json, yaml, wide, name, jsonpath=<template>, go-template=<template> or custom-columns=<spec>")
+	cmd.Flags().StringVar(&printOptions.SortBy, "sort-by", "", "Sort by a JSONPath expression, e.g. .metadata.name")
+	cmd.Flags().BoolVar(&printOptions.NoHeaders, "no-headers", false, "Don't print column headers")
+	cmd.Flags().BoolVar(&printOptions.ShowLabels, "show-labels", false, "Show labels as the last column")
+}
+
+// printList prints items in the format chosen by the output flags. Tables
+// have the given columns and one row per item, after a NAMESPACE column
+// when listing all namespaces; an empty table prints empty instead.
+func printList[T any](res client.Resource, items []T, columns []printers.Column, row func(T) []string, empty string) error {
+	printer, err := printers.NewPrinter(printOptions, resourcePrefix(res))
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 && printOptions.IsTable() {
+		fmt.Println(empty)
+		return nil
+	}
+
+	objects := make([]interface{}, len(items))
+	for i, item := range items {
+		if objects[i], err = printers.Decode(item); err != nil {
+			return fmt.Errorf("failed to encode %s: %v", res.Singular, err)
+		}
+	}
+	if printOptions.SortBy != "" {
+		order, err := printers.SortOrder(objects, printOptions.SortBy)
+		if err != nil {
+			return err
+		}
+		sortedItems := make([]T, len(items))
+		sortedObjects := make([]interface{}, len(objects))
+		for i, j := range order {
+			sortedItems[i], sortedObjects[i] = items[j], objects[j]
+		}
+		items, objects = sortedItems, sortedObjects
+	}
+
+	withNamespace := allNamespaces && res.Namespaced
+	table := printers.Table{Columns: columns}
+	if withNamespace {
+		table.Columns = append([]printers.Column{{Name: "NAMESPACE"}}, columns...)
+	}
+	if printOptions.IsTable() {
+		for i, item := range items {
+			cells := row(item)
+			if withNamespace {
+				cells = append([]string{objectNamespace(objects[i])}, cells...)
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+	}
+	return printer.Print(os.Stdout, objects, table)
+}
+
+// resourcePrefix is what -o name prints before the slash, e.g. pod or
+// role.rbac.authorization.k8s.io.
+func resourcePrefix(res client.Resource) string {
+	if res.Group == "" {
+		return res.Singular
+	}
+	return res.Singular + "." + res.Group
+}
+
+// objectNamespace reads metadata.namespace from a decoded object.
+func objectNamespace(obj interface{}) string {
+	m, _ := obj.(map[string]interface{})
+	meta, _ := m["metadata"].(map[string]interface{})
+	ns, _ := meta["namespace"].(string)
+	return ns
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	patchData string
+	patchFile string
+	patchType string
+)
+
+var patchTypes = map[string]string{
+	"strategic": client.StrategicMergePatchType,
+	"merge":     client.MergePatchType,
+	"json":      client.JSONPatchType,
+}
+
+var patchCmd = &cobra.Command{
+	Use:   "patch (TYPE NAME | TYPE/NAME) -p PATCH",
+	Short: "Update fields of a resource with a strategic merge, JSON merge or JSON patch",
+	Example: `  # Add a label
+  mykube patch pod web -p '{"metadata":{"labels":{"tier":"frontend"}}}'
+
+  # Change one container's image; containers are merged by name
+  mykube patch pod web -p '{"spec":{"containers":[{"name":"nginx","image":"nginx:1.25"}]}}'
+
+  # Remove a label with a JSON patch
+  mykube patch pod/web --type json -p '[{"op":"remove","path":"/metadata/labels/tier"}]'`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		res, name, err := resourceAndName(args)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		contentType, ok := patchTypes[patchType]
+		if !ok {
+			fmt.Printf("❌ --type must be one of strategic, merge or json\n")
+			return
+		}
+
+		data, err := readPatch()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		c := getClient()
+		if _, err := c.Patch(res, namespace, name, contentType, data, client.PatchOptions{}); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ %s/%s patched\n", res.Singular, name)
+	},
+}
+
+// resourceAndName accepts "TYPE NAME" or "TYPE/NAME".
+func resourceAndName(args []string) (client.Resource, string, error) {
+	typeName, name := args[0], ""
+	if len(args) == 2 {
+		name = args[1]
+	} else if parts := strings.SplitN(args[0], "/", 2); len(parts) == 2 {
+		typeName, name = parts[0], parts[1]
+	}
+	if name == "" {
+		return client.Resource{}, "", fmt.Errorf("a resource name is required")
+	}
+
+	res, ok := client.LookupResource(typeName)
+	if !ok {
+		return client.Resource{}, "", fmt.Errorf("unknown resource type %q", typeName)
+	}
+	return res, name, nil
+}
+
+// readPatch returns the patch from -p or --patch-file as JSON. YAML is
+// accepted too and converted.
+func readPatch() ([]byte, error) {
+	var raw []byte
+	switch {
+	case patchData != "" && patchFile != "":
+		return nil, fmt.Errorf("use only one of --patch and --patch-file")
+	case patchData != "":
+		raw = []byte(patchData)
+	case patchFile != "":
+		data, err := os.ReadFile(patchFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch file: %v", err)
+		}
+		raw = data
+	default:
+		return nil, fmt.Errorf("a patch is required, use --patch or --patch-file")
+	}
+
+	if json.Valid(raw) {
+		return raw, nil
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("patch is neither JSON nor YAML: %v", err)
+	}
+	return json.Marshal(value)
+}
+
+func init() {
+	patchCmd.Flags().StringVarP(&patchData, "patch", "p", "", "The patch, as JSON or YAML")
+	patchCmd.Flags().StringVar(&patchFile, "patch-file", "", "File containing the patch")
+	patchCmd.Flags().StringVar(&patchType, "type", "strategic", "Patch type: strategic, merge or json")
+	patchCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the resource")
+	rootCmd.AddCommand(patchCmd)
+}
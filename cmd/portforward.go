@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/spf13/cobra"
+)
+
+var forwardAddress string
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward (POD | pod/POD | svc/SERVICE) [LOCAL_PORT:]REMOTE_PORT [...]",
+	Short: "Forward local ports to a pod",
+	Long: `Forward local ports to a pod until interrupted. Each connection to a local
+port is tunnelled through the API server to the node server running the pod,
+which connects to the remote port of the pod.
+
+For svc/SERVICE a running pod the service selects is picked, and remote ports
+are ports of the service, forwarded to their target ports. Without a local
+port the remote one is used; with an empty one, as in :80, a free port is
+chosen.`,
+	Example: `  mykube port-forward pod/web 8080:80
+  mykube port-forward web 8080:80 8443:443
+  mykube port-forward svc/web :80 --address 0.0.0.0`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ns := namespace
+		if ns == "" {
+			ns = "default"
+		}
+		locals := make([]int, len(args)-1)
+		remotes := make([]int, len(args)-1)
+		for i, spec := range args[1:] {
+			var err error
+			if locals[i], remotes[i], err = parsePortSpec(spec); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		c := getClient()
+		pod, ports, err := forwardTarget(c, ns, args[0], remotes)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		var listeners []net.Listener
+		for i := range ports {
+			listener, err := net.Listen("tcp", net.JoinHostPort(forwardAddress, strconv.Itoa(locals[i])))
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			listeners = append(listeners, listener)
+			fmt.Printf("Forwarding from %s -> %d\n", listener.Addr(), ports[i])
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		var wg sync.WaitGroup
+		for i, listener := range listeners {
+			wg.Add(1)
+			go func(listener net.Listener, port int) {
+				defer wg.Done()
+				forwardConnections(ctx, c, ns, pod, listener, port)
+			}(listener, ports[i])
+		}
+		<-ctx.Done()
+		for _, listener := range listeners {
+			listener.Close()
+		}
+		wg.Wait()
+	},
+}
+
+// parsePortSpec parses [LOCAL_PORT:]REMOTE_PORT. An empty local port is 0,
+// which lets the system choose one.
+func parsePortSpec(spec string) (int, int, error) {
+	localSpec, remoteSpec, found := strings.Cut(spec, ":")
+	if !found {
+		localSpec, remoteSpec = spec, spec
+	}
+	remote, err := strconv.Atoi(remoteSpec)
+	if err != nil || remote < 1 || remote > 65535 {
+		return 0, 0, fmt.Errorf("invalid remote port in %q", spec)
+	}
+	local := 0
+	if localSpec != "" {
+		if local, err = strconv.Atoi(localSpec); err != nil || local < 0 || local > 65535 {
+			return 0, 0, fmt.Errorf("invalid local port in %q", spec)
+		}
+	}
+	return local, remote, nil
+}
+
+// forwardTarget returns the pod an argument of port-forward names and the
+// ports of the pod that remotes are forwarded to: remotes themselves for a
+// pod, the target ports of the service ports remotes name for a service.
+func forwardTarget(c *client.Client, namespace, arg string, remotes []int) (string, []int, error) {
+	if typeName, name, ok := strings.Cut(arg, "/"); ok {
+		res, found := client.LookupResource(typeName)
+		switch {
+		case found && res.Name == "services":
+			return serviceTarget(c, namespace, name, remotes)
+		case !found || res.Name != "pods":
+			return "", nil, fmt.Errorf("%q is not a pod or service", arg)
+		}
+	}
+
+	name, err := podArg(arg)
+	if err != nil {
+		return "", nil, err
+	}
+	var pod models.Pod
+	if err := c.Get(podResource, namespace, name, &pod); err != nil {
+		return "", nil, err
+	}
+	if pod.Status.Phase != "Running" {
+		return "", nil, fmt.Errorf("pod %q is %s, not Running", name, valueOrNone(pod.Status.Phase))
+	}
+	return name, remotes, nil
+}
+
+// serviceTarget picks a running pod the service called name selects, by
+// name, and maps service ports to its target ports.
+func serviceTarget(c *client.Client, namespace, name string, remotes []int) (string, []int, error) {
+	serviceResource, _ := client.LookupResource("services")
+	var service models.Service
+	if err := c.Get(serviceResource, namespace, name, &service); err != nil {
+		return "", nil, err
+	}
+
+	ports := make([]int, len(remotes))
+	for i, remote := range remotes {
+		for _, port := range service.Spec.Ports {
+			if port.Port == remote {
+				ports[i] = port.TargetPort
+			}
+		}
+		if ports[i] == 0 {
+			return "", nil, fmt.Errorf("service %q has no port %d", name, remote)
+		}
+	}
+
+	if len(service.Spec.Selector) == 0 {
+		return "", nil, fmt.Errorf("service %q has no selector", name)
+	}
+	pods, err := c.ListPodsWithOptions(namespace, client.ListOptions{LabelSelector: selectorString(service.Spec.Selector)})
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Metadata.Name < pods[j].Metadata.Name })
+	for _, pod := range pods {
+		if pod.Status.Phase == "Running" && pod.Spec.NodeName != "" {
+			return pod.Metadata.Name, ports, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no running pod backs service %q", name)
+}
+
+// forwardConnections forwards every connection accepted by listener to a
+// port of pod, until the listener is closed.
+func forwardConnections(ctx context.Context, c *client.Client, namespace, pod string, listener net.Listener, port int) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			}
+			return
+		}
+		fmt.Printf("Handling connection for %s\n", listener.Addr())
+		go func() {
+			if err := c.PortForward(ctx, namespace, pod, int32(port), conn); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			}
+		}()
+	}
+}
+
+func init() {
+	portForwardCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the pod or service")
+	portForwardCmd.Flags().StringVar(&forwardAddress, "address", "localhost", "Address to listen on, e.g. 0.0.0.0 for every interface")
+	rootCmd.AddCommand(portForwardCmd)
+}
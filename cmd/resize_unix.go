@@ -0,0 +1,33 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
+)
+
+// notifyResize sends the size of the terminal fd to sizes each time it
+// changes, until stop is called.
+func notifyResize(fd int, sizes chan wsstream.TerminalSize) (stop func()) {
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-winch:
+				sendTerminalSize(fd, sizes)
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(winch)
+		close(done)
+	}
+}
@@ -0,0 +1,9 @@
+package cmd
+
+import "github.com/selimhanmrl/Own-Kubernetes/wsstream"
+
+// notifyResize does nothing on Windows, which has no SIGWINCH; the session
+// keeps the size the terminal had when it started.
+func notifyResize(fd int, sizes chan wsstream.TerminalSize) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/printers"
+)
+
+// resourceTarget is a resource named on the command line and the objects
+// named with it; no names means the whole collection.
+type resourceTarget struct {
+	res   client.Resource
+	names []string
+}
+
+// parseResourceArgs reads the "TYPE [NAME...]" or "TYPE/NAME..." arguments
+// of the generic verbs. TYPE is a plural, singular, kind or short name such
+// as pods, pod, Pod or po; without names it may list several types, e.g.
+// pods,services.
+func parseResourceArgs(args []string) ([]resourceTarget, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("you must specify the type of resource, e.g. pods, services or nodes (see api-resources)")
+	}
+
+	if strings.Contains(args[0], "/") {
+		var targets []resourceTarget
+		for _, arg := range args {
+			typeName, name, ok := strings.Cut(arg, "/")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("there is no need to specify a resource type as a separate argument when passing arguments in resource/name form, got %q", arg)
+			}
+			res, ok := client.LookupResource(typeName)
+			if !ok {
+				return nil, fmt.Errorf("unknown resource type %q", typeName)
+			}
+			if n := len(targets); n > 0 && targets[n-1].res.Name == res.Name {
+				targets[n-1].names = append(targets[n-1].names, name)
+			} else {
+				targets = append(targets, resourceTarget{res: res, names: []string{name}})
+			}
+		}
+		return targets, nil
+	}
+
+	typeNames := strings.Split(args[0], ",")
+	if len(typeNames) > 1 && len(args) > 1 {
+		return nil, fmt.Errorf("names cannot be given with several resource types, use TYPE/NAME instead")
+	}
+	var targets []resourceTarget
+	for _, typeName := range typeNames {
+		res, ok := client.LookupResource(typeName)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource type %q", typeName)
+		}
+		targets = append(targets, resourceTarget{res: res, names: args[1:]})
+	}
+	return targets, nil
+}
+
+// resourceTable prints the objects of one resource, as they came from the
+// API server, in the format chosen by the output flags.
+type resourceTable func(res client.Resource, objects []json.RawMessage, empty string) error
+
+// table is the resourceTable of a model type: its columns, a row for each
+// object and, optionally, the order objects are printed in unless
+// --sort-by says otherwise.
+func table[T any](columns []printers.Column, row func(T) []string, sortItems func([]T)) resourceTable {
+	return func(res client.Resource, objects []json.RawMessage, empty string) error {
+		items := make([]T, len(objects))
+		for i, obj := range objects {
+			if err := json.Unmarshal(obj, &items[i]); err != nil {
+				return fmt.Errorf("failed to decode %s: %v", res.Singular, err)
+			}
+		}
+		if sortItems != nil {
+			sortItems(items)
+		}
+		return printList(res, items, columns, row, empty)
+	}
+}
+
+// objectColumns are the columns of resources without a table of their own.
+var objectColumns = []printers.Column{{Name: "NAME"}, {Name: "AGE"}}
+
+func objectRow(obj map[string]interface{}) []string {
+	meta, _ := obj["metadata"].(map[string]interface{})
+	name, _ := meta["name"].(string)
+	created, _ := meta["creationTimestamp"].(string)
+	return []string{name, ageSince(created)}
+}
+
+// ageSince is the age of an object created at an RFC 3339 timestamp.
+func ageSince(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "<unknown>"
+	}
+	return formatAge(time.Since(t))
+}
+
+// resourceTables are the tables of the resources get knows more about than
+// their name and age, by resource name.
+func resourceTables(c *client.Client) map[string]resourceTable {
+	return map[string]resourceTable{
+		"pods":        table(podColumns, func(pod models.Pod) []string { return podRow(c, pod) }, sortPods),
+		"services":    table(serviceColumns, serviceRow, nil),
+		"nodes":       table(nodeColumns, nodeRow, nil),
+		"namespaces":  table(namespaceColumns, namespaceRow, nil),
+		"events":      table(eventColumns, eventRow, sortEvents),
+		"deployments": table(deploymentColumns, deploymentRow, nil),
+		"replicasets": table(replicaSetColumns, replicaSetRow, nil),
+		"leases":      table(leaseColumns, leaseRow, nil),
+	}
+}
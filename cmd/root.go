@@ -1,17 +1,40 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/config"
 	"github.com/spf13/cobra"
 )
 
 var (
 	apiHost string
 	apiPort string
+
+	token                string
+	clientCertificate    string
+	clientKey            string
+	certificateAuthority string
+
+	kubeconfig  string
+	contextName string
+	// contextNamespace is the namespace of the context in use, if it sets
+	// one
+	contextNamespace string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "mykube",
 	Short: "MyKube is a tiny container orchestration CLI",
+	Long: `MyKube is a tiny container orchestration CLI.
+
+Without --api-host the API server, the credentials and the default
+namespace come from the current context of ~/.mykube/config, or of the file
+named by --kubeconfig or MYKUBE_CONFIG; see mykube config. Without a config
+file the API server is localhost:8080.`,
+	PersistentPreRunE: useContextNamespace,
 }
 
 func Execute() error {
@@ -20,10 +43,94 @@ func Execute() error {
 
 func init() {
 	// Add global flags for API server configuration
-	rootCmd.PersistentFlags().StringVar(&apiHost, "api-host", "localhost", "API server host")
-	rootCmd.PersistentFlags().StringVar(&apiPort, "api-port", "8080", "API server port")
+	rootCmd.PersistentFlags().StringVar(&apiHost, "api-host", "", "API server host, instead of the server of the current context (default localhost)")
+	rootCmd.PersistentFlags().StringVar(&apiPort, "api-port", "", "API server port (default 8080)")
+	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Bearer token for authentication to the API server")
+	rootCmd.PersistentFlags().StringVar(&clientCertificate, "client-certificate", "", "Path to a client certificate file for TLS")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "", "Path to a client key file for TLS")
+	rootCmd.PersistentFlags().StringVar(&certificateAuthority, "certificate-authority", "", "Path to a CA bundle used to verify the API server")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the config file (default $MYKUBE_CONFIG or ~/.mykube/config)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Name of the config file context to use instead of the current one")
 
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(nodeServerCmd) // Add this line
 }
+
+// clientConfig collects the connection and credential flags shared by
+// every command.
+func clientConfig() client.ClientConfig {
+	return client.ClientConfig{
+		Host:     apiHost,
+		Port:     apiPort,
+		Token:    token,
+		CertFile: clientCertificate,
+		KeyFile:  clientKey,
+		CAFile:   certificateAuthority,
+
+		Kubeconfig: kubeconfig,
+		Context:    contextName,
+	}
+}
+
+// useContextNamespace makes the namespace of the context in use the default
+// of commands whose --namespace defaults to "default". Commands sharing the
+// namespace variable leave it holding the last registered default, so it
+// is reset to this command's own default first.
+func useContextNamespace(cmd *cobra.Command, args []string) error {
+	flag := cmd.Flags().Lookup("namespace")
+	if flag == nil || flag.Changed {
+		return nil
+	}
+	namespace = flag.DefValue
+	if apiHost != "" {
+		// the kubeconfig is not used at all
+		return nil
+	}
+
+	file, err := client.LoadConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+	context, err := file.Context(contextName)
+	if err != nil || context == nil {
+		return err
+	}
+	contextNamespace = context.Namespace
+	if contextNamespace != "" && namespace != "" {
+		namespace = contextNamespace
+	}
+	return nil
+}
+
+// applyClientFlags copies the connection flags given on the command line
+// over conn, for components that also read it from a config file.
+func applyClientFlags(cmd *cobra.Command, conn *config.ClientConnection) {
+	flags := cmd.Flags()
+	for name, field := range map[string]struct {
+		dst   *string
+		value string
+	}{
+		"api-host":              {&conn.Host, apiHost},
+		"api-port":              {&conn.Port, apiPort},
+		"token":                 {&conn.Token, token},
+		"client-certificate":    {&conn.CertFile, clientCertificate},
+		"client-key":            {&conn.KeyFile, clientKey},
+		"certificate-authority": {&conn.CAFile, certificateAuthority},
+	} {
+		if flags.Changed(name) {
+			*field.dst = field.value
+		}
+	}
+}
+
+// getClient exits the command when the client configuration is unusable,
+// since no command can do anything without it.
+func getClient() *client.Client {
+	c, err := client.NewClient(clientConfig())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	return c
+}
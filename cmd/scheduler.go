@@ -1,10 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/config"
+	"github.com/selimhanmrl/Own-Kubernetes/healthz"
+	"github.com/selimhanmrl/Own-Kubernetes/leaderelection"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
 	"github.com/spf13/cobra"
 )
@@ -12,54 +24,154 @@ import (
 var schedulerCmd = &cobra.Command{
 	Use:   "scheduler",
 	Short: "Run the scheduler to assign pods to nodes",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printed, err := config.Load(cmd.Flags(), schedulerConfig, os.Stdout, func() {
+			applyClientFlags(cmd, &schedulerConfig.ClientConnection)
+		})
+		if printed || err != nil {
+			return err
+		}
 		fmt.Println("🎯 Starting scheduler...")
 
-		c := client.NewClient(client.ClientConfig{
-			Host: apiHost,
-			Port: apiPort,
-		})
+		c, err := client.NewClient(schedulerConfig.ClientConnection.ClientConfig())
+		if err != nil {
+			return err
+		}
+		recorder := client.NewEventRecorder(c, "default-scheduler", "")
+		runner := component.NewRunner("scheduler", schedulerConfig.ShutdownTimeout)
+		checks := []healthz.Checker{healthz.Ping}
 
-		// Wait for API server to be ready
-		fmt.Println("⌛ Waiting for API server...")
-		for {
-			_, err := c.ListNodes()
-			if err == nil {
-				break
+		election := schedulerConfig.LeaderElection
+		if !election.LeaderElect {
+			runner.Go("scheduling loop", func(ctx context.Context) error {
+				schedule(ctx, c, recorder, schedulerConfig.PollInterval)
+				return nil
+			})
+		} else {
+			elector, err := leaderelection.New(c, leaderelection.Config{
+				Namespace:     election.ResourceNamespace,
+				Name:          election.ResourceName,
+				Identity:      leaderIdentity(),
+				LeaseDuration: election.LeaseDuration,
+				RenewDeadline: election.RenewDeadline,
+				RetryPeriod:   election.RetryPeriod,
+			})
+			if err != nil {
+				return err
 			}
-			fmt.Printf("🔄 Retrying connection to API server...\n")
-			time.Sleep(2 * time.Second)
+			checks = append(checks, elector)
+			runner.Go("scheduling loop", func(ctx context.Context) error {
+				return elector.Run(ctx, func(ctx context.Context) {
+					schedule(ctx, c, recorder, schedulerConfig.PollInterval)
+				})
+			})
 		}
 
-		fmt.Println("✅ Connected to API server")
+		if schedulerConfig.HealthPort != 0 {
+			runner.Serve(&http.Server{
+				Addr:    net.JoinHostPort(schedulerConfig.HealthBindAddress, strconv.Itoa(schedulerConfig.HealthPort)),
+				Handler: schedulerHealth(runner, checks),
+			}, false)
+		}
+		return runner.Run(cmd.Context())
+	},
+}
 
-		for {
-			pods, err := c.ListPods("")
-			if err != nil {
-				fmt.Printf("❌ Failed to list pods: %v\n", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
+var schedulerConfig = config.DefaultSchedulerConfiguration()
 
-			// Schedule pending pods
-			for _, pod := range pods {
-				if pod.Status.Phase != "Pending" || pod.Spec.NodeName != "" {
-					continue
-				}
+// leaderIdentity names this scheduler in the leader election lease. The
+// random suffix keeps two schedulers on one host apart.
+func leaderIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "scheduler"
+	}
+	return hostname + "_" + uuid.NewString()
+}
+
+// schedulerHealth serves /livez, /readyz and /healthz for the scheduler.
+// /readyz also fails once shutdown begins.
+func schedulerHealth(runner *component.Runner, checks []healthz.Checker) http.Handler {
+	var shuttingDown atomic.Bool
+	runner.OnShutdown(func() { shuttingDown.Store(true) })
+	shutdown := healthz.NamedCheck("shutdown", func(*http.Request) error {
+		if shuttingDown.Load() {
+			return fmt.Errorf("scheduler is shutting down")
+		}
+		return nil
+	})
+	ready := append(append([]healthz.Checker{}, checks...), shutdown)
+
+	router := mux.NewRouter()
+	router.PathPrefix("/livez").Handler(healthz.Handler("livez", healthz.Ping)).Methods("GET")
+	router.PathPrefix("/readyz").Handler(healthz.Handler("readyz", ready...)).Methods("GET")
+	router.PathPrefix("/healthz").Handler(healthz.Handler("healthz", checks...)).Methods("GET")
+	return router
+}
+
+// schedule assigns pending pods to nodes every pollInterval until ctx is
+// cancelled, recording the outcome for each pod as an event.
+func schedule(ctx context.Context, c *client.Client, recorder *client.EventRecorder, pollInterval time.Duration) {
+	// Wait for API server to be ready
+	fmt.Println("⌛ Waiting for API server...")
+	for {
+		err := c.Ready(5 * time.Second)
+		if err == nil {
+			break
+		}
+		fmt.Printf("🔄 API server not ready, retrying: %v\n", err)
+		if !sleep(ctx, 2*time.Second) {
+			return
+		}
+	}
 
-				if err := assignNodeToPod(&pod, c); err != nil {
-					fmt.Printf("❌ Failed to assign node to pod '%s': %v\n",
-						pod.Metadata.Name, err)
-					continue
-				}
+	fmt.Println("✅ Connected to API server")
 
-				fmt.Printf("✅ Successfully assigned pod '%s' to node '%s'\n",
-					pod.Metadata.Name, pod.Spec.NodeName)
+	for {
+		pods, err := c.ListPods("")
+		if err != nil {
+			fmt.Printf("❌ Failed to list pods: %v\n", err)
+			if !sleep(ctx, pollInterval) {
+				return
 			}
+			continue
+		}
 
-			time.Sleep(5 * time.Second)
+		// Schedule pending pods
+		for _, pod := range pods {
+			if pod.Status.Phase != "Pending" || pod.Spec.NodeName != "" {
+				continue
+			}
+
+			if err := assignNodeToPod(&pod, c); err != nil {
+				fmt.Printf("❌ Failed to assign node to pod '%s': %v\n",
+					pod.Metadata.Name, err)
+				recorder.Eventf(models.PodReference(pod), models.EventTypeWarning, "FailedScheduling", "%v", err)
+				continue
+			}
+			recorder.Eventf(models.PodReference(pod), models.EventTypeNormal, "Scheduled",
+				"Successfully assigned %s/%s to %s", pod.Metadata.Namespace, pod.Metadata.Name, pod.Spec.NodeName)
+
+			fmt.Printf("✅ Successfully assigned pod '%s' to node '%s'\n",
+				pod.Metadata.Name, pod.Spec.NodeName)
 		}
-	},
+
+		if !sleep(ctx, pollInterval) {
+			return
+		}
+	}
+}
+
+// sleep waits for d, returning false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
 
 var lastNodeIndex = 0
@@ -75,16 +187,28 @@ func assignNodeToPod(pod *models.Pod, c *client.Client) error {
 		return fmt.Errorf("no nodes available")
 	}
 
-	// Filter ready nodes
+	pods, err := c.ListPods("")
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	request := pod.ResourceRequests()
+
+	// Filter ready nodes that still have room for the pod's requests
 	readyNodes := []models.Node{}
 	for _, node := range nodes {
-		if node.Status.Phase == "Ready" {
-			readyNodes = append(readyNodes, node)
+		if node.Status.Phase != "Ready" {
+			continue
+		}
+		if fits, resource := nodeFitsPod(node, pods, request); !fits {
+			fmt.Printf("⚠️ Node '%s' has insufficient %s for pod '%s'\n",
+				node.Name, resource, pod.Metadata.Name)
+			continue
 		}
+		readyNodes = append(readyNodes, node)
 	}
 
 	if len(readyNodes) == 0 {
-		return fmt.Errorf("no ready nodes available")
+		return fmt.Errorf("no ready nodes with enough resources available")
 	}
 
 	// Round-robin selection
@@ -102,10 +226,37 @@ func assignNodeToPod(pod *models.Pod, c *client.Client) error {
 	return c.UpdatePod(*pod)
 }
 
+// nodeFitsPod checks the pod's requests against what is left of the node's
+// allocatable resources after the pods already bound to it.
+func nodeFitsPod(node models.Node, pods []models.Pod, request models.ResourceList) (bool, string) {
+	allocatable := node.Status.Allocatable
+	if len(allocatable) == 0 {
+		allocatable = node.Status.Capacity
+	}
+
+	used := models.ResourceList{}
+	for _, p := range pods {
+		if p.Spec.NodeName != node.Name || p.Status.Phase == "Failed" || p.Status.Phase == "Succeeded" {
+			continue
+		}
+		used.Add(p.ResourceRequests())
+		used.Add(models.ResourceList{models.ResourcePods: models.NewQuantity(1, models.DecimalSI)})
+	}
+
+	remaining := models.ResourceList{}
+	for name, q := range allocatable {
+		left := q
+		left.Sub(used[name])
+		remaining[name] = left
+	}
+
+	needed := models.ResourceList{models.ResourcePods: models.NewQuantity(1, models.DecimalSI)}
+	needed.Add(request)
+	return remaining.Fits(needed)
+}
+
 func init() {
-	// Add configuration flags
-	schedulerCmd.Flags().StringVar(&apiHost, "api-host", "localhost", "API server hostname")
-	schedulerCmd.Flags().StringVar(&apiPort, "api-port", "8080", "API server port")
 	schedulerCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to filter services and pods")
+	schedulerConfig.AddFlags(schedulerCmd.Flags())
 	rootCmd.AddCommand(schedulerCmd)
 }
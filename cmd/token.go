@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	signingKeyFile string
+	tokenTTL       time.Duration
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Service account token commands",
+}
+
+var createTokenCmd = &cobra.Command{
+	Use:   "create [namespace/]service-account",
+	Short: "Sign a service account token with the API server's key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ns, name := namespace, args[0]
+		if parts := strings.SplitN(args[0], "/", 2); len(parts) == 2 {
+			ns, name = parts[0], parts[1]
+		}
+		if ns == "" {
+			ns = "default"
+		}
+
+		key, err := auth.LoadPrivateKey(signingKeyFile)
+		if err != nil {
+			fmt.Printf("❌ Failed to load signing key: %v\n", err)
+			return
+		}
+
+		signed, err := auth.SignServiceAccountToken(key, ns, name, tokenTTL)
+		if err != nil {
+			fmt.Printf("❌ Failed to create token: %v\n", err)
+			return
+		}
+		fmt.Println(signed)
+	},
+}
+
+func init() {
+	createTokenCmd.Flags().StringVar(&signingKeyFile, "signing-key", "", "RSA private key the API server verifies service account tokens with")
+	createTokenCmd.Flags().DurationVar(&tokenTTL, "ttl", 0, "Token lifetime, 0 for a token that does not expire")
+	createTokenCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace of the service account")
+	createTokenCmd.MarkFlagRequired("signing-key")
+
+	tokenCmd.AddCommand(createTokenCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
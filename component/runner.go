@@ -0,0 +1,232 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Exit statuses of the components.
+const (
+	// ExitOK: stopped cleanly, including after SIGTERM or SIGINT.
+	ExitOK = 0
+	// ExitFailure: failed to start, failed while running, or did not
+	// finish shutting down within the shutdown timeout.
+	ExitFailure = 1
+	// ExitConfig: invalid flags, environment or config file.
+	ExitConfig = 2
+)
+
+// ErrShutdownTimeout is returned by Run when requests or tasks were still
+// running when the shutdown timeout ran out.
+var ErrShutdownTimeout = errors.New("shutdown timed out")
+
+// Runner runs the HTTP servers and background tasks of one component and
+// stops them together:
+//
+//  1. listeners are bound up front, so a taken port fails startup
+//  2. servers and tasks run until SIGTERM/SIGINT, the parent context is
+//     cancelled, or one of them fails
+//  3. OnShutdown hooks run, e.g. to fail /readyz
+//  4. long-running requests are told to end and servers drain in-flight
+//     requests
+//  5. task contexts are cancelled and the tasks waited for
+//
+// Steps 4 and 5 share the shutdown timeout. A second signal during
+// shutdown kills the process.
+type Runner struct {
+	name            string
+	shutdownTimeout time.Duration
+
+	servers    []runnerServer
+	tasks      []runnerTask
+	onShutdown []func()
+	stopping   chan struct{}
+}
+
+type runnerServer struct {
+	server *http.Server
+	tls    bool
+}
+
+type runnerTask struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// NewRunner returns a Runner for the component called name.
+func NewRunner(name string, shutdownTimeout time.Duration) *Runner {
+	return &Runner{
+		name:            name,
+		shutdownTimeout: shutdownTimeout,
+		stopping:        make(chan struct{}),
+	}
+}
+
+// Serve adds an HTTP server listening on server.Addr. With tls the
+// certificates must be in server.TLSConfig.
+func (r *Runner) Serve(server *http.Server, tls bool) {
+	if server.BaseContext == nil {
+		server.BaseContext = func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), stoppingKey{}, r.stopping)
+		}
+	}
+	r.servers = append(r.servers, runnerServer{server: server, tls: tls})
+}
+
+// Go adds a background task. Its context is cancelled on shutdown; a task
+// that returns an error any earlier stops the whole component.
+func (r *Runner) Go(name string, run func(ctx context.Context) error) {
+	r.tasks = append(r.tasks, runnerTask{name: name, run: run})
+}
+
+// OnShutdown registers f to run as soon as shutdown begins.
+func (r *Runner) OnShutdown(f func()) {
+	r.onShutdown = append(r.onShutdown, f)
+}
+
+// Run starts the servers and tasks and blocks until the component has shut
+// down. It returns nil after a clean shutdown on a signal or ctx.
+func (r *Runner) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	listeners := make([]net.Listener, 0, len(r.servers))
+	for _, s := range r.servers {
+		listener, err := net.Listen("tcp", s.server.Addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return fmt.Errorf("failed to listen on %s: %v", s.server.Addr, err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	failed := make(chan error, len(r.servers)+len(r.tasks))
+	for i, s := range r.servers {
+		go func(s runnerServer, listener net.Listener) {
+			var err error
+			if s.tls {
+				err = s.server.ServeTLS(listener, "", "")
+			} else {
+				err = s.server.Serve(listener)
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("server on %s: %v", s.server.Addr, err)
+			}
+		}(s, listeners[i])
+	}
+
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	defer cancelTasks()
+	var tasks sync.WaitGroup
+	for _, t := range r.tasks {
+		tasks.Add(1)
+		go func(t runnerTask) {
+			defer tasks.Done()
+			if err := t.run(taskCtx); err != nil && taskCtx.Err() == nil {
+				failed <- fmt.Errorf("%s: %v", t.name, err)
+			}
+		}(t)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Printf("🛑 %s shutting down", r.name)
+	case runErr = <-failed:
+		log.Printf("❌ %s failed, shutting down: %v", r.name, runErr)
+	}
+	// Restore the default handlers so a second signal kills the process
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+	for _, f := range r.onShutdown {
+		f()
+	}
+	close(r.stopping)
+
+	timedOut := false
+	var servers sync.WaitGroup
+	var mu sync.Mutex
+	for _, s := range r.servers {
+		servers.Add(1)
+		go func(server *http.Server) {
+			defer servers.Done()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				server.Close()
+				mu.Lock()
+				timedOut = true
+				mu.Unlock()
+			}
+		}(s.server)
+	}
+	servers.Wait()
+
+	cancelTasks()
+	done := make(chan struct{})
+	go func() {
+		tasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		timedOut = true
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	if timedOut {
+		return fmt.Errorf("%w after %s", ErrShutdownTimeout, r.shutdownTimeout)
+	}
+	log.Printf("✅ %s stopped", r.name)
+	return nil
+}
+
+type stoppingKey struct{}
+
+// LongRunning returns a context for a request that may stay open
+// indefinitely, such as a stream. It is cancelled when the component
+// starts shutting down, where ordinary requests are left to finish.
+func LongRunning(req *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(req.Context())
+	stopping, ok := req.Context().Value(stoppingKey{}).(chan struct{})
+	if !ok {
+		return ctx, cancel
+	}
+	go func() {
+		select {
+		case <-stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// Exit ends the process with the status matching err, logging it first.
+func Exit(name string, err error) {
+	var configErr *ConfigError
+	switch {
+	case err == nil:
+		os.Exit(ExitOK)
+	case errors.As(err, &configErr):
+		log.Printf("❌ %s: invalid configuration: %v", name, err)
+		os.Exit(ExitConfig)
+	default:
+		log.Printf("❌ %s: %v", name, err)
+		os.Exit(ExitFailure)
+	}
+}
@@ -0,0 +1,68 @@
+// Package component holds what the long-running processes (API server,
+// node server, node agent, proxy and scheduler) share: where they listen
+// and how they start and stop.
+package component
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// ServingOptions say where a component listens and how long it may take to
+// stop.
+type ServingOptions struct {
+	// BindAddress is the IP to listen on; empty means all interfaces.
+	BindAddress string `yaml:"bindAddress"`
+	Port        int    `yaml:"port"`
+	// ShutdownTimeout bounds draining in-flight requests and stopping
+	// background work after SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+}
+
+// DefaultShutdownTimeout is used when a component does not pick its own.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Address is the host:port to listen on.
+func (o ServingOptions) Address() string {
+	return net.JoinHostPort(o.BindAddress, strconv.Itoa(o.Port))
+}
+
+// AddFlags registers --bind-address, --port and --shutdown-timeout,
+// defaulting to the current values of o.
+func (o *ServingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.BindAddress, "bind-address", o.BindAddress, "IP address to listen on, empty for all interfaces")
+	fs.IntVar(&o.Port, "port", o.Port, "Port to listen on")
+	fs.DurationVar(&o.ShutdownTimeout, "shutdown-timeout", o.ShutdownTimeout, "How long to drain requests and stop background work on SIGTERM")
+}
+
+// Validate checks the options are usable.
+func (o ServingOptions) Validate() error {
+	if o.BindAddress != "" && net.ParseIP(o.BindAddress) == nil {
+		return ConfigErrorf("bind address %q is not an IP address", o.BindAddress)
+	}
+	if o.Port < 1 || o.Port > 65535 {
+		return ConfigErrorf("port %d is out of range 1-65535", o.Port)
+	}
+	if o.ShutdownTimeout < 0 {
+		return ConfigErrorf("shutdown timeout %s is negative", o.ShutdownTimeout)
+	}
+	return nil
+}
+
+// ConfigError is a problem with flags, environment or config file. The
+// process exits with ExitConfig for it.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// ConfigErrorf returns a ConfigError with a formatted message.
+func ConfigErrorf(format string, args ...interface{}) error {
+	return &ConfigError{Err: fmt.Errorf(format, args...)}
+}
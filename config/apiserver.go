@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/spf13/pflag"
+)
+
+// APIServerConfiguration configures the API server.
+type APIServerConfiguration struct {
+	TypeMeta `yaml:",inline"`
+
+	Serving component.ServingOptions `yaml:"serving"`
+	// ProxyBindAddress is the IP NodePort services listen on; empty means
+	// all interfaces.
+	ProxyBindAddress string `yaml:"proxyBindAddress"`
+
+	Storage        StorageConfiguration        `yaml:"storage"`
+	TLS            TLSConfiguration            `yaml:"tls"`
+	Authentication AuthenticationConfiguration `yaml:"authentication"`
+	Authorization  AuthorizationConfiguration  `yaml:"authorization"`
+	Audit          AuditConfiguration          `yaml:"audit"`
+	KubeletClient  KubeletClientConfiguration  `yaml:"kubeletClient"`
+
+	// ServiceNodePortRange is the inclusive range NodePorts are assigned
+	// from, e.g. 30000-32767.
+	ServiceNodePortRange string `yaml:"serviceNodePortRange"`
+	// NodeIPRange is the inclusive range of addresses given to nodes that
+	// register without one, e.g. 192.168.1.100-192.168.1.105. Both ends
+	// must share the first three octets.
+	NodeIPRange string `yaml:"nodeIPRange"`
+	// EventTTL is how long events are kept after they were last recorded.
+	EventTTL time.Duration `yaml:"eventTTL"`
+}
+
+// StorageConfiguration locates Redis.
+type StorageConfiguration struct {
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+}
+
+// TLSConfiguration enables https with a serving certificate.
+type TLSConfiguration struct {
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+}
+
+type AuthenticationConfiguration struct {
+	TokenFile             string `yaml:"tokenFile,omitempty"`
+	ServiceAccountKeyFile string `yaml:"serviceAccountKeyFile,omitempty"`
+	ClientCAFile          string `yaml:"clientCAFile,omitempty"`
+	// AllowAnonymous lets requests without credentials read.
+	AllowAnonymous bool `yaml:"allowAnonymous"`
+}
+
+type AuthorizationConfiguration struct {
+	// Modes are tried in order: AlwaysAllow, Node or RBAC. Empty allows
+	// every authenticated request.
+	Modes []string `yaml:"modes,omitempty"`
+}
+
+// KubeletClientConfiguration is how the API server reaches node servers to
+// stream pod logs. Setting CAFile switches to https, and the certificate
+// is presented to node servers that require one.
+type KubeletClientConfiguration struct {
+	Port     int    `yaml:"port"`
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	CAFile   string `yaml:"caFile,omitempty"`
+}
+
+type AuditConfiguration struct {
+	PolicyFile    string `yaml:"policyFile,omitempty"`
+	LogPath       string `yaml:"logPath,omitempty"`
+	LogMaxSizeMB  int    `yaml:"logMaxSizeMB"`
+	LogMaxBackups int    `yaml:"logMaxBackups"`
+	WebhookURL    string `yaml:"webhookURL,omitempty"`
+}
+
+// DefaultAPIServerConfiguration returns the settings used when nothing
+// else is given.
+func DefaultAPIServerConfiguration() *APIServerConfiguration {
+	return &APIServerConfiguration{
+		TypeMeta:             TypeMeta{APIVersion: GroupVersion, Kind: "APIServerConfiguration"},
+		Serving:              component.ServingOptions{Port: 8080, ShutdownTimeout: component.DefaultShutdownTimeout},
+		Storage:              StorageConfiguration{Host: "localhost", Port: "6379"},
+		Authentication:       AuthenticationConfiguration{AllowAnonymous: true},
+		Audit:                AuditConfiguration{LogMaxSizeMB: 100, LogMaxBackups: 5},
+		KubeletClient:        KubeletClientConfiguration{Port: 8081},
+		ServiceNodePortRange: "30000-32767",
+		NodeIPRange:          "192.168.1.100-192.168.1.105",
+		EventTTL:             time.Hour,
+	}
+}
+
+// AddFlags binds the API server's flags to c.
+func (c *APIServerConfiguration) AddFlags(fs *pflag.FlagSet) {
+	c.Serving.AddFlags(fs)
+	fs.StringVar(&c.ProxyBindAddress, "proxy-bind-address", c.ProxyBindAddress, "IP address NodePort services listen on, empty for all interfaces")
+	fs.StringSliceVar(&c.Authorization.Modes, "authorization-mode", c.Authorization.Modes, "Authorization modes to try in order: AlwaysAllow, Node, RBAC")
+	fs.StringVar(&c.ServiceNodePortRange, "service-node-port-range", c.ServiceNodePortRange, "Range NodePorts are assigned from")
+	fs.StringVar(&c.NodeIPRange, "node-ip-range", c.NodeIPRange, "Range of addresses given to nodes that register without one")
+	fs.DurationVar(&c.EventTTL, "event-ttl", c.EventTTL, "How long to keep events after they were last recorded")
+	fs.IntVar(&c.KubeletClient.Port, "kubelet-port", c.KubeletClient.Port, "Port node servers listen on")
+	fs.StringVar(&c.KubeletClient.CertFile, "kubelet-client-certificate", c.KubeletClient.CertFile, "Client certificate presented to node servers")
+	fs.StringVar(&c.KubeletClient.KeyFile, "kubelet-client-key", c.KubeletClient.KeyFile, "Key of the client certificate presented to node servers")
+	fs.StringVar(&c.KubeletClient.CAFile, "kubelet-certificate-authority", c.KubeletClient.CAFile, "CA bundle verifying node servers, enables https")
+	AddFlags(fs)
+}
+
+func (c *APIServerConfiguration) applyEnv() error {
+	var e env
+	e.serving("SERVER_", &c.Serving)
+	e.string("PROXY_BIND_ADDRESS", &c.ProxyBindAddress)
+	e.string("REDIS_HOST", &c.Storage.Host)
+	e.string("REDIS_PORT", &c.Storage.Port)
+	e.string("TLS_CERT_FILE", &c.TLS.CertFile)
+	e.string("TLS_KEY_FILE", &c.TLS.KeyFile)
+	e.string("TOKEN_AUTH_FILE", &c.Authentication.TokenFile)
+	e.string("SERVICE_ACCOUNT_KEY_FILE", &c.Authentication.ServiceAccountKeyFile)
+	e.string("CLIENT_CA_FILE", &c.Authentication.ClientCAFile)
+	e.bool("ANONYMOUS_AUTH", &c.Authentication.AllowAnonymous)
+	e.list("AUTHORIZATION_MODE", &c.Authorization.Modes)
+	e.string("AUDIT_POLICY_FILE", &c.Audit.PolicyFile)
+	e.string("AUDIT_LOG_PATH", &c.Audit.LogPath)
+	e.int("AUDIT_LOG_MAXSIZE", &c.Audit.LogMaxSizeMB)
+	e.int("AUDIT_LOG_MAXBACKUP", &c.Audit.LogMaxBackups)
+	e.string("AUDIT_WEBHOOK_URL", &c.Audit.WebhookURL)
+	e.string("SERVICE_NODE_PORT_RANGE", &c.ServiceNodePortRange)
+	e.string("NODE_IP_RANGE", &c.NodeIPRange)
+	e.duration("EVENT_TTL", &c.EventTTL)
+	e.int("KUBELET_PORT", &c.KubeletClient.Port)
+	e.string("KUBELET_CLIENT_CERTIFICATE", &c.KubeletClient.CertFile)
+	e.string("KUBELET_CLIENT_KEY", &c.KubeletClient.KeyFile)
+	e.string("KUBELET_CERTIFICATE_AUTHORITY", &c.KubeletClient.CAFile)
+	return e.err()
+}
+
+// Validate checks every field and reports all problems at once.
+func (c *APIServerConfiguration) Validate() error {
+	var v validator
+	v.serving("serving", c.Serving)
+	v.ip("proxyBindAddress", c.ProxyBindAddress, true)
+	v.require(c.Storage.Host != "", "storage.host is required")
+	v.port("storage.port", c.Storage.Port)
+	v.require((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.certFile and tls.keyFile must be set together")
+	v.require(c.Authentication.ClientCAFile == "" || c.TLS.CertFile != "", "authentication.clientCAFile requires tls.certFile and tls.keyFile")
+	for _, mode := range c.Authorization.Modes {
+		v.require(mode == "AlwaysAllow" || mode == "Node" || mode == "RBAC", "authorization.modes: unknown mode %q", mode)
+	}
+	v.require(c.Audit.LogMaxSizeMB >= 0, "audit.logMaxSizeMB must not be negative")
+	v.require(c.Audit.LogMaxBackups >= 0, "audit.logMaxBackups must not be negative")
+	v.positive("eventTTL", c.EventTTL)
+	v.require(c.KubeletClient.Port >= 1 && c.KubeletClient.Port <= 65535, "kubeletClient.port %d is not a port", c.KubeletClient.Port)
+	v.require((c.KubeletClient.CertFile == "") == (c.KubeletClient.KeyFile == ""), "kubeletClient.certFile and kubeletClient.keyFile must be set together")
+	if _, _, err := c.NodePortRange(); err != nil {
+		v.errs = append(v.errs, err.Error())
+	}
+	if _, _, _, err := c.NodeIPs(); err != nil {
+		v.errs = append(v.errs, err.Error())
+	}
+	return v.err()
+}
+
+// NodePortRange returns the bounds of ServiceNodePortRange.
+func (c *APIServerConfiguration) NodePortRange() (min, max int, err error) {
+	low, high, ok := strings.Cut(c.ServiceNodePortRange, "-")
+	min, errMin := strconv.Atoi(strings.TrimSpace(low))
+	max, errMax := strconv.Atoi(strings.TrimSpace(high))
+	if !ok || errMin != nil || errMax != nil || min < 1 || max > 65535 || min > max {
+		return 0, 0, fmt.Errorf("serviceNodePortRange %q is not a port range such as 30000-32767", c.ServiceNodePortRange)
+	}
+	return min, max, nil
+}
+
+// NodeIPs splits NodeIPRange into the shared prefix, e.g. "192.168.1.",
+// and the first and last values of the final octet.
+func (c *APIServerConfiguration) NodeIPs() (prefix string, first, last int, err error) {
+	invalid := fmt.Errorf("nodeIPRange %q is not an IPv4 range such as 192.168.1.100-192.168.1.105", c.NodeIPRange)
+	low, high, ok := strings.Cut(c.NodeIPRange, "-")
+	start, end := net.ParseIP(strings.TrimSpace(low)).To4(), net.ParseIP(strings.TrimSpace(high)).To4()
+	if !ok || start == nil || end == nil {
+		return "", 0, 0, invalid
+	}
+	if !start.Mask(net.CIDRMask(24, 32)).Equal(end.Mask(net.CIDRMask(24, 32))) || start[3] > end[3] {
+		return "", 0, 0, invalid
+	}
+	return fmt.Sprintf("%d.%d.%d.", start[0], start[1], start[2]), int(start[3]), int(end[3]), nil
+}
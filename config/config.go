@@ -0,0 +1,332 @@
+// Package config holds the versioned configuration files of the
+// components. Every component resolves its settings the same way, in
+// increasing priority:
+//
+//  1. built-in defaults
+//  2. the YAML file named by --config
+//  3. environment variables
+//  4. flags given on the command line
+//
+// and validates the result before starting. --print-config shows it.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// GroupVersion is the apiVersion of every configuration kind.
+const GroupVersion = "config.mykube.io/v1alpha1"
+
+// TypeMeta names the version and kind of a configuration file.
+type TypeMeta struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+}
+
+func (t *TypeMeta) typeMeta() *TypeMeta { return t }
+
+// Configuration is one of the configuration kinds.
+type Configuration interface {
+	Validate() error
+	applyEnv() error
+	typeMeta() *TypeMeta
+}
+
+// ClientConnection says how a component reaches the API server.
+type ClientConnection struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Token    string `yaml:"token,omitempty"`
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	CAFile   string `yaml:"caFile,omitempty"`
+}
+
+func defaultClientConnection() ClientConnection {
+	return ClientConnection{Host: "localhost", Port: "8080"}
+}
+
+// ClientConfig converts the connection for client.NewClient.
+func (c ClientConnection) ClientConfig() client.ClientConfig {
+	return client.ClientConfig{
+		Host:     c.Host,
+		Port:     c.Port,
+		Token:    c.Token,
+		CertFile: c.CertFile,
+		KeyFile:  c.KeyFile,
+		CAFile:   c.CAFile,
+	}
+}
+
+func (c ClientConnection) validate(v *validator) {
+	v.require(c.Host != "", "clientConnection.host is required")
+	v.port("clientConnection.port", c.Port)
+	v.require((c.CertFile == "") == (c.KeyFile == ""), "clientConnection.certFile and keyFile must be set together")
+}
+
+// AddFlags registers --config and --print-config.
+func AddFlags(fs *pflag.FlagSet) {
+	fs.String("config", "", "YAML configuration file, see --print-config for its format")
+	fs.Bool("print-config", false, "Print the effective configuration and exit")
+}
+
+// ParseFlags parses args into fs for components started outside the CLI.
+// help is set after -h or --help, which printed the usage; the caller
+// should exit.
+func ParseFlags(fs *pflag.FlagSet, args []string) (help bool, err error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return true, nil
+		}
+		return false, &component.ConfigError{Err: err}
+	}
+	return false, nil
+}
+
+// Load resolves cfg, which holds the defaults and has its flags bound and
+// parsed on fs, from the --config file, the environment and the flags that
+// were given, then validates it. Flags bound elsewhere, such as the CLI's
+// connection flags, are copied in by the overrides, which run after the
+// bound flags. With --print-config it writes the result to out and reports
+// printed, and the caller should exit.
+func Load(fs *pflag.FlagSet, cfg Configuration, out io.Writer, overrides ...func()) (printed bool, err error) {
+	// Flags win over everything, so remember them before the file and
+	// environment overwrite the fields they are bound to
+	type setFlag struct {
+		flag  *pflag.Flag
+		value string
+		slice []string
+	}
+	var given []setFlag
+	fs.Visit(func(f *pflag.Flag) {
+		set := setFlag{flag: f, value: f.Value.String()}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			set.slice = slice.GetSlice()
+		}
+		given = append(given, set)
+	})
+
+	if path, _ := fs.GetString("config"); path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return false, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return false, err
+	}
+	for _, set := range given {
+		if slice, ok := set.flag.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(set.slice)
+		} else {
+			err = set.flag.Value.Set(set.value)
+		}
+		if err != nil {
+			return false, component.ConfigErrorf("--%s: %v", set.flag.Name, err)
+		}
+	}
+	for _, override := range overrides {
+		override()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return false, err
+	}
+	if print, _ := fs.GetBool("print-config"); print {
+		var doc yaml.Node
+		if err := doc.Encode(cfg); err != nil {
+			return false, err
+		}
+		redactSecrets(&doc)
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&doc); err != nil {
+			return false, err
+		}
+		return true, encoder.Close()
+	}
+	return false, nil
+}
+
+// secretFields are the configuration fields --print-config does not show.
+var secretFields = map[string]bool{"token": true}
+
+// redactSecrets replaces the values of secret fields set anywhere in node,
+// like config view does with kubeconfig tokens.
+func redactSecrets(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if secretFields[key.Value] && value.Kind == yaml.ScalarNode && value.Value != "" {
+				value.SetString("REDACTED")
+			}
+		}
+	}
+	for _, child := range node.Content {
+		redactSecrets(child)
+	}
+}
+
+// decodeFile reads a configuration file of cfg's kind over cfg. Fields the
+// file leaves out keep their current values; unknown fields are errors.
+func decodeFile(path string, cfg Configuration) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return component.ConfigErrorf("failed to read config: %v", err)
+	}
+
+	var meta TypeMeta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return component.ConfigErrorf("failed to parse %s: %v", path, err)
+	}
+	want := *cfg.typeMeta()
+	if meta.APIVersion != want.APIVersion {
+		return component.ConfigErrorf("%s: unsupported apiVersion %q, expected %q", path, meta.APIVersion, want.APIVersion)
+	}
+	if meta.Kind != want.Kind {
+		return component.ConfigErrorf("%s: kind %q is not %q", path, meta.Kind, want.Kind)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return component.ConfigErrorf("failed to parse %s: %v", path, err)
+	}
+	return nil
+}
+
+// env reads environment variables over configuration fields, leaving
+// fields alone when their variable is unset.
+type env struct {
+	errs []string
+}
+
+func (e *env) string(name string, dst *string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*dst = value
+	}
+}
+
+func (e *env) list(name string, dst *[]string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*dst = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*dst = append(*dst, item)
+			}
+		}
+	}
+}
+
+func (e *env) int(name string, dst *int) {
+	if value, ok := os.LookupEnv(name); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s=%q is not a number", name, value))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *env) bool(name string, dst *bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s=%q is not true or false", name, value))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (e *env) duration(name string, dst *time.Duration) {
+	if value, ok := os.LookupEnv(name); ok {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s=%q is not a duration", name, value))
+			return
+		}
+		*dst = d
+	}
+}
+
+// serving reads <prefix>BIND_ADDRESS, <prefix>PORT and
+// <prefix>SHUTDOWN_TIMEOUT.
+func (e *env) serving(prefix string, dst *component.ServingOptions) {
+	e.string(prefix+"BIND_ADDRESS", &dst.BindAddress)
+	e.int(prefix+"PORT", &dst.Port)
+	e.duration(prefix+"SHUTDOWN_TIMEOUT", &dst.ShutdownTimeout)
+}
+
+// clientConnection reads API_HOST, API_PORT, API_TOKEN, API_CLIENT_CERT,
+// API_CLIENT_KEY and API_CA_FILE.
+func (e *env) clientConnection(dst *ClientConnection) {
+	e.string("API_HOST", &dst.Host)
+	e.string("API_PORT", &dst.Port)
+	e.string("API_TOKEN", &dst.Token)
+	e.string("API_CLIENT_CERT", &dst.CertFile)
+	e.string("API_CLIENT_KEY", &dst.KeyFile)
+	e.string("API_CA_FILE", &dst.CAFile)
+}
+
+func (e *env) err() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return component.ConfigErrorf("%s", strings.Join(e.errs, "; "))
+}
+
+// validator collects every problem with a configuration, so they can be
+// fixed in one go.
+type validator struct {
+	errs []string
+}
+
+func (v *validator) require(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) port(field, port string) {
+	n, err := strconv.Atoi(port)
+	v.require(err == nil && n >= 1 && n <= 65535, "%s %q is not a port", field, port)
+}
+
+func (v *validator) ip(field, ip string, optional bool) {
+	if ip == "" {
+		v.require(optional, "%s is required", field)
+		return
+	}
+	v.require(net.ParseIP(ip) != nil, "%s %q is not an IP address", field, ip)
+}
+
+func (v *validator) positive(field string, d time.Duration) {
+	v.require(d > 0, "%s must be positive, got %s", field, d)
+}
+
+func (v *validator) serving(field string, o component.ServingOptions) {
+	if err := o.Validate(); err != nil {
+		v.errs = append(v.errs, field+": "+err.Error())
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return component.ConfigErrorf("%s", strings.Join(v.errs, "; "))
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// LeaderElectionConfiguration says whether replicas of a component elect
+// one of them to act, and through which lease.
+type LeaderElectionConfiguration struct {
+	LeaderElect bool `yaml:"leaderElect"`
+	// LeaseDuration is how long standby replicas wait after the leader's
+	// last renewal before taking over.
+	LeaseDuration time.Duration `yaml:"leaseDuration"`
+	// RenewDeadline is how long the leader retries renewing before it
+	// stops leading.
+	RenewDeadline time.Duration `yaml:"renewDeadline"`
+	// RetryPeriod is how often the lease is tried or renewed.
+	RetryPeriod       time.Duration `yaml:"retryPeriod"`
+	ResourceNamespace string        `yaml:"resourceNamespace"`
+	ResourceName      string        `yaml:"resourceName"`
+}
+
+func defaultLeaderElection(name string) LeaderElectionConfiguration {
+	return LeaderElectionConfiguration{
+		LeaderElect:       true,
+		LeaseDuration:     15 * time.Second,
+		RenewDeadline:     10 * time.Second,
+		RetryPeriod:       2 * time.Second,
+		ResourceNamespace: "kube-system",
+		ResourceName:      name,
+	}
+}
+
+// AddFlags registers the --leader-elect flags, defaulting to the current
+// values of c.
+func (c *LeaderElectionConfiguration) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&c.LeaderElect, "leader-elect", c.LeaderElect, "Elect a leader among the replicas, so only one acts at a time")
+	fs.DurationVar(&c.LeaseDuration, "leader-elect-lease-duration", c.LeaseDuration, "How long standby replicas wait after the leader's last renewal before taking over")
+	fs.DurationVar(&c.RenewDeadline, "leader-elect-renew-deadline", c.RenewDeadline, "How long the leader retries renewing its lease before it stops leading")
+	fs.DurationVar(&c.RetryPeriod, "leader-elect-retry-period", c.RetryPeriod, "How often the lease is tried or renewed")
+	fs.StringVar(&c.ResourceNamespace, "leader-elect-resource-namespace", c.ResourceNamespace, "Namespace of the lease used for leader election")
+	fs.StringVar(&c.ResourceName, "leader-elect-resource-name", c.ResourceName, "Name of the lease used for leader election")
+}
+
+func (c *LeaderElectionConfiguration) validate(v *validator) {
+	if !c.LeaderElect {
+		return
+	}
+	v.positive("leaderElection.retryPeriod", c.RetryPeriod)
+	v.require(c.RetryPeriod < c.RenewDeadline, "leaderElection.retryPeriod must be shorter than renewDeadline")
+	v.require(c.RenewDeadline < c.LeaseDuration, "leaderElection.renewDeadline must be shorter than leaseDuration")
+	v.require(c.ResourceNamespace != "", "leaderElection.resourceNamespace is required")
+	v.require(c.ResourceName != "", "leaderElection.resourceName is required")
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/spf13/pflag"
+)
+
+// NodeAgentConfiguration configures the node agent, and the node server
+// that runs it.
+type NodeAgentConfiguration struct {
+	TypeMeta `yaml:",inline"`
+
+	NodeName         string           `yaml:"nodeName"`
+	NodeIP           string           `yaml:"nodeIP"`
+	ClientConnection ClientConnection `yaml:"clientConnection"`
+
+	// Serving is where the node server listens. An empty bind address
+	// means the node IP. The node agent alone only uses ShutdownTimeout.
+	Serving component.ServingOptions `yaml:"serving"`
+	// TLS makes the node server serve https.
+	TLS NodeTLSConfiguration `yaml:"tls"`
+
+	// HeartbeatInterval is how often the node status is reported.
+	HeartbeatInterval time.Duration `yaml:"heartbeatInterval"`
+	// PodSyncInterval is how often the agent starts pods assigned to the
+	// node and cleans up deleted ones.
+	PodSyncInterval time.Duration `yaml:"podSyncInterval"`
+	// ContainerCheckInterval is how often the node server checks that
+	// running pods' containers are still up.
+	ContainerCheckInterval time.Duration `yaml:"containerCheckInterval"`
+}
+
+type NodeTLSConfiguration struct {
+	CertFile string `yaml:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty"`
+	// ClientCAFile makes callers present a client certificate signed by
+	// this CA.
+	ClientCAFile string `yaml:"clientCAFile,omitempty"`
+	// AllowedClientNames and AllowedClientGroups are the certificate
+	// subjects (CN) and organizations (O) allowed to read logs of, exec into
+	// and forward ports of pods on the node. Only the API server is allowed by default,
+	// so callers go through its authorization.
+	AllowedClientNames  []string `yaml:"allowedClientNames,omitempty"`
+	AllowedClientGroups []string `yaml:"allowedClientGroups,omitempty"`
+}
+
+// DefaultNodeAgentConfiguration returns the settings used when nothing
+// else is given.
+func DefaultNodeAgentConfiguration() *NodeAgentConfiguration {
+	return &NodeAgentConfiguration{
+		TypeMeta:               TypeMeta{APIVersion: GroupVersion, Kind: "NodeAgentConfiguration"},
+		ClientConnection:       defaultClientConnection(),
+		Serving:                component.ServingOptions{Port: 8081, ShutdownTimeout: component.DefaultShutdownTimeout},
+		TLS:                    NodeTLSConfiguration{AllowedClientNames: []string{auth.KubeletClientUser}},
+		HeartbeatInterval:      30 * time.Second,
+		PodSyncInterval:        10 * time.Second,
+		ContainerCheckInterval: 5 * time.Second,
+	}
+}
+
+// AddFlags binds the node server's own flags to c. The node name is its
+// argument and the connection comes from the CLI's --api-host and related
+// flags.
+func (c *NodeAgentConfiguration) AddFlags(fs *pflag.FlagSet) {
+	c.Serving.AddFlags(fs)
+	fs.StringVar(&c.NodeIP, "node-ip", c.NodeIP, "IP address of this node")
+	fs.StringVar(&c.TLS.CertFile, "tls-cert-file", c.TLS.CertFile, "Serving certificate for the node server, enables https")
+	fs.StringVar(&c.TLS.KeyFile, "tls-private-key-file", c.TLS.KeyFile, "Key for --tls-cert-file")
+	fs.StringVar(&c.TLS.ClientCAFile, "client-ca-file", c.TLS.ClientCAFile, "Require callers to present a client certificate signed by this CA")
+	fs.StringSliceVar(&c.TLS.AllowedClientNames, "allowed-client-names", c.TLS.AllowedClientNames, "Client certificate names allowed to read logs of, exec into and forward ports of pods")
+	fs.StringSliceVar(&c.TLS.AllowedClientGroups, "allowed-client-groups", c.TLS.AllowedClientGroups, "Client certificate groups allowed to read logs of, exec into and forward ports of pods")
+	fs.DurationVar(&c.HeartbeatInterval, "heartbeat-interval", c.HeartbeatInterval, "How often to report the node status")
+	AddFlags(fs)
+}
+
+func (c *NodeAgentConfiguration) applyEnv() error {
+	var e env
+	e.string("NODE_NAME", &c.NodeName)
+	e.string("NODE_IP", &c.NodeIP)
+	e.clientConnection(&c.ClientConnection)
+	e.serving("NODE_SERVER_", &c.Serving)
+	return e.err()
+}
+
+// Validate checks every field and reports all problems at once.
+func (c *NodeAgentConfiguration) Validate() error {
+	var v validator
+	v.require(c.NodeName != "", "nodeName is required")
+	v.ip("nodeIP", c.NodeIP, false)
+	c.ClientConnection.validate(&v)
+	v.serving("serving", c.Serving)
+	v.require((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.certFile and tls.keyFile must be set together")
+	v.require(c.TLS.ClientCAFile == "" || c.TLS.CertFile != "", "tls.clientCAFile requires tls.certFile and tls.keyFile")
+	v.positive("heartbeatInterval", c.HeartbeatInterval)
+	v.positive("podSyncInterval", c.PodSyncInterval)
+	v.positive("containerCheckInterval", c.ContainerCheckInterval)
+	return v.err()
+}
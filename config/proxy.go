@@ -0,0 +1,54 @@
+package config
+
+import (
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/spf13/pflag"
+)
+
+// ProxyConfiguration configures the standalone NodePort proxy.
+type ProxyConfiguration struct {
+	TypeMeta `yaml:",inline"`
+
+	ClientConnection ClientConnection `yaml:"clientConnection"`
+	// BindAddress is the IP NodePort services listen on; empty means all
+	// interfaces.
+	BindAddress     string        `yaml:"bindAddress"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+}
+
+// DefaultProxyConfiguration returns the settings used when nothing else
+// is given.
+func DefaultProxyConfiguration() *ProxyConfiguration {
+	return &ProxyConfiguration{
+		TypeMeta:         TypeMeta{APIVersion: GroupVersion, Kind: "ProxyConfiguration"},
+		ClientConnection: defaultClientConnection(),
+		ShutdownTimeout:  component.DefaultShutdownTimeout,
+	}
+}
+
+// AddFlags binds the proxy's flags to c.
+func (c *ProxyConfiguration) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&c.ClientConnection.Host, "api-host", c.ClientConnection.Host, "API server host")
+	fs.StringVar(&c.ClientConnection.Port, "api-port", c.ClientConnection.Port, "API server port")
+	fs.StringVar(&c.BindAddress, "bind-address", c.BindAddress, "IP address NodePort services listen on, empty for all interfaces")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "How long to drain requests on SIGTERM")
+	AddFlags(fs)
+}
+
+func (c *ProxyConfiguration) applyEnv() error {
+	var e env
+	e.clientConnection(&c.ClientConnection)
+	e.string("PROXY_BIND_ADDRESS", &c.BindAddress)
+	return e.err()
+}
+
+// Validate checks every field and reports all problems at once.
+func (c *ProxyConfiguration) Validate() error {
+	var v validator
+	c.ClientConnection.validate(&v)
+	v.ip("bindAddress", c.BindAddress, true)
+	v.require(c.ShutdownTimeout >= 0, "shutdownTimeout must not be negative")
+	return v.err()
+}
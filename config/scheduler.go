@@ -0,0 +1,67 @@
+package config
+
+import (
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/spf13/pflag"
+)
+
+// SchedulerConfiguration configures the scheduler.
+type SchedulerConfiguration struct {
+	TypeMeta `yaml:",inline"`
+
+	ClientConnection ClientConnection `yaml:"clientConnection"`
+	// PollInterval is how often pending pods are looked for.
+	PollInterval    time.Duration `yaml:"pollInterval"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// LeaderElection lets several schedulers run with one scheduling at
+	// a time.
+	LeaderElection LeaderElectionConfiguration `yaml:"leaderElection"`
+	// HealthBindAddress and HealthPort are where /healthz, /livez and
+	// /readyz are served; port 0 turns them off.
+	HealthBindAddress string `yaml:"healthBindAddress"`
+	HealthPort        int    `yaml:"healthPort"`
+}
+
+// DefaultSchedulerConfiguration returns the settings used when nothing
+// else is given.
+func DefaultSchedulerConfiguration() *SchedulerConfiguration {
+	return &SchedulerConfiguration{
+		TypeMeta:         TypeMeta{APIVersion: GroupVersion, Kind: "SchedulerConfiguration"},
+		ClientConnection: defaultClientConnection(),
+		PollInterval:     5 * time.Second,
+		ShutdownTimeout:  component.DefaultShutdownTimeout,
+		LeaderElection:   defaultLeaderElection("mykube-scheduler"),
+		HealthPort:       10259,
+	}
+}
+
+// AddFlags binds the scheduler's own flags to c. The connection comes
+// from the CLI's --api-host and related flags.
+func (c *SchedulerConfiguration) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&c.PollInterval, "poll-interval", c.PollInterval, "How often to look for pending pods")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "How long to wait for the scheduling loop to stop on SIGTERM")
+	fs.StringVar(&c.HealthBindAddress, "health-bind-address", c.HealthBindAddress, "IP address to serve health checks on, empty for all interfaces")
+	fs.IntVar(&c.HealthPort, "health-port", c.HealthPort, "Port to serve health checks on, 0 to turn them off")
+	c.LeaderElection.AddFlags(fs)
+	AddFlags(fs)
+}
+
+func (c *SchedulerConfiguration) applyEnv() error {
+	var e env
+	e.clientConnection(&c.ClientConnection)
+	return e.err()
+}
+
+// Validate checks every field and reports all problems at once.
+func (c *SchedulerConfiguration) Validate() error {
+	var v validator
+	c.ClientConnection.validate(&v)
+	v.positive("pollInterval", c.PollInterval)
+	v.require(c.ShutdownTimeout >= 0, "shutdownTimeout must not be negative")
+	v.ip("healthBindAddress", c.HealthBindAddress, true)
+	v.require(c.HealthPort >= 0 && c.HealthPort <= 65535, "healthPort %d is out of range 0-65535", c.HealthPort)
+	c.LeaderElection.validate(&v)
+	return v.err()
+}
@@ -0,0 +1,141 @@
+// Package diff computes line-based unified diffs, as printed by diff -u.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is how many unchanged lines surround each change.
+const contextLines = 3
+
+// edit is one line of an edit script: kept (' '), removed ('-') or added
+// ('+'). a is the index of the line in the old text, or of the next old
+// line for additions; b the same in the new text.
+type edit struct {
+	op   byte
+	line string
+	a, b int
+}
+
+// Unified returns the unified diff turning from into to, or "" when they
+// are equal. fromName and toName label the --- and +++ lines.
+func Unified(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	edits := lineEdits(splitLines(from), splitLines(to))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		// A hunk runs from the context before its first change to the
+		// context after its last one, joining changes whose contexts meet
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		last := i
+		for j := i; j < len(edits) && j-last <= 2*contextLines+1; j++ {
+			if edits[j].op != ' ' {
+				last = j
+			}
+		}
+		end := last + contextLines + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+		writeHunk(&sb, edits[start:end])
+		i = end
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []edit) {
+	var fromLines, toLines int
+	for _, e := range edits {
+		if e.op != '+' {
+			fromLines++
+		}
+		if e.op != '-' {
+			toLines++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(edits[0].a, fromLines), hunkRange(edits[0].b, toLines))
+	for _, e := range edits {
+		sb.WriteByte(e.op)
+		sb.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the start and length of a hunk's lines. Empty ranges
+// start at the line before them, as in diff -u.
+func hunkRange(start, lines int) string {
+	if lines == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if lines == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, lines)
+}
+
+// splitLines splits text into lines, each keeping its newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineEdits returns the shortest edit script turning a into b, from their
+// longest common subsequence. Objects are small enough for the quadratic
+// table.
+func lineEdits(a, b []string) []edit {
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
@@ -0,0 +1,369 @@
+// Package fieldmanager implements server-side apply: it records which
+// manager owns each field of an object in metadata.managedFields, merges
+// applied configurations and detects when two managers want different
+// values for the same field.
+package fieldmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+)
+
+// Conflict is a field another manager owns with a different value.
+type Conflict struct {
+	Manager string
+	Path    Path
+}
+
+// ConflictError is returned by Apply when the configuration would
+// overwrite fields owned by other managers.
+type ConflictError []Conflict
+
+func (e ConflictError) Error() string {
+	noun := "conflicts"
+	if len(e) == 1 {
+		noun = "conflict"
+	}
+	var parts []string
+	for _, c := range e {
+		parts = append(parts, fmt.Sprintf("conflict with %q: %s", c.Manager, c.Path))
+	}
+	return fmt.Sprintf("Apply failed with %d %s: %s", len(e), noun, strings.Join(parts, "; "))
+}
+
+// managedEntry is a ManagedFieldsEntry with its fields decoded.
+type managedEntry struct {
+	models.ManagedFieldsEntry
+	fields Set
+}
+
+// Apply merges applied, the full configuration manager wants, into live
+// and returns the result. live is nil when the object does not exist yet.
+//
+// Fields that manager applied before but left out of applied are removed
+// unless another manager still owns them. Fields that another manager owns
+// with a different value make Apply fail with a ConflictError, unless
+// force is set, in which case manager takes them over.
+func Apply(live, applied []byte, manager string, force bool, mergeKeys patch.MergeKeys, now time.Time) ([]byte, error) {
+	if live == nil {
+		live = []byte("{}")
+	}
+	liveObj, entries, err := decodeObject(live)
+	if err != nil {
+		return nil, err
+	}
+	appliedObj, err := decodeMap(applied)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %v", err)
+	}
+	appliedFields := FieldsOf(appliedObj, mergeKeys)
+
+	var conflicts ConflictError
+	var mine *managedEntry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Manager == manager && entry.Operation == models.ManagedFieldsOperationApply {
+			mine = entry
+			continue
+		}
+		for _, p := range entry.fields.Intersection(appliedFields).Paths() {
+			// Owning a list item only means owning its key, which
+			// matched
+			if strings.HasPrefix(p[len(p)-1], "k:") {
+				continue
+			}
+			current, ok := valueAt(liveObj, p)
+			if !ok {
+				continue
+			}
+			wanted, _ := valueAt(appliedObj, p)
+			if patch.Equal(current, wanted) {
+				continue
+			}
+			if force {
+				entry.fields.Delete(p)
+				continue
+			}
+			conflicts = append(conflicts, Conflict{Manager: entry.Manager, Path: p})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+
+	if mine != nil {
+		others := Set{}
+		for i := range entries {
+			if &entries[i] != mine {
+				others = others.Union(entries[i].fields)
+			}
+		}
+		// Parents come first, so a dropped list item goes before its
+		// fields, which are then gone with it
+		for _, p := range mine.fields.Difference(appliedFields).Paths() {
+			if !others.HasPrefix(p) {
+				liveObj = removeAt(liveObj, p).(map[string]interface{})
+			}
+		}
+	} else {
+		entries = append(entries, managedEntry{ManagedFieldsEntry: models.ManagedFieldsEntry{
+			Manager:   manager,
+			Operation: models.ManagedFieldsOperationApply,
+		}})
+		mine = &entries[len(entries)-1]
+	}
+	mine.fields = appliedFields
+	mine.APIVersion, _ = appliedObj["apiVersion"].(string)
+	mine.Time = now.UTC().Format(time.RFC3339)
+
+	liveJSON, err := json.Marshal(liveObj)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := patch.StrategicMerge(liveJSON, applied, mergeKeys)
+	if err != nil {
+		return nil, err
+	}
+	return withManagedFields(merged, entries)
+}
+
+// Update records that manager changed live into updated with a plain
+// create, update or patch. The changed fields move to manager; updates
+// never conflict.
+func Update(live, updated []byte, manager string, mergeKeys patch.MergeKeys, now time.Time) ([]byte, error) {
+	liveObj, entries, err := decodeObject(live)
+	if err != nil {
+		return nil, err
+	}
+	updatedObj, _, err := decodeObject(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	liveFields := FieldsOf(liveObj, mergeKeys)
+	updatedFields := FieldsOf(updatedObj, mergeKeys)
+
+	changed := Set{}
+	for _, p := range updatedFields {
+		current, ok := valueAt(liveObj, p)
+		if !ok {
+			changed.Insert(p)
+			continue
+		}
+		// An existing list item only changes through its fields
+		if strings.HasPrefix(p[len(p)-1], "k:") {
+			continue
+		}
+		if wanted, _ := valueAt(updatedObj, p); !patch.Equal(current, wanted) {
+			changed.Insert(p)
+		}
+	}
+	removed := liveFields.Difference(updatedFields)
+	if len(changed) == 0 && len(removed) == 0 {
+		return withManagedFields(updated, entries)
+	}
+
+	var mine *managedEntry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Manager == manager && entry.Operation == models.ManagedFieldsOperationUpdate {
+			mine = entry
+		}
+		entry.fields = entry.fields.Difference(changed).Difference(removed)
+	}
+	if mine == nil {
+		entries = append(entries, managedEntry{ManagedFieldsEntry: models.ManagedFieldsEntry{
+			Manager:   manager,
+			Operation: models.ManagedFieldsOperationUpdate,
+		}, fields: Set{}})
+		mine = &entries[len(entries)-1]
+	}
+	mine.fields = mine.fields.Union(changed)
+	mine.Time = now.UTC().Format(time.RFC3339)
+
+	return withManagedFields(updated, entries)
+}
+
+// decodeObject decodes an object and its managed fields, returning the
+// object without metadata.managedFields.
+func decodeObject(data []byte) (map[string]interface{}, []managedEntry, error) {
+	obj, err := decodeMap(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	raw, ok := metadata["managedFields"]
+	if !ok {
+		return obj, nil, nil
+	}
+	delete(metadata, "managedFields")
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	var list []models.ManagedFieldsEntry
+	if err := json.Unmarshal(encoded, &list); err != nil {
+		return nil, nil, fmt.Errorf("invalid managedFields: %v", err)
+	}
+	entries := make([]managedEntry, 0, len(list))
+	for _, entry := range list {
+		fields, err := ParseFields(entry.FieldsV1)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, managedEntry{ManagedFieldsEntry: entry, fields: fields})
+	}
+	return obj, entries, nil
+}
+
+func decodeMap(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("expected an object")
+	}
+	return obj, nil
+}
+
+// withManagedFields stores entries, dropping those that own nothing, in
+// the metadata of the JSON object data.
+func withManagedFields(data []byte, entries []managedEntry) ([]byte, error) {
+	obj, err := decodeMap(data)
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+
+	var list []models.ManagedFieldsEntry
+	for _, entry := range entries {
+		if len(entry.fields) == 0 {
+			continue
+		}
+		fields, err := json.Marshal(entry.fields)
+		if err != nil {
+			return nil, err
+		}
+		entry.FieldsType = "FieldsV1"
+		entry.FieldsV1 = fields
+		list = append(list, entry.ManagedFieldsEntry)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Operation == models.ManagedFieldsOperationApply && list[j].Operation != models.ManagedFieldsOperationApply
+	})
+	if len(list) > 0 {
+		metadata["managedFields"] = list
+	} else {
+		delete(metadata, "managedFields")
+	}
+	return json.Marshal(obj)
+}
+
+// valueAt returns the value at p in obj.
+func valueAt(obj interface{}, p Path) (interface{}, bool) {
+	value := obj
+	for _, elem := range p {
+		switch {
+		case strings.HasPrefix(elem, "f:"):
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = m[elem[2:]]; !ok {
+				return nil, false
+			}
+		case strings.HasPrefix(elem, "k:"):
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i := itemIndex(list, elem)
+			if i < 0 {
+				return nil, false
+			}
+			value = list[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// removeAt returns value with the field or list item at p removed.
+func removeAt(value interface{}, p Path) interface{} {
+	if len(p) == 0 {
+		return value
+	}
+	elem := p[0]
+	switch {
+	case strings.HasPrefix(elem, "f:"):
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		field := elem[2:]
+		if len(p) == 1 {
+			delete(m, field)
+		} else if child, ok := m[field]; ok {
+			m[field] = removeAt(child, p[1:])
+		}
+		return m
+	case strings.HasPrefix(elem, "k:"):
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		i := itemIndex(list, elem)
+		if i < 0 {
+			return value
+		}
+		if len(p) == 1 {
+			return append(list[:i], list[i+1:]...)
+		}
+		list[i] = removeAt(list[i], p[1:])
+		return list
+	}
+	return value
+}
+
+// itemIndex finds the list item matching a k: path element.
+func itemIndex(list []interface{}, elem string) int {
+	var key map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(elem[2:]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&key); err != nil {
+		return -1
+	}
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match := true
+		for field, want := range key {
+			if !patch.Equal(obj[field], want) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,275 @@
+package fieldmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+)
+
+// Path addresses a field in an object. Elements are "f:<field>" for an
+// object field and "k:<json>" for the list item whose merge key has the
+// given value, e.g. k:{"name":"nginx"}.
+type Path []string
+
+func (p Path) key() string { return strings.Join(p, "\x00") }
+
+// String renders the path for messages, e.g. .spec.containers[name="nginx"].image
+func (p Path) String() string {
+	var b strings.Builder
+	for _, elem := range p {
+		switch {
+		case strings.HasPrefix(elem, "f:"):
+			b.WriteString("." + elem[2:])
+		case strings.HasPrefix(elem, "k:"):
+			var key map[string]interface{}
+			if err := json.Unmarshal([]byte(elem[2:]), &key); err != nil {
+				b.WriteString("[" + elem[2:] + "]")
+				continue
+			}
+			var parts []string
+			for field, value := range key {
+				v, _ := json.Marshal(value)
+				parts = append(parts, fmt.Sprintf("%s=%s", field, v))
+			}
+			sort.Strings(parts)
+			b.WriteString("[" + strings.Join(parts, ",") + "]")
+		default:
+			b.WriteString("." + elem)
+		}
+	}
+	return b.String()
+}
+
+func (p Path) child(elem string) Path {
+	return append(append(Path(nil), p...), elem)
+}
+
+// hasPrefix reports whether p is prefix or lies below it.
+func (p Path) hasPrefix(prefix Path) bool {
+	if len(p) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// dotted returns the field names of p joined by dots, the form MergeKeys
+// is keyed by.
+func (p Path) dotted() string {
+	var fields []string
+	for _, elem := range p {
+		if strings.HasPrefix(elem, "f:") {
+			fields = append(fields, elem[2:])
+		}
+	}
+	return strings.Join(fields, ".")
+}
+
+// Set is a set of field paths.
+type Set map[string]Path
+
+func (s Set) Insert(p Path) { s[p.key()] = p }
+
+func (s Set) Has(p Path) bool {
+	_, ok := s[p.key()]
+	return ok
+}
+
+func (s Set) Delete(p Path) { delete(s, p.key()) }
+
+// Union returns the paths in s or other.
+func (s Set) Union(other Set) Set {
+	result := Set{}
+	for k, p := range s {
+		result[k] = p
+	}
+	for k, p := range other {
+		result[k] = p
+	}
+	return result
+}
+
+// Difference returns the paths in s that are not in other.
+func (s Set) Difference(other Set) Set {
+	result := Set{}
+	for k, p := range s {
+		if _, ok := other[k]; !ok {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+// Intersection returns the paths in both s and other.
+func (s Set) Intersection(other Set) Set {
+	result := Set{}
+	for k, p := range s {
+		if _, ok := other[k]; ok {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+// HasPrefix reports whether s holds prefix or any path below it.
+func (s Set) HasPrefix(prefix Path) bool {
+	for _, p := range s {
+		if p.hasPrefix(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths returns the paths sorted, parents before their children.
+func (s Set) Paths() []Path {
+	paths := make([]Path, 0, len(s))
+	for _, p := range s {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].key() < paths[j].key() })
+	return paths
+}
+
+// fieldsNode is one level of the FieldsV1 tree.
+type fieldsNode struct {
+	member   bool
+	children map[string]*fieldsNode
+}
+
+// MarshalJSON encodes s in the FieldsV1 format: a tree of path elements
+// where {} marks a leaf and "." marks an inner node that is itself in the
+// set.
+func (s Set) MarshalJSON() ([]byte, error) {
+	root := &fieldsNode{children: map[string]*fieldsNode{}}
+	for _, p := range s {
+		node := root
+		for _, elem := range p {
+			child, ok := node.children[elem]
+			if !ok {
+				child = &fieldsNode{children: map[string]*fieldsNode{}}
+				node.children[elem] = child
+			}
+			node = child
+		}
+		node.member = true
+	}
+	return json.Marshal(root.value())
+}
+
+func (n *fieldsNode) value() map[string]interface{} {
+	value := make(map[string]interface{}, len(n.children)+1)
+	if n.member && len(n.children) > 0 {
+		value["."] = map[string]interface{}{}
+	}
+	for elem, child := range n.children {
+		value[elem] = child.value()
+	}
+	return value
+}
+
+// ParseFields decodes a FieldsV1 tree.
+func ParseFields(data []byte) (Set, error) {
+	s := Set{}
+	if len(data) == 0 {
+		return s, nil
+	}
+	var tree map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("invalid fieldsV1: %v", err)
+	}
+	if err := s.parse(nil, tree); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s Set) parse(prefix Path, tree map[string]json.RawMessage) error {
+	if len(tree) == 0 && len(prefix) > 0 {
+		s.Insert(prefix)
+	}
+	for elem, raw := range tree {
+		if elem == "." {
+			s.Insert(prefix)
+			continue
+		}
+		var child map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &child); err != nil {
+			return fmt.Errorf("invalid fieldsV1 at %s: %v", prefix.child(elem), err)
+		}
+		if err := s.parse(prefix.child(elem), child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FieldsOf returns the paths of every value set in obj. Objects are walked
+// field by field, list items with a merge key are tracked by key and any
+// other list counts as a single value. apiVersion, kind and
+// metadata.managedFields are not tracked.
+func FieldsOf(obj map[string]interface{}, mergeKeys patch.MergeKeys) Set {
+	s := Set{}
+	for field, value := range obj {
+		if field == "apiVersion" || field == "kind" {
+			continue
+		}
+		s.walk(value, Path{"f:" + field}, mergeKeys)
+	}
+	s.Delete(Path{"f:metadata", "f:managedFields"})
+	return s
+}
+
+func (s Set) walk(value interface{}, path Path, mergeKeys patch.MergeKeys) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			s.Insert(path)
+		}
+		for field, child := range v {
+			s.walk(child, path.child("f:"+field), mergeKeys)
+		}
+	case []interface{}:
+		mergeKey, ok := mergeKeys[path.dotted()]
+		if !ok || !keyedItems(v, mergeKey) {
+			s.Insert(path)
+			return
+		}
+		for _, item := range v {
+			obj := item.(map[string]interface{})
+			itemPath := path.child(keyElement(mergeKey, obj[mergeKey]))
+			s.Insert(itemPath)
+			for field, child := range obj {
+				s.walk(child, itemPath.child("f:"+field), mergeKeys)
+			}
+		}
+	default:
+		s.Insert(path)
+	}
+}
+
+// keyedItems reports whether every item is an object carrying mergeKey.
+func keyedItems(items []interface{}, mergeKey string) bool {
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := obj[mergeKey]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func keyElement(mergeKey string, value interface{}) string {
+	data, _ := json.Marshal(map[string]interface{}{mergeKey: value})
+	return "k:" + string(data)
+}
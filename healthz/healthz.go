@@ -0,0 +1,131 @@
+// Package healthz serves /healthz, /livez and /readyz style endpoints made
+// of named checks.
+//
+//	GET /readyz                 "ok", or 500 listing the failed checks
+//	GET /readyz?verbose         every check and its result
+//	GET /readyz?exclude=storage skips a check, may be repeated
+//	GET /readyz/storage         runs a single check
+package healthz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Checker is one named health check.
+type Checker interface {
+	Name() string
+	Check(r *http.Request) error
+}
+
+type namedCheck struct {
+	name  string
+	check func(r *http.Request) error
+}
+
+func (c namedCheck) Name() string                { return c.name }
+func (c namedCheck) Check(r *http.Request) error { return c.check(r) }
+
+// NamedCheck turns a function into a Checker.
+func NamedCheck(name string, check func(r *http.Request) error) Checker {
+	return namedCheck{name: name, check: check}
+}
+
+// Ping always passes; it shows the server answers at all.
+var Ping = NamedCheck("ping", func(*http.Request) error { return nil })
+
+// Signal is a check that fails until Set is called, for one-off startup
+// work such as an initial sync.
+type Signal struct {
+	name string
+	done atomic.Bool
+}
+
+func NewSignal(name string) *Signal { return &Signal{name: name} }
+
+func (s *Signal) Name() string { return s.name }
+
+// Set marks the work as done.
+func (s *Signal) Set() { s.done.Store(true) }
+
+func (s *Signal) Check(*http.Request) error {
+	if !s.done.Load() {
+		return fmt.Errorf("not finished")
+	}
+	return nil
+}
+
+// Handler runs checks and reports them as the endpoint called name, e.g.
+// "readyz". It serves both /name and /name/<check>, so mount it on the
+// path and everything below it.
+func Handler(name string, checks ...Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if single := strings.TrimPrefix(r.URL.Path, "/"+name+"/"); single != r.URL.Path {
+			serveCheck(w, r, single, checks)
+			return
+		}
+		if r.URL.Path != "/"+name {
+			http.NotFound(w, r)
+			return
+		}
+
+		excluded := map[string]bool{}
+		for _, exclude := range r.URL.Query()["exclude"] {
+			excluded[exclude] = true
+		}
+		_, verbose := r.URL.Query()["verbose"]
+
+		var out bytes.Buffer
+		failed := false
+		for _, check := range checks {
+			if excluded[check.Name()] {
+				fmt.Fprintf(&out, "[+]%s excluded: ok\n", check.Name())
+				delete(excluded, check.Name())
+				continue
+			}
+			if err := check.Check(r); err != nil {
+				fmt.Fprintf(&out, "[-]%s failed: %v\n", check.Name(), err)
+				failed = true
+				continue
+			}
+			fmt.Fprintf(&out, "[+]%s ok\n", check.Name())
+		}
+		for exclude := range excluded {
+			fmt.Fprintf(&out, "warn: some health checks cannot be excluded: no matches for %q\n", exclude)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%s%s check failed\n", out.String(), name)
+			return
+		}
+		if verbose {
+			fmt.Fprintf(w, "%s%s check passed\n", out.String(), name)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+}
+
+// serveCheck runs the one check called name.
+func serveCheck(w http.ResponseWriter, r *http.Request, name string, checks []Checker) {
+	for _, check := range checks {
+		if check.Name() != name {
+			continue
+		}
+		if err := check.Check(r); err != nil {
+			http.Error(w, fmt.Sprintf("internal server error: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fmt.Fprint(w, "ok")
+		return
+	}
+	http.NotFound(w, r)
+}
@@ -0,0 +1,278 @@
+// Package leaderelection lets one replica of a component act at a time. The
+// replicas race to hold a Lease through the API server: the holder renews
+// it while it leads, and the others take it over once it has gone
+// unrenewed for the lease duration.
+//
+// Expiry is judged by the local clock from when a replica last saw the
+// lease change, never by the times written in it, so replicas need not
+// agree on the time.
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/client"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// ErrLeaseLost is returned by Run when the lease could not be renewed
+// within the renew deadline.
+var ErrLeaseLost = errors.New("leader election lost")
+
+// healthTolerance is how far past the lease duration a leader may go
+// without renewing before its health check fails.
+const healthTolerance = 20 * time.Second
+
+// LeaseClient reads and writes leases, see client.Client.
+type LeaseClient interface {
+	GetLease(ctx context.Context, namespace, name string) (*models.Lease, error)
+	CreateLease(ctx context.Context, lease models.Lease) (*models.Lease, error)
+	UpdateLease(ctx context.Context, lease models.Lease) (*models.Lease, error)
+}
+
+// Config names the lease and sets the timing of an election.
+type Config struct {
+	Namespace string
+	Name      string
+	// Identity names this replica in the lease; it must differ between
+	// replicas
+	Identity string
+
+	// LeaseDuration is how long standby replicas wait after the last
+	// renewal before taking the lease over
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader keeps retrying a renewal
+	// before it gives up leading; shorter than LeaseDuration
+	RenewDeadline time.Duration
+	// RetryPeriod is how often the lease is tried or renewed
+	RetryPeriod time.Duration
+}
+
+// Elector runs one replica's side of an election.
+type Elector struct {
+	config Config
+	client LeaseClient
+
+	mu           sync.Mutex
+	observed     models.LeaseSpec // the lease as last read or written
+	observedTime time.Time        // local time observed last changed
+	lease        *models.Lease    // the stored lease, nil before the first read
+	leading      bool
+}
+
+// New returns an Elector for config.
+func New(c LeaseClient, config Config) (*Elector, error) {
+	if config.Name == "" || config.Identity == "" {
+		return nil, fmt.Errorf("leader election needs a lease name and an identity")
+	}
+	if config.RenewDeadline >= config.LeaseDuration {
+		return nil, fmt.Errorf("leader election renew deadline %s must be shorter than the lease duration %s",
+			config.RenewDeadline, config.LeaseDuration)
+	}
+	if config.RetryPeriod <= 0 || config.RetryPeriod >= config.RenewDeadline {
+		return nil, fmt.Errorf("leader election retry period %s must be positive and shorter than the renew deadline %s",
+			config.RetryPeriod, config.RenewDeadline)
+	}
+	return &Elector{config: config, client: c}, nil
+}
+
+// Run waits until this replica holds the lease, then calls lead and keeps
+// renewing the lease until ctx is cancelled. lead's context is cancelled
+// when leading ends, and Run returns once lead has. A lease that was held
+// is released on the way out, so another replica can take over at once.
+//
+// Run returns nil when ctx is cancelled and ErrLeaseLost when a renewal
+// failed for the renew deadline; the replica should then stop, as another
+// may take over.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) error {
+	log.Printf("🗳️ Waiting to acquire lease %s/%s as %s", e.config.Namespace, e.config.Name, e.config.Identity)
+	for !e.tryAcquireOrRenew(ctx, e.config.RenewDeadline) {
+		if !sleep(ctx, e.config.RetryPeriod) {
+			return nil
+		}
+	}
+	log.Printf("👑 Acquired lease %s/%s, leading", e.config.Namespace, e.config.Name)
+
+	leadCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leadCtx)
+	}()
+
+	err := e.renew(ctx)
+	stop()
+	<-done
+	e.release()
+	return err
+}
+
+// renew keeps the lease until ctx is cancelled or a renewal fails for the
+// renew deadline.
+func (e *Elector) renew(ctx context.Context) error {
+	for {
+		if !sleep(ctx, e.config.RetryPeriod) {
+			return nil
+		}
+		deadline := time.Now().Add(e.config.RenewDeadline)
+		for !e.tryAcquireOrRenew(ctx, time.Until(deadline)) {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				log.Printf("❌ Failed to renew lease %s/%s for %s", e.config.Namespace, e.config.Name, e.config.RenewDeadline)
+				return ErrLeaseLost
+			}
+			if !sleep(ctx, e.config.RetryPeriod) {
+				return nil
+			}
+		}
+	}
+}
+
+// tryAcquireOrRenew takes the lease if it is free or expired, or renews it
+// if this replica holds it, and reports whether it now holds it. Requests
+// are bounded by timeout.
+func (e *Elector) tryAcquireOrRenew(ctx context.Context, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	now := time.Now()
+
+	current, err := e.client.GetLease(ctx, e.config.Namespace, e.config.Name)
+	if errors.Is(err, client.ErrNotFound) {
+		lease := models.Lease{
+			Metadata: models.Metadata{Name: e.config.Name, Namespace: e.config.Namespace},
+			Spec:     e.heldSpec(models.LeaseSpec{}, now),
+		}
+		created, err := e.client.CreateLease(ctx, lease)
+		if err != nil {
+			log.Printf("⚠️ Failed to create lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+			return false
+		}
+		e.observe(created, now, true)
+		return true
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to get lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+		return false
+	}
+
+	held := e.observe(current, now, false)
+	if !held && !e.expired(now) {
+		return false
+	}
+
+	updated := *current
+	updated.Spec = e.heldSpec(current.Spec, now)
+	// The resourceVersion read above makes a racing replica's update fail
+	stored, err := e.client.UpdateLease(ctx, updated)
+	if err != nil {
+		if !errors.Is(err, client.ErrConflict) {
+			log.Printf("⚠️ Failed to update lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+		}
+		return false
+	}
+	e.observe(stored, now, true)
+	return true
+}
+
+// heldSpec returns spec held by this replica as of now.
+func (e *Elector) heldSpec(spec models.LeaseSpec, now time.Time) models.LeaseSpec {
+	if spec.HolderIdentity != e.config.Identity {
+		if spec.HolderIdentity != "" || spec.AcquireTime != "" {
+			spec.LeaseTransitions++
+		}
+		spec.HolderIdentity = e.config.Identity
+		spec.AcquireTime = now.UTC().Format(time.RFC3339Nano)
+	}
+	spec.LeaseDurationSeconds = int(e.config.LeaseDuration / time.Second)
+	spec.RenewTime = now.UTC().Format(time.RFC3339Nano)
+	return spec
+}
+
+// observe records the stored lease, restarting the expiry clock when it
+// changed, and whether this replica leads. It reports whether this replica
+// is the holder.
+func (e *Elector) observe(lease *models.Lease, now time.Time, leading bool) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lease == nil || lease.Spec != e.observed {
+		e.observed = lease.Spec
+		e.observedTime = now
+	}
+	e.lease = lease
+	held := lease.Spec.HolderIdentity == e.config.Identity
+	e.leading = leading || (e.leading && held)
+	return held
+}
+
+// expired reports whether the observed holder let the lease lapse, or
+// released it.
+func (e *Elector) expired(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.observed.HolderIdentity == "" {
+		return true
+	}
+	duration := time.Duration(e.observed.LeaseDurationSeconds) * time.Second
+	return now.After(e.observedTime.Add(duration))
+}
+
+// release gives up a lease this replica still holds.
+func (e *Elector) release() {
+	e.mu.Lock()
+	lease, leading := e.lease, e.leading
+	e.leading = false
+	e.mu.Unlock()
+	if !leading || lease == nil || lease.Spec.HolderIdentity != e.config.Identity {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.RenewDeadline)
+	defer cancel()
+	released := *lease
+	released.Spec.HolderIdentity = ""
+	released.Spec.LeaseDurationSeconds = 1
+	released.Spec.RenewTime = time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err := e.client.UpdateLease(ctx, released); err != nil {
+		log.Printf("⚠️ Failed to release lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+		return
+	}
+	log.Printf("✅ Released lease %s/%s", e.config.Namespace, e.config.Name)
+}
+
+// Name names the health check, see Check.
+func (e *Elector) Name() string { return "leaderElection" }
+
+// Check fails when this replica leads but has not renewed the lease for
+// longer than the lease duration: it is stuck, and another replica may be
+// leading too. Standby replicas pass.
+func (e *Elector) Check(*http.Request) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.leading {
+		return nil
+	}
+	if since := time.Since(e.observedTime); since > e.config.LeaseDuration+healthTolerance {
+		return fmt.Errorf("leading but failed to renew lease %s/%s for %s", e.config.Namespace, e.config.Name, since.Round(time.Second))
+	}
+	return nil
+}
+
+// sleep waits for d, returning false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -1,115 +1,179 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/selimhanmrl/Own-Kubernetes/agent"
+	"github.com/selimhanmrl/Own-Kubernetes/audit"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
 	"github.com/selimhanmrl/Own-Kubernetes/client"
 	"github.com/selimhanmrl/Own-Kubernetes/cmd"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/config"
 	own_redis "github.com/selimhanmrl/Own-Kubernetes/redis"
 	"github.com/selimhanmrl/Own-Kubernetes/server"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+	"github.com/spf13/pflag"
 )
 
 func main() {
 	args := os.Args[1:]
 	if len(args) > 0 && args[0] == "-mode" {
-		mode := "server"
+		mode, rest := "server", []string(nil)
 		if len(args) > 1 {
-			mode = args[1]
+			mode, rest = args[1], args[2:]
 		}
 
 		switch mode {
 		case "server":
-			// Initialize Redis
-			own_redis.InitRedis()
-
-			// Create and start API server
-			apiServer := server.NewAPIServer()
-			apiServer.Start()
+			component.Exit("API server", runAPIServer(rest))
 
 		case "node":
-			// Start as a node
-			nodeName := os.Getenv("NODE_NAME")
-			nodeIP := os.Getenv("NODE_IP")
-			apiHost := os.Getenv("API_HOST")
-			apiPort := os.Getenv("API_PORT")
-
-			if nodeName == "" || nodeIP == "" {
-				log.Fatal("❌ NODE_NAME and NODE_IP environment variables are required")
-			}
-
-			if apiHost == "" {
-				apiHost = "localhost"
-			}
-			if apiPort == "" {
-				apiPort = "8080"
-			}
-
-			nodeAgent := agent.NewNodeAgent(nodeName, nodeIP, apiHost, apiPort)
-			if err := nodeAgent.Start(); err != nil {
-				log.Fatalf("❌ Failed to start node agent: %v", err)
-			}
-
-			// Keep the node running
-			select {}
+			component.Exit("node agent", runNodeAgent(rest))
 
 		case "cli":
 			// Remove the -mode cli arguments before passing to cobra
 			os.Args = append(os.Args[:1], os.Args[3:]...)
-			if err := cmd.Execute(); err != nil {
-				log.Fatalf("❌ Error executing CLI command: %v", err)
-			}
+			component.Exit("mykube", cmd.Execute())
 
 		default:
-			log.Fatalf("❌ Invalid mode: %s. Must be 'server', 'node', or 'cli'", mode)
+			component.Exit("mykube", component.ConfigErrorf("invalid mode: %s. Must be 'server', 'node', or 'cli'", mode))
 		}
 		return
 	}
 
 	if len(args) > 0 && args[0] == "proxy" {
-		fmt.Println("🚀 Starting kube-proxy...")
-		proxyServer := server.NewProxyServer()
-
-		// Get services and pods
-		client := client.NewClient(client.ClientConfig{
-			Host: "localhost",
-			Port: "8080",
-		})
-
-		services, err := client.ListServices("")
-		if err != nil {
-			fmt.Printf("❌ Failed to list services: %v\n", err)
-			os.Exit(1)
-		}
+		component.Exit("kube-proxy", runProxy(args[1:]))
+	}
 
-		// Register NodePort services
-		for _, svc := range services {
-			if svc.Spec.Type == "NodePort" {
-				pods, err := client.ListPods("")
-				if err != nil {
-					fmt.Printf("❌ Failed to list pods: %v\n", err)
-					continue
-				}
-
-				fmt.Printf("📦 Registering service %s with NodePort\n", svc.Metadata.Name)
-				proxyServer.RegisterService(&svc, pods)
-			}
-		}
+	// If no mode specified, assume it's a CLI command
+	component.Exit("mykube", cmd.Execute())
+}
 
-		// Start the proxy server
-		if err := proxyServer.Start(); err != nil {
-			fmt.Printf("❌ Failed to start proxy: %v\n", err)
-			os.Exit(1)
-		}
+// runAPIServer serves the API until SIGTERM, configured by flags, the
+// environment and an APIServerConfiguration file.
+func runAPIServer(args []string) error {
+	cfg := config.DefaultAPIServerConfiguration()
+	fs := pflag.NewFlagSet("server", pflag.ContinueOnError)
+	cfg.AddFlags(fs)
+	if help, err := config.ParseFlags(fs, args); help || err != nil {
+		return err
+	}
+	if printed, err := config.Load(fs, cfg, os.Stdout); printed || err != nil {
+		return err
+	}
+
+	nodePortMin, nodePortMax, _ := cfg.NodePortRange()
+	store.SetNodePortRange(nodePortMin, nodePortMax)
+	nodeIPPrefix, nodeIPFirst, nodeIPLast, _ := cfg.NodeIPs()
+	store.SetNodeIPRange(nodeIPPrefix, nodeIPFirst, nodeIPLast)
+	store.SetEventTTL(cfg.EventTTL)
+
+	// Initialize Redis
+	own_redis.InitRedis(cfg.Storage.Host, cfg.Storage.Port)
+
+	// Create and start API server
+	apiServer, err := server.NewAPIServer(server.APIServerOptions{
+		Serving:          cfg.Serving,
+		ProxyBindAddress: cfg.ProxyBindAddress,
+		Auth: auth.Config{
+			TokenFile:             cfg.Authentication.TokenFile,
+			ServiceAccountKeyFile: cfg.Authentication.ServiceAccountKeyFile,
+			ClientCAFile:          cfg.Authentication.ClientCAFile,
+			AllowAnonymous:        cfg.Authentication.AllowAnonymous,
+		},
+		Audit: audit.Config{
+			PolicyFile:    cfg.Audit.PolicyFile,
+			LogPath:       cfg.Audit.LogPath,
+			LogMaxSizeMB:  cfg.Audit.LogMaxSizeMB,
+			LogMaxBackups: cfg.Audit.LogMaxBackups,
+			WebhookURL:    cfg.Audit.WebhookURL,
+		},
+		TLSCertFile:        cfg.TLS.CertFile,
+		TLSKeyFile:         cfg.TLS.KeyFile,
+		AuthorizationModes: cfg.Authorization.Modes,
+		NodeClient: server.NodeClientOptions{
+			Port:     cfg.KubeletClient.Port,
+			CertFile: cfg.KubeletClient.CertFile,
+			KeyFile:  cfg.KubeletClient.KeyFile,
+			CAFile:   cfg.KubeletClient.CAFile,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API server: %v", err)
+	}
+	return apiServer.Run(context.Background())
+}
 
-		// Keep the proxy running
-		select {}
+// runNodeAgent runs the node agent alone until SIGTERM, configured by the
+// environment and a NodeAgentConfiguration file.
+func runNodeAgent(args []string) error {
+	cfg := config.DefaultNodeAgentConfiguration()
+	fs := pflag.NewFlagSet("node", pflag.ContinueOnError)
+	config.AddFlags(fs)
+	if help, err := config.ParseFlags(fs, args); help || err != nil {
+		return err
+	}
+	if printed, err := config.Load(fs, cfg, os.Stdout); printed || err != nil {
+		return err
 	}
 
-	// If no mode specified, assume it's a CLI command
-	if err := cmd.Execute(); err != nil {
-		log.Fatalf("❌ Error executing CLI command: %v", err)
+	nodeAgent, err := agent.NewNodeAgent(cfg.NodeName, cfg.NodeIP, cfg.ClientConnection.ClientConfig(),
+		agent.Intervals{Heartbeat: cfg.HeartbeatInterval, PodSync: cfg.PodSyncInterval})
+	if err != nil {
+		return err
+	}
+	runner := component.NewRunner("node agent", cfg.Serving.ShutdownTimeout)
+	runner.Go("node agent", nodeAgent.Run)
+	return runner.Run(context.Background())
+}
+
+// runProxy forwards NodePort traffic for the services that exist at
+// startup until SIGTERM.
+func runProxy(args []string) error {
+	cfg := config.DefaultProxyConfiguration()
+	fs := pflag.NewFlagSet("proxy", pflag.ContinueOnError)
+	cfg.AddFlags(fs)
+	if help, err := config.ParseFlags(fs, args); help || err != nil {
+		return err
+	}
+	if printed, err := config.Load(fs, cfg, os.Stdout); printed || err != nil {
+		return err
 	}
+
+	fmt.Println("🚀 Starting kube-proxy...")
+	proxyServer := server.NewProxyServer()
+
+	// Get services and pods
+	client, err := client.NewClient(cfg.ClientConnection.ClientConfig())
+	if err != nil {
+		return err
+	}
+
+	services, err := client.ListServices("")
+	if err != nil {
+		return fmt.Errorf("failed to list services: %v", err)
+	}
+
+	// Register NodePort services
+	for _, svc := range services {
+		if svc.Spec.Type == "NodePort" {
+			pods, err := client.ListPods("")
+			if err != nil {
+				fmt.Printf("❌ Failed to list pods: %v\n", err)
+				continue
+			}
+
+			fmt.Printf("📦 Registering service %s with NodePort\n", svc.Metadata.Name)
+			proxyServer.RegisterService(&svc, pods)
+		}
+	}
+
+	runner := component.NewRunner("kube-proxy", cfg.ShutdownTimeout)
+	runner.Go("proxy", func(ctx context.Context) error {
+		return proxyServer.Run(ctx, cfg.BindAddress)
+	})
+	return runner.Run(context.Background())
 }
@@ -0,0 +1,32 @@
+package models
+
+// Deployment rolls out Replicas pods from Template through ReplicaSets.
+type Deployment struct {
+	APIVersion string           `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string           `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata         `json:"metadata" yaml:"metadata"`
+	Spec       DeploymentSpec   `json:"spec" yaml:"spec"`
+	Status     DeploymentStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// DeploymentSpec is the desired state of a deployment.
+type DeploymentSpec struct {
+	Replicas int           `json:"replicas" yaml:"replicas"`
+	Selector LabelSelector `json:"selector" yaml:"selector"`
+	Template PodTemplate   `json:"template" yaml:"template"`
+	// Strategy is RollingUpdate (the default) or Recreate
+	Strategy DeploymentStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+}
+
+// DeploymentStrategy is how old pods are replaced by new ones.
+type DeploymentStrategy struct {
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // RollingUpdate or Recreate
+}
+
+// DeploymentStatus is the observed state of a deployment.
+type DeploymentStatus struct {
+	Replicas          int `json:"replicas" yaml:"replicas"`
+	UpdatedReplicas   int `json:"updatedReplicas,omitempty" yaml:"updatedReplicas,omitempty"`
+	ReadyReplicas     int `json:"readyReplicas,omitempty" yaml:"readyReplicas,omitempty"`
+	AvailableReplicas int `json:"availableReplicas,omitempty" yaml:"availableReplicas,omitempty"`
+}
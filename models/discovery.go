@@ -0,0 +1,49 @@
+package models
+
+// APIVersions lists the versions of the core group, served at /api.
+type APIVersions struct {
+	Kind     string   `json:"kind"`
+	Versions []string `json:"versions"`
+}
+
+// GroupVersionForDiscovery names one version of a group.
+type GroupVersionForDiscovery struct {
+	GroupVersion string `json:"groupVersion"` // e.g. rbac.authorization.k8s.io/v1
+	Version      string `json:"version"`
+}
+
+// APIGroup describes a named group and the versions it is served at.
+type APIGroup struct {
+	Kind             string                     `json:"kind,omitempty"`
+	APIVersion       string                     `json:"apiVersion,omitempty"`
+	Name             string                     `json:"name"`
+	Versions         []GroupVersionForDiscovery `json:"versions"`
+	PreferredVersion GroupVersionForDiscovery   `json:"preferredVersion"`
+}
+
+// APIGroupList lists the named groups, served at /apis.
+type APIGroupList struct {
+	Kind       string     `json:"kind"`
+	APIVersion string     `json:"apiVersion"`
+	Groups     []APIGroup `json:"groups"`
+}
+
+// APIResource describes a resource, or a subresource such as pods/status,
+// served in a group version.
+type APIResource struct {
+	Name         string   `json:"name"`
+	SingularName string   `json:"singularName"`
+	Namespaced   bool     `json:"namespaced"`
+	Kind         string   `json:"kind"`
+	Verbs        []string `json:"verbs"`
+	ShortNames   []string `json:"shortNames,omitempty"`
+}
+
+// APIResourceList lists the resources of one group version, served at
+// /api/v1 and /apis/{group}/{version}.
+type APIResourceList struct {
+	Kind         string        `json:"kind"`
+	APIVersion   string        `json:"apiVersion"`
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []APIResource `json:"resources"`
+}
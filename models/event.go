@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Event types. Warnings are worth a look; normal events record progress.
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+// Event reports something that happened to an object, such as a pod being
+// scheduled or a container failing to start. Repeats of the same event
+// bump Count and LastTimestamp instead of creating new objects.
+type Event struct {
+	APIVersion string   `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string   `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata `json:"metadata" yaml:"metadata"`
+
+	InvolvedObject ObjectReference `json:"involvedObject" yaml:"involvedObject"`
+	// Reason is a short CamelCase cause, e.g. Scheduled or FailedScheduling.
+	Reason  string `json:"reason" yaml:"reason"`
+	Message string `json:"message" yaml:"message"`
+	Type    string `json:"type" yaml:"type"` // Normal or Warning
+
+	Count          int       `json:"count" yaml:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp" yaml:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp" yaml:"lastTimestamp"`
+
+	Source EventSource `json:"source" yaml:"source"`
+}
+
+// ObjectReference points at an object by kind, namespace and name.
+type ObjectReference struct {
+	Kind       string `json:"kind" yaml:"kind"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name       string `json:"name" yaml:"name"`
+	UID        string `json:"uid,omitempty" yaml:"uid,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+}
+
+// EventSource is the component, and for node components the host, that
+// reported an event.
+type EventSource struct {
+	Component string `json:"component,omitempty" yaml:"component,omitempty"`
+	Host      string `json:"host,omitempty" yaml:"host,omitempty"`
+}
+
+// PodReference refers to pod in events.
+func PodReference(pod Pod) ObjectReference {
+	return ObjectReference{
+		Kind:       "Pod",
+		Namespace:  pod.Metadata.Namespace,
+		Name:       pod.Metadata.Name,
+		UID:        pod.Metadata.UID,
+		APIVersion: "v1",
+	}
+}
+
+// NodeReference refers to the named node in events.
+func NodeReference(name string) ObjectReference {
+	return ObjectReference{Kind: "Node", Name: name, APIVersion: "v1"}
+}
@@ -0,0 +1,26 @@
+package models
+
+// Lease is held by one replica of a component at a time, electing it the
+// leader while the others stand by.
+type Lease struct {
+	APIVersion string    `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string    `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata  `json:"metadata" yaml:"metadata"`
+	Spec       LeaseSpec `json:"spec" yaml:"spec"`
+}
+
+// LeaseSpec says who holds a lease and until when.
+type LeaseSpec struct {
+	// HolderIdentity is the replica holding the lease, empty when it was
+	// released
+	HolderIdentity string `json:"holderIdentity,omitempty" yaml:"holderIdentity,omitempty"`
+	// LeaseDurationSeconds is how long others wait after the last renewal
+	// before taking the lease over
+	LeaseDurationSeconds int `json:"leaseDurationSeconds,omitempty" yaml:"leaseDurationSeconds,omitempty"`
+	// AcquireTime is when the current holder took the lease, RFC 3339
+	AcquireTime string `json:"acquireTime,omitempty" yaml:"acquireTime,omitempty"`
+	// RenewTime is when the holder last renewed the lease, RFC 3339
+	RenewTime string `json:"renewTime,omitempty" yaml:"renewTime,omitempty"`
+	// LeaseTransitions counts how often the lease changed holders
+	LeaseTransitions int `json:"leaseTransitions,omitempty" yaml:"leaseTransitions,omitempty"`
+}
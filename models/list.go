@@ -0,0 +1,30 @@
+package models
+
+// ListMeta describes a page of a list. Continue is set when more items are
+// available and is passed back as the continue query parameter to get them.
+type ListMeta struct {
+	ResourceVersion    string `json:"resourceVersion,omitempty" yaml:"resourceVersion,omitempty"`
+	Continue           string `json:"continue,omitempty" yaml:"continue,omitempty"`
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty" yaml:"remainingItemCount,omitempty"`
+}
+
+// List is the envelope list endpoints return, e.g. a PodList.
+type List[T any] struct {
+	APIVersion string   `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string   `json:"kind" yaml:"kind"`
+	Metadata   ListMeta `json:"metadata" yaml:"metadata"`
+	Items      []T      `json:"items" yaml:"items"`
+}
+
+type PodList = List[Pod]
+type ServiceList = List[Service]
+type NodeList = List[Node]
+type RoleList = List[Role]
+type ClusterRoleList = List[ClusterRole]
+type RoleBindingList = List[RoleBinding]
+type ClusterRoleBindingList = List[ClusterRoleBinding]
+type EventList = List[Event]
+type ReplicaSetList = List[ReplicaSet]
+type DeploymentList = List[Deployment]
+type NamespaceList = List[Namespace]
+type LeaseList = List[Lease]
@@ -0,0 +1,20 @@
+package models
+
+import "encoding/json"
+
+// Field manager operations.
+const (
+	ManagedFieldsOperationApply  = "Apply"
+	ManagedFieldsOperationUpdate = "Update"
+)
+
+// ManagedFieldsEntry records the fields one manager set on an object,
+// either by applying a configuration or by a plain update.
+type ManagedFieldsEntry struct {
+	Manager    string          `json:"manager" yaml:"manager"`
+	Operation  string          `json:"operation" yaml:"operation"`
+	APIVersion string          `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Time       string          `json:"time,omitempty" yaml:"time,omitempty"`
+	FieldsType string          `json:"fieldsType" yaml:"fieldsType"`
+	FieldsV1   json.RawMessage `json:"fieldsV1,omitempty" yaml:"-"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Namespace groups namespaced objects such as pods and services.
+type Namespace struct {
+	APIVersion string          `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string          `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata        `json:"metadata" yaml:"metadata"`
+	Status     NamespaceStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// NamespaceStatus is the observed state of a namespace.
+type NamespaceStatus struct {
+	Phase string `json:"phase,omitempty" yaml:"phase,omitempty"` // Active, set by the API server
+}
@@ -2,6 +2,7 @@ package models
 
 import "time"
 
+// NodeStatus is the last state a node reported.
 type NodeStatus struct {
 	Conditions    []NodeCondition `json:"conditions"`
 	Capacity      ResourceList    `json:"capacity"`
@@ -10,14 +11,14 @@ type NodeStatus struct {
 	LastHeartbeat time.Time       `json:"lastHeartbeat"`
 }
 
+// NodeCondition is one aspect of a node's health.
 type NodeCondition struct {
 	Type           string    `json:"type"`   // Ready, DiskPressure, MemoryPressure, NetworkUnavailable
 	Status         string    `json:"status"` // True, False, Unknown
 	LastUpdateTime time.Time `json:"lastUpdateTime"`
 }
 
-type ResourceList map[string]string
-
+// Node is a machine running the node server, on which pods are scheduled.
 type Node struct {
 	Name   string            `json:"name"`
 	IP     string            `json:"ip"`
@@ -27,9 +28,9 @@ type Node struct {
 }
 
 type NodeResources struct {
-    CPU    string `json:"cpu"`
-    Memory string `json:"memory"`
-    Pods   string `json:"pods"`
+    CPU    Quantity `json:"cpu"`
+    Memory Quantity `json:"memory"`
+    Pods   Quantity `json:"pods"`
 }
 
 type NodeSpec struct {
@@ -1,51 +1,114 @@
 // models/pod.go
 package models
 
+// Metadata is the metadata every namespaced object carries.
 type Metadata struct {
 	Name      string            `json:"name"`
 	Namespace string            `json:"namespace"`
 	UID       string            `json:"uid"`
 	Labels    map[string]string `json:"labels,omitempty"` // e.g., {"app": "nginx"}
+
+	// CreationTimestamp is when the API server created the object, in
+	// RFC 3339
+	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+
+	// ResourceVersion changes on every write of the object. Updates and
+	// patches that carry one are rejected with a conflict unless it is
+	// still the stored one
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// OwnerReferences name the objects this one belongs to, e.g. the
+	// ReplicaSet that created a pod
+	OwnerReferences []OwnerReference `json:"ownerReferences,omitempty"`
+
+	// ManagedFields records which manager set which fields, see
+	// server-side apply
+	ManagedFields []ManagedFieldsEntry `json:"managedFields,omitempty"`
 }
 
+// OwnerReference points at an object's owner. At most one owner is the
+// Controller that manages it.
+type OwnerReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+	Controller bool   `json:"controller,omitempty"`
+}
+
+// PodSpec is the desired state of a pod.
 type PodSpec struct {
 	Containers []Container `json:"containers"`
 	NodeName   string      `json:"nodeName,omitempty"` // empty until scheduled
 	Replicas   int         `json:"replicas,omitempty"` // for deployment
+	Volumes    []Volume    `json:"volumes,omitempty"`
 
 }
 
+// PodStatus is the observed state of a pod, reported by its node.
 type PodStatus struct {
 	Phase        string `json:"phase"` // Pending, Running, Failed
 	HostIP       string `json:"hostIP"`
 	PodIP        string `json:"podIP"`
 	StartTime    string `json:"startTime"`
 	ContainerID  string `json:"containerID"`
-    AssignedPort int    `json:"assignedPort"` // Make sure this is tagged
+    AssignedPort int    `json:"assignedPort"` // host port the container port is published on
 
 }
 
+// Pod is a group of containers scheduled together onto one node.
 type Pod struct {
 	Metadata Metadata  `json:"metadata"`
 	Spec     PodSpec   `json:"spec"`
 	Status   PodStatus `json:"status"`
 }
 
+// ResourceRequirements are the compute resources a container asks for
+// and may not exceed.
 type ResourceRequirements struct {
-	Requests map[string]string `json:"requests"` // e.g., {"cpu": "250m", "memory": "64Mi"}
-	Limits   map[string]string `json:"limits"`   // e.g., {"cpu": "500m", "memory": "128Mi"}
+	Requests ResourceList `json:"requests"` // e.g., {"cpu": "250m", "memory": "64Mi"}
+	Limits   ResourceList `json:"limits"`   // e.g., {"cpu": "500m", "memory": "128Mi"}
 }
 
+// Container is a single container of a pod.
 type Container struct {
 	Name      string               `json:"name"`
 	Image     string               `json:"image"`
 	Cmd       []string             `json:"cmd"`
-	Resources ResourceRequirements `json:"resources"`       // Add resources field
-	Ports     []ContainerPort      `json:"ports,omitempty"` // Add this field
+	Resources ResourceRequirements `json:"resources"`
+	Ports     []ContainerPort      `json:"ports,omitempty"`
+	// VolumeMounts mount the pod's volumes into the container
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"`
 
 }
+
+// ContainerPort is a port a container listens on.
 type ContainerPort struct {
 	ContainerPort int32  `json:"containerPort"`
 	Protocol      string `json:"protocol,omitempty"`
 	HostPort      int32  `json:"hostPort,omitempty"`
 }
+
+// Volume is storage the pod's containers can mount. Exactly one source is
+// set.
+type Volume struct {
+	Name     string                `json:"name"`
+	HostPath *HostPathVolumeSource `json:"hostPath,omitempty"`
+	EmptyDir *EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+}
+
+// HostPathVolumeSource mounts a directory of the node.
+type HostPathVolumeSource struct {
+	Path string `json:"path"`
+}
+
+// EmptyDirVolumeSource is a scratch directory that lives as long as the
+// pod.
+type EmptyDirVolumeSource struct{}
+
+// VolumeMount mounts a volume at MountPath inside a container.
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+}
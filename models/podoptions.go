@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PodLogOptions select the log lines the pods/log subresource returns. They
+// travel as query parameters, from the CLI to the API server and on to the
+// node server.
+type PodLogOptions struct {
+	// Container to read; defaults to the pod's first container
+	Container string `json:"container,omitempty"`
+	// Follow keeps the stream open, sending lines as they are written
+	Follow bool `json:"follow,omitempty"`
+	// Previous reads the last terminated instance of the container
+	Previous bool `json:"previous,omitempty"`
+	// SinceSeconds only returns lines written in the last seconds; it
+	// cannot be combined with SinceTime
+	SinceSeconds *int64 `json:"sinceSeconds,omitempty"`
+	// SinceTime only returns lines written after it
+	SinceTime *time.Time `json:"sinceTime,omitempty"`
+	// Timestamps prefixes each line with the RFC 3339 time it was written
+	Timestamps bool `json:"timestamps,omitempty"`
+	// TailLines only returns the last lines of the log
+	TailLines *int64 `json:"tailLines,omitempty"`
+}
+
+// Query renders the options as query parameters.
+func (o PodLogOptions) Query() url.Values {
+	q := url.Values{}
+	if o.Container != "" {
+		q.Set("container", o.Container)
+	}
+	if o.Follow {
+		q.Set("follow", "true")
+	}
+	if o.Previous {
+		q.Set("previous", "true")
+	}
+	if o.SinceSeconds != nil {
+		q.Set("sinceSeconds", strconv.FormatInt(*o.SinceSeconds, 10))
+	}
+	if o.SinceTime != nil {
+		q.Set("sinceTime", o.SinceTime.UTC().Format(time.RFC3339))
+	}
+	if o.Timestamps {
+		q.Set("timestamps", "true")
+	}
+	if o.TailLines != nil {
+		q.Set("tailLines", strconv.FormatInt(*o.TailLines, 10))
+	}
+	return q
+}
+
+// ParsePodLogOptions reads options from query parameters and checks them.
+func ParsePodLogOptions(q url.Values) (PodLogOptions, error) {
+	opts := PodLogOptions{Container: q.Get("container")}
+	var err error
+	if opts.Follow, err = queryBool(q, "follow"); err != nil {
+		return opts, err
+	}
+	if opts.Previous, err = queryBool(q, "previous"); err != nil {
+		return opts, err
+	}
+	if opts.Timestamps, err = queryBool(q, "timestamps"); err != nil {
+		return opts, err
+	}
+	if opts.SinceSeconds, err = queryInt(q, "sinceSeconds"); err != nil {
+		return opts, err
+	}
+	if opts.TailLines, err = queryInt(q, "tailLines"); err != nil {
+		return opts, err
+	}
+	if value := q.Get("sinceTime"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return opts, fmt.Errorf("sinceTime %q is not an RFC 3339 time", value)
+		}
+		opts.SinceTime = &t
+	}
+
+	if opts.SinceSeconds != nil && *opts.SinceSeconds < 1 {
+		return opts, fmt.Errorf("sinceSeconds must be at least 1")
+	}
+	if opts.SinceSeconds != nil && opts.SinceTime != nil {
+		return opts, fmt.Errorf("sinceSeconds and sinceTime cannot both be set")
+	}
+	if opts.TailLines != nil && *opts.TailLines < 0 {
+		return opts, fmt.Errorf("tailLines must not be negative")
+	}
+	return opts, nil
+}
+
+// PodExecOptions describe a command to run in a pod's container through
+// the pods/exec subresource.
+type PodExecOptions struct {
+	// Container to run in; defaults to the pod's first container
+	Container string `json:"container,omitempty"`
+	// Command and its arguments, sent as one command parameter each
+	Command []string `json:"command"`
+	// Stdin, Stdout and Stderr say which streams the command is given
+	Stdin  bool `json:"stdin,omitempty"`
+	Stdout bool `json:"stdout,omitempty"`
+	Stderr bool `json:"stderr,omitempty"`
+	// TTY runs the command in a terminal, whose output all goes to stdout
+	TTY bool `json:"tty,omitempty"`
+}
+
+// Query renders the options as query parameters.
+func (o PodExecOptions) Query() url.Values {
+	q := url.Values{}
+	if o.Container != "" {
+		q.Set("container", o.Container)
+	}
+	for _, arg := range o.Command {
+		q.Add("command", arg)
+	}
+	for name, set := range map[string]bool{"stdin": o.Stdin, "stdout": o.Stdout, "stderr": o.Stderr, "tty": o.TTY} {
+		if set {
+			q.Set(name, "true")
+		}
+	}
+	return q
+}
+
+// ParsePodExecOptions reads options from query parameters and checks them.
+func ParsePodExecOptions(q url.Values) (PodExecOptions, error) {
+	opts := PodExecOptions{Container: q.Get("container"), Command: q["command"]}
+	for name, dst := range map[string]*bool{"stdin": &opts.Stdin, "stdout": &opts.Stdout, "stderr": &opts.Stderr, "tty": &opts.TTY} {
+		value, err := queryBool(q, name)
+		if err != nil {
+			return opts, err
+		}
+		*dst = value
+	}
+
+	if len(opts.Command) == 0 {
+		return opts, fmt.Errorf("a command is required")
+	}
+	if !opts.Stdin && !opts.Stdout && !opts.Stderr {
+		return opts, fmt.Errorf("at least one of stdin, stdout or stderr is required")
+	}
+	if opts.TTY && opts.Stderr {
+		return opts, fmt.Errorf("stderr cannot be used with tty, whose output all goes to stdout")
+	}
+	return opts, nil
+}
+
+// PodPortForwardOptions name the port of a pod the pods/portforward
+// subresource connects to. Each forwarded connection is a request of its
+// own.
+type PodPortForwardOptions struct {
+	// Port in the pod's network namespace
+	Port int32 `json:"port"`
+}
+
+// Query renders the options as query parameters.
+func (o PodPortForwardOptions) Query() url.Values {
+	return url.Values{"port": {strconv.Itoa(int(o.Port))}}
+}
+
+// ParsePodPortForwardOptions reads options from query parameters and checks
+// them.
+func ParsePodPortForwardOptions(q url.Values) (PodPortForwardOptions, error) {
+	var opts PodPortForwardOptions
+	port, err := queryInt(q, "port")
+	if err != nil {
+		return opts, err
+	}
+	if port == nil {
+		return opts, fmt.Errorf("a port is required")
+	}
+	if *port < 1 || *port > 65535 {
+		return opts, fmt.Errorf("port %d is not between 1 and 65535", *port)
+	}
+	opts.Port = int32(*port)
+	return opts, nil
+}
+
+func queryBool(q url.Values, name string) (bool, error) {
+	value := q.Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s %q is not true or false", name, value)
+	}
+	return b, nil
+}
+
+func queryInt(q url.Values, name string) (*int64, error) {
+	value := q.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q is not a number", name, value)
+	}
+	return &n, nil
+}
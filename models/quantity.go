@@ -0,0 +1,362 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the notation a Quantity is written back out in.
+type Format string
+
+const (
+	BinarySI        Format = "BinarySI"        // e.g. 128Mi, 1.5Gi
+	DecimalSI       Format = "DecimalSI"       // e.g. 500m, 2k, 500M
+	DecimalExponent Format = "DecimalExponent" // e.g. 2e3, 1E6
+)
+
+// Quantity is a Kubernetes style resource amount such as "250m", "1.5Gi"
+// or "2e3". Values are kept as an integer number of billionths (nano units)
+// of arbitrary size, so that arithmetic and comparison are exact from 1n up
+// to and beyond 1Ei; anything finer than 1n is rounded up.
+type Quantity struct {
+	nano   *big.Int // nil means zero; never modified once set
+	Format Format
+}
+
+// nanoPerUnit and nanoPerMilli convert between nano units and the whole and
+// milli units callers deal in.
+var (
+	nanoPerUnit  = big.NewInt(1000000000)
+	nanoPerMilli = big.NewInt(1000000)
+)
+
+var binarySuffixes = []struct {
+	suffix string
+	shift  uint
+}{
+	{"Ei", 60}, {"Pi", 50}, {"Ti", 40}, {"Gi", 30}, {"Mi", 20}, {"Ki", 10},
+}
+
+var decimalSuffixes = []struct {
+	suffix string
+	exp    int
+}{
+	{"E", 18}, {"P", 15}, {"T", 12}, {"G", 9}, {"M", 6}, {"k", 3},
+	{"", 0}, {"m", -3}, {"u", -6}, {"n", -9},
+}
+
+// ParseQuantity parses a quantity string with an optional binary (Ki, Mi,
+// Gi, Ti, Pi, Ei), decimal (n, u, m, k, M, G, T, P, E) or exponent (e3, E-2)
+// suffix. An exponent may be combined with a decimal suffix, as in "2e3m".
+func ParseQuantity(s string) (Quantity, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return Quantity{}, fmt.Errorf("quantity is empty")
+	}
+
+	numEnd := 0
+	if str[0] == '+' || str[0] == '-' {
+		numEnd = 1
+	}
+	digits := 0
+	for numEnd < len(str) && (str[numEnd] >= '0' && str[numEnd] <= '9' || str[numEnd] == '.') {
+		if str[numEnd] != '.' {
+			digits++
+		}
+		numEnd++
+	}
+	if digits == 0 || strings.Count(str[:numEnd], ".") > 1 {
+		return Quantity{}, fmt.Errorf("invalid quantity %q: missing number", s)
+	}
+
+	value, ok := new(big.Rat).SetString(str[:numEnd])
+	if !ok {
+		return Quantity{}, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	suffix := str[numEnd:]
+	format := DecimalSI
+	multiplier := new(big.Rat)
+
+	// An exponent may be followed by a decimal suffix, e.g. "2e3m"
+	if exp, rest, ok := splitExponent(suffix); ok {
+		if exp > 30 || exp < -30 {
+			return Quantity{}, fmt.Errorf("invalid quantity %q: bad exponent", s)
+		}
+		multiplier.Set(pow10(exp))
+		format = DecimalExponent
+		if rest != "" {
+			format = DecimalSI
+		}
+		suffix = rest
+	} else {
+		multiplier.SetInt64(1)
+		for _, b := range binarySuffixes {
+			if suffix == b.suffix {
+				multiplier.SetInt(new(big.Int).Lsh(big.NewInt(1), b.shift))
+				format = BinarySI
+				suffix = ""
+				break
+			}
+		}
+	}
+
+	found := false
+	for _, d := range decimalSuffixes {
+		if suffix == d.suffix {
+			multiplier.Mul(multiplier, pow10(d.exp))
+			found = true
+			break
+		}
+	}
+	if !found {
+		return Quantity{}, fmt.Errorf("invalid quantity %q: unknown suffix %q", s, suffix)
+	}
+
+	value.Mul(value, multiplier)
+	value.Mul(value, new(big.Rat).SetInt(nanoPerUnit))
+	return Quantity{nano: ratCeil(value), Format: format}, nil
+}
+
+// MustParse is like ParseQuantity but panics on error. It is meant for
+// constants and defaults.
+func MustParse(s string) Quantity {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// NewQuantity returns a quantity of value whole units.
+func NewQuantity(value int64, format Format) Quantity {
+	return Quantity{nano: new(big.Int).Mul(big.NewInt(value), nanoPerUnit), Format: format}
+}
+
+// NewMilliQuantity returns a quantity of milli thousandths of a unit.
+func NewMilliQuantity(milli int64, format Format) Quantity {
+	return Quantity{nano: new(big.Int).Mul(big.NewInt(milli), nanoPerMilli), Format: format}
+}
+
+// splitExponent splits a leading "e<int>" or "E<int>" off suffix. A bare
+// "E" is the exa suffix, not an exponent.
+func splitExponent(suffix string) (int, string, bool) {
+	if len(suffix) < 2 || (suffix[0] != 'e' && suffix[0] != 'E') {
+		return 0, "", false
+	}
+	end := 1
+	if suffix[end] == '+' || suffix[end] == '-' {
+		end++
+	}
+	start := end
+	for end < len(suffix) && suffix[end] >= '0' && suffix[end] <= '9' {
+		end++
+	}
+	if end == start {
+		return 0, "", false
+	}
+	exp, err := strconv.Atoi(suffix[1:end])
+	if err != nil {
+		return 0, "", false
+	}
+	return exp, suffix[end:], true
+}
+
+func pow10(exp int) *big.Rat {
+	if exp >= 0 {
+		return new(big.Rat).SetInt(pow10Int(exp))
+	}
+	return new(big.Rat).SetFrac(big.NewInt(1), pow10Int(-exp))
+}
+
+// ratCeil rounds r away from zero to the next integer.
+func ratCeil(r *big.Rat) *big.Int {
+	return quoCeil(r.Num(), r.Denom())
+}
+
+// quoCeil divides x by y, rounding away from zero.
+func quoCeil(x, y *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	} else if rem.Sign() < 0 {
+		quo.Sub(quo, big.NewInt(1))
+	}
+	return quo
+}
+
+// clampInt64 returns x, or the nearest int64 if it does not fit in one.
+func clampInt64(x *big.Int) int64 {
+	switch {
+	case x.IsInt64():
+		return x.Int64()
+	case x.Sign() > 0:
+		return math.MaxInt64
+	}
+	return math.MinInt64
+}
+
+// amount returns the quantity in nano units.
+func (q Quantity) amount() *big.Int {
+	if q.nano == nil {
+		return new(big.Int)
+	}
+	return q.nano
+}
+
+// Value returns the quantity in whole units, rounded up and clamped to the
+// int64 range.
+func (q Quantity) Value() int64 {
+	return clampInt64(quoCeil(q.amount(), nanoPerUnit))
+}
+
+// MilliValue returns the quantity in thousandths of a unit, rounded up and
+// clamped to the int64 range.
+func (q Quantity) MilliValue() int64 {
+	return clampInt64(quoCeil(q.amount(), nanoPerMilli))
+}
+
+// AsFloat64 returns the quantity as a float for callers that need to hand
+// it to something that only understands decimals, such as docker --cpus.
+func (q Quantity) AsFloat64() float64 {
+	f, _ := new(big.Rat).SetFrac(q.amount(), nanoPerUnit).Float64()
+	return f
+}
+
+func (q Quantity) IsZero() bool {
+	return q.amount().Sign() == 0
+}
+
+// Cmp returns -1, 0 or 1 if q is less than, equal to or greater than y.
+func (q Quantity) Cmp(y Quantity) int {
+	return q.amount().Cmp(y.amount())
+}
+
+// Add adds y to q.
+func (q *Quantity) Add(y Quantity) {
+	if q.Format == "" {
+		q.Format = y.Format
+	}
+	q.nano = new(big.Int).Add(q.amount(), y.amount())
+}
+
+// Sub subtracts y from q.
+func (q *Quantity) Sub(y Quantity) {
+	if q.Format == "" {
+		q.Format = y.Format
+	}
+	q.nano = new(big.Int).Sub(q.amount(), y.amount())
+}
+
+// String returns the canonical form of q in its format, e.g. "1536Mi",
+// "500m", "2k" or "2e3". Fractions of a unit are written with the largest
+// of the m, u and n suffixes that is exact.
+func (q Quantity) String() string {
+	nano := q.amount()
+	if nano.Sign() == 0 {
+		return "0"
+	}
+	sign := ""
+	if nano.Sign() < 0 {
+		sign = "-"
+		nano = new(big.Int).Neg(nano)
+	}
+
+	value, rem := new(big.Int).QuoRem(nano, nanoPerUnit, new(big.Int))
+	if rem.Sign() != 0 {
+		for _, d := range decimalSuffixes {
+			if d.exp >= 0 {
+				continue
+			}
+			if scaled, ok := divideExactly(nano, pow10Int(9+d.exp)); ok {
+				return sign + scaled.String() + d.suffix
+			}
+		}
+	}
+
+	switch q.Format {
+	case BinarySI:
+		for _, b := range binarySuffixes {
+			if scaled, ok := divideExactly(value, new(big.Int).Lsh(big.NewInt(1), b.shift)); ok {
+				return sign + scaled.String() + b.suffix
+			}
+		}
+	case DecimalExponent:
+		// Use the largest power of 1000 that divides the value exactly
+		exp := 0
+		thousand := big.NewInt(1000)
+		for {
+			scaled, ok := divideExactly(value, thousand)
+			if !ok {
+				break
+			}
+			value = scaled
+			exp += 3
+		}
+		if exp == 0 {
+			return sign + value.String()
+		}
+		return sign + value.String() + "e" + strconv.Itoa(exp)
+	default:
+		for _, d := range decimalSuffixes {
+			if d.exp <= 0 {
+				break
+			}
+			if scaled, ok := divideExactly(value, pow10Int(d.exp)); ok {
+				return sign + scaled.String() + d.suffix
+			}
+		}
+	}
+	return sign + value.String()
+}
+
+// divideExactly returns x/y if y divides x without a remainder.
+func divideExactly(x, y *big.Int) (*big.Int, bool) {
+	quo, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+	return quo, rem.Sign() == 0
+}
+
+func pow10Int(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.String())
+}
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*q = Quantity{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// Bare JSON numbers such as {"cpu": 2} are accepted too
+		s = string(data)
+	}
+	parsed, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+func (q Quantity) MarshalYAML() (interface{}, error) {
+	return q.String(), nil
+}
+
+func (q *Quantity) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseQuantity(value.Value)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
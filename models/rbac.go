@@ -0,0 +1,98 @@
+package models
+
+// PolicyRule grants Verbs on Resources (optionally limited to
+// ResourceNames), or on NonResourceURLs such as /healthz. "*" matches
+// anything.
+type PolicyRule struct {
+	Verbs           []string `json:"verbs" yaml:"verbs"`
+	Resources       []string `json:"resources,omitempty" yaml:"resources,omitempty"` // e.g. pods, pods/status
+	ResourceNames   []string `json:"resourceNames,omitempty" yaml:"resourceNames,omitempty"`
+	NonResourceURLs []string `json:"nonResourceURLs,omitempty" yaml:"nonResourceURLs,omitempty"`
+}
+
+// Role grants rules within a single namespace.
+type Role struct {
+	APIVersion string       `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string       `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata     `json:"metadata" yaml:"metadata"`
+	Rules      []PolicyRule `json:"rules" yaml:"rules"`
+}
+
+// ClusterRole grants rules in every namespace and on cluster scoped
+// resources such as nodes.
+type ClusterRole struct {
+	APIVersion string       `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string       `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata     `json:"metadata" yaml:"metadata"`
+	Rules      []PolicyRule `json:"rules" yaml:"rules"`
+}
+
+const (
+	SubjectUser           = "User"
+	SubjectGroup          = "Group"
+	SubjectServiceAccount = "ServiceAccount"
+)
+
+// Subject is who a binding applies to.
+type Subject struct {
+	Kind      string `json:"kind" yaml:"kind"` // User, Group, ServiceAccount
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"` // ServiceAccount only
+}
+
+// RoleRef points a binding at a Role or ClusterRole.
+type RoleRef struct {
+	Kind string `json:"kind" yaml:"kind"` // Role, ClusterRole
+	Name string `json:"name" yaml:"name"`
+}
+
+// RoleBinding grants a Role, or a ClusterRole's rules, within its own
+// namespace.
+type RoleBinding struct {
+	APIVersion string    `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string    `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata  `json:"metadata" yaml:"metadata"`
+	Subjects   []Subject `json:"subjects" yaml:"subjects"`
+	RoleRef    RoleRef   `json:"roleRef" yaml:"roleRef"`
+}
+
+// ClusterRoleBinding grants a ClusterRole across the whole cluster.
+type ClusterRoleBinding struct {
+	APIVersion string    `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string    `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Metadata   Metadata  `json:"metadata" yaml:"metadata"`
+	Subjects   []Subject `json:"subjects" yaml:"subjects"`
+	RoleRef    RoleRef   `json:"roleRef" yaml:"roleRef"`
+}
+
+// ResourceAttributes describe a resource request for an access review.
+type ResourceAttributes struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Verb        string `json:"verb"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// NonResourceAttributes describe a request for a plain path such as /healthz.
+type NonResourceAttributes struct {
+	Path string `json:"path"`
+	Verb string `json:"verb"`
+}
+
+// SelfSubjectAccessReview asks whether the caller may perform an action.
+type SelfSubjectAccessReview struct {
+	Spec   SelfSubjectAccessReviewSpec `json:"spec"`
+	Status SubjectAccessReviewStatus   `json:"status"`
+}
+
+type SelfSubjectAccessReviewSpec struct {
+	ResourceAttributes    *ResourceAttributes    `json:"resourceAttributes,omitempty"`
+	NonResourceAttributes *NonResourceAttributes `json:"nonResourceAttributes,omitempty"`
+}
+
+type SubjectAccessReviewStatus struct {
+	Allowed bool   `json:"allowed"`
+	Denied  bool   `json:"denied,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -1,45 +1,40 @@
 package models
 
+// ReplicaSet keeps Replicas pods matching Selector running, creating them
+// from Template.
 type ReplicaSet struct {
-
-    Metadata   ReplicaSetMetadata `yaml:"metadata"`
-    Spec       ReplicaSetSpec     `yaml:"spec"`
-    Status     ReplicaSetStatus   `yaml:"status"`
-}
-
-type ReplicaSetMetadata struct {
-    Name        string            `yaml:"name"`
-    Namespace   string            `yaml:"namespace"`
-    Labels      map[string]string `yaml:"labels,omitempty"`
-    Annotations map[string]string `yaml:"annotations,omitempty"`
-    UID         string            `yaml:"uid,omitempty"`
+    APIVersion string           `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+    Kind       string           `json:"kind,omitempty" yaml:"kind,omitempty"`
+    Metadata   Metadata         `json:"metadata" yaml:"metadata"`
+    Spec       ReplicaSetSpec   `json:"spec" yaml:"spec"`
+    Status     ReplicaSetStatus `json:"status,omitempty" yaml:"status,omitempty"`
 }
 
 type ReplicaSetSpec struct {
-    Replicas int          `yaml:"replicas"`
-    Selector LabelSelector `yaml:"selector"`
-    Template PodTemplate  `yaml:"template"`
+    Replicas int           `json:"replicas" yaml:"replicas"`
+    Selector LabelSelector `json:"selector" yaml:"selector"`
+    Template PodTemplate   `json:"template" yaml:"template"`
 }
 
 type LabelSelector struct {
-    MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
+    MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchLabels,omitempty"`
 }
 
 type ReplicaSetStatus struct {
-    Replicas           int   `yaml:"replicas"`
-    ReadyReplicas      int   `yaml:"readyReplicas,omitempty"`
-    AvailableReplicas  int   `yaml:"availableReplicas,omitempty"`
-    ObservedGeneration int64 `yaml:"observedGeneration,omitempty"`
+    Replicas           int   `json:"replicas" yaml:"replicas"`
+    ReadyReplicas      int   `json:"readyReplicas,omitempty" yaml:"readyReplicas,omitempty"`
+    AvailableReplicas  int   `json:"availableReplicas,omitempty" yaml:"availableReplicas,omitempty"`
+    ObservedGeneration int64 `json:"observedGeneration,omitempty" yaml:"observedGeneration,omitempty"`
 }
 
 // PodTemplate represents the template for creating new pods
 type PodTemplate struct {
-    Metadata PodTemplateMetadata `yaml:"metadata"`
-    Spec     PodSpec            `yaml:"spec"`
+    Metadata PodTemplateMetadata `json:"metadata" yaml:"metadata"`
+    Spec     PodSpec             `json:"spec" yaml:"spec"`
 }
 
 // PodTemplateMetadata contains metadata for pod template
 type PodTemplateMetadata struct {
-    Labels      map[string]string `yaml:"labels,omitempty"`
-    Annotations map[string]string `yaml:"annotations,omitempty"`
-}
\ No newline at end of file
+    Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+    Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
@@ -0,0 +1,84 @@
+package models
+
+// Well-known resource names used in ResourceList keys.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+	ResourcePods   = "pods"
+)
+
+// ResourceList maps a resource name to its amount, e.g. {"cpu": 250m, "memory": 64Mi}.
+type ResourceList map[string]Quantity
+
+func (rl ResourceList) Cpu() Quantity {
+	if q, ok := rl[ResourceCPU]; ok {
+		return q
+	}
+	return Quantity{Format: DecimalSI}
+}
+
+func (rl ResourceList) Memory() Quantity {
+	if q, ok := rl[ResourceMemory]; ok {
+		return q
+	}
+	return Quantity{Format: BinarySI}
+}
+
+func (rl ResourceList) Pods() Quantity {
+	if q, ok := rl[ResourcePods]; ok {
+		return q
+	}
+	return Quantity{Format: DecimalSI}
+}
+
+// Add sums other into rl, creating entries that are missing.
+func (rl ResourceList) Add(other ResourceList) {
+	for name, q := range other {
+		sum := rl[name]
+		sum.Add(q)
+		rl[name] = sum
+	}
+}
+
+// Fits reports whether every resource in request is available in rl.
+// Resources rl does not mention are treated as unlimited. The first
+// resource that does not fit is returned.
+func (rl ResourceList) Fits(request ResourceList) (bool, string) {
+	for name, q := range request {
+		available, ok := rl[name]
+		if !ok {
+			continue
+		}
+		if q.Cmp(available) > 0 {
+			return false, name
+		}
+	}
+	return true, ""
+}
+
+// ResourceRequests sums the requests of all containers in the pod. A
+// container that sets a limit without a request is treated as requesting
+// its limit.
+func (p *Pod) ResourceRequests() ResourceList {
+	total := ResourceList{}
+	for _, c := range p.Spec.Containers {
+		requests := ResourceList{}
+		for name, q := range c.Resources.Limits {
+			requests[name] = q
+		}
+		for name, q := range c.Resources.Requests {
+			requests[name] = q
+		}
+		total.Add(requests)
+	}
+	return total
+}
+
+// ResourceLimits sums the limits of all containers in the pod.
+func (p *Pod) ResourceLimits() ResourceList {
+	total := ResourceList{}
+	for _, c := range p.Spec.Containers {
+		total.Add(c.Resources.Limits)
+	}
+	return total
+}
@@ -1,28 +1,34 @@
 package models
 
+// Service exposes the pods matching its selector behind one set of ports.
 type Service struct {
-	APIVersion string          `yaml:"apiVersion"`
-	Kind       string          `yaml:"kind"`
-	Metadata   ServiceMetadata `yaml:"metadata"`
-	Spec       ServiceSpec     `yaml:"spec"`
+	APIVersion string          `json:"apiVersion,omitempty" yaml:"apiVersion"`
+	Kind       string          `json:"kind,omitempty" yaml:"kind"`
+	Metadata   ServiceMetadata `json:"metadata" yaml:"metadata"`
+	Spec       ServiceSpec     `json:"spec" yaml:"spec"`
 }
 
+// ServiceMetadata is the metadata of a service.
 type ServiceMetadata struct {
-	Name        string            `yaml:"name"`
-	Namespace   string            `yaml:"namespace"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Name        string            `json:"name" yaml:"name"`
+	Namespace   string            `json:"namespace" yaml:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+
+	ManagedFields []ManagedFieldsEntry `json:"managedFields,omitempty" yaml:"managedFields,omitempty"`
 }
 
+// ServiceSpec is the desired state of a service.
 type ServiceSpec struct {
-	Type     string            `yaml:"type"`
-	Selector map[string]string `yaml:"selector"`
-	Ports    []ServicePort     `yaml:"ports"`
+	Type     string            `json:"type" yaml:"type"`
+	Selector map[string]string `json:"selector" yaml:"selector"`
+	Ports    []ServicePort     `json:"ports" yaml:"ports"`
 }
 
+// ServicePort maps a service port to the pods' target port.
 type ServicePort struct {
-	Port         int `yaml:"port"`
-	TargetPort   int `yaml:"targetPort"`
-	NodePort     int `yaml:"nodePort,omitempty"`
+	Port         int `json:"port" yaml:"port"`
+	TargetPort   int `json:"targetPort" yaml:"targetPort"`
+	NodePort     int `json:"nodePort,omitempty" yaml:"nodePort,omitempty"`
 	assignedPort int // Internal field to track assigned port
 }
@@ -0,0 +1,109 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package openapi
+
+// descriptions holds the doc comments of the models types, keyed by type
+// name and by type name and Go field name, e.g. "Pod.Spec".
+var descriptions = map[string]string{
+	"APIGroup":                              "APIGroup describes a named group and the versions it is served at.",
+	"APIGroupList":                          "APIGroupList lists the named groups, served at /apis.",
+	"APIResource":                           "APIResource describes a resource, or a subresource such as pods/status, served in a group version.",
+	"APIResourceList":                       "APIResourceList lists the resources of one group version, served at /api/v1 and /apis/{group}/{version}.",
+	"APIVersions":                           "APIVersions lists the versions of the core group, served at /api.",
+	"ClusterRole":                           "ClusterRole grants rules in every namespace and on cluster scoped resources such as nodes.",
+	"ClusterRoleBinding":                    "ClusterRoleBinding grants a ClusterRole across the whole cluster.",
+	"Container":                             "Container is a single container of a pod.",
+	"Container.VolumeMounts":                "VolumeMounts mount the pod's volumes into the container",
+	"ContainerPort":                         "ContainerPort is a port a container listens on.",
+	"Deployment":                            "Deployment rolls out Replicas pods from Template through ReplicaSets.",
+	"DeploymentSpec":                        "DeploymentSpec is the desired state of a deployment.",
+	"DeploymentSpec.Strategy":               "Strategy is RollingUpdate (the default) or Recreate",
+	"DeploymentStatus":                      "DeploymentStatus is the observed state of a deployment.",
+	"DeploymentStrategy":                    "DeploymentStrategy is how old pods are replaced by new ones.",
+	"DeploymentStrategy.Type":               "RollingUpdate or Recreate",
+	"EmptyDirVolumeSource":                  "EmptyDirVolumeSource is a scratch directory that lives as long as the pod.",
+	"Event":                                 "Event reports something that happened to an object, such as a pod being scheduled or a container failing to start. Repeats of the same event bump Count and LastTimestamp instead of creating new objects.",
+	"Event.Reason":                          "Reason is a short CamelCase cause, e.g. Scheduled or FailedScheduling.",
+	"Event.Type":                            "Normal or Warning",
+	"EventSource":                           "EventSource is the component, and for node components the host, that reported an event.",
+	"Format":                                "Format is the notation a Quantity is written back out in.",
+	"GroupVersionForDiscovery":              "GroupVersionForDiscovery names one version of a group.",
+	"GroupVersionForDiscovery.GroupVersion": "e.g. rbac.authorization.k8s.io/v1",
+	"HostPathVolumeSource":                  "HostPathVolumeSource mounts a directory of the node.",
+	"Lease":                                 "Lease is held by one replica of a component at a time, electing it the leader while the others stand by.",
+	"LeaseSpec":                             "LeaseSpec says who holds a lease and until when.",
+	"LeaseSpec.AcquireTime":                 "AcquireTime is when the current holder took the lease, RFC 3339",
+	"LeaseSpec.HolderIdentity":              "HolderIdentity is the replica holding the lease, empty when it was released",
+	"LeaseSpec.LeaseDurationSeconds":        "LeaseDurationSeconds is how long others wait after the last renewal before taking the lease over",
+	"LeaseSpec.LeaseTransitions":            "LeaseTransitions counts how often the lease changed holders",
+	"LeaseSpec.RenewTime":                   "RenewTime is when the holder last renewed the lease, RFC 3339",
+	"List":                                  "List is the envelope list endpoints return, e.g. a PodList.",
+	"ListMeta":                              "ListMeta describes a page of a list. Continue is set when more items are available and is passed back as the continue query parameter to get them.",
+	"ManagedFieldsEntry":                    "ManagedFieldsEntry records the fields one manager set on an object, either by applying a configuration or by a plain update.",
+	"Metadata":                              "Metadata is the metadata every namespaced object carries.",
+	"Metadata.CreationTimestamp":            "CreationTimestamp is when the API server created the object, in RFC 3339",
+	"Metadata.Labels":                       "e.g., {\"app\": \"nginx\"}",
+	"Metadata.ManagedFields":                "ManagedFields records which manager set which fields, see server-side apply",
+	"Metadata.OwnerReferences":              "OwnerReferences name the objects this one belongs to, e.g. the ReplicaSet that created a pod",
+	"Metadata.ResourceVersion":              "ResourceVersion changes on every write of the object. Updates and patches that carry one are rejected with a conflict unless it is still the stored one",
+	"Namespace":                             "Namespace groups namespaced objects such as pods and services.",
+	"NamespaceStatus":                       "NamespaceStatus is the observed state of a namespace.",
+	"NamespaceStatus.Phase":                 "Active, set by the API server",
+	"Node":                                  "Node is a machine running the node server, on which pods are scheduled.",
+	"Node.Pods":                             "List of pod UIDs running on this node",
+	"NodeCondition":                         "NodeCondition is one aspect of a node's health.",
+	"NodeCondition.Status":                  "True, False, Unknown",
+	"NodeCondition.Type":                    "Ready, DiskPressure, MemoryPressure, NetworkUnavailable",
+	"NodeStatus":                            "NodeStatus is the last state a node reported.",
+	"NodeStatus.Phase":                      "Ready, NotReady",
+	"NonResourceAttributes":                 "NonResourceAttributes describe a request for a plain path such as /healthz.",
+	"ObjectReference":                       "ObjectReference points at an object by kind, namespace and name.",
+	"OwnerReference":                        "OwnerReference points at an object's owner. At most one owner is the Controller that manages it.",
+	"Pod":                                   "Pod is a group of containers scheduled together onto one node.",
+	"PodExecOptions":                        "PodExecOptions describe a command to run in a pod's container through the pods/exec subresource.",
+	"PodExecOptions.Command":                "Command and its arguments, sent as one command parameter each",
+	"PodExecOptions.Container":              "Container to run in; defaults to the pod's first container",
+	"PodExecOptions.Stdin":                  "Stdin, Stdout and Stderr say which streams the command is given",
+	"PodExecOptions.TTY":                    "TTY runs the command in a terminal, whose output all goes to stdout",
+	"PodLogOptions":                         "PodLogOptions select the log lines the pods/log subresource returns. They travel as query parameters, from the CLI to the API server and on to the node server.",
+	"PodLogOptions.Container":               "Container to read; defaults to the pod's first container",
+	"PodLogOptions.Follow":                  "Follow keeps the stream open, sending lines as they are written",
+	"PodLogOptions.Previous":                "Previous reads the last terminated instance of the container",
+	"PodLogOptions.SinceSeconds":            "SinceSeconds only returns lines written in the last seconds; it cannot be combined with SinceTime",
+	"PodLogOptions.SinceTime":               "SinceTime only returns lines written after it",
+	"PodLogOptions.TailLines":               "TailLines only returns the last lines of the log",
+	"PodLogOptions.Timestamps":              "Timestamps prefixes each line with the RFC 3339 time it was written",
+	"PodPortForwardOptions":                 "PodPortForwardOptions name the port of a pod the pods/portforward subresource connects to. Each forwarded connection is a request of its own.",
+	"PodPortForwardOptions.Port":            "Port in the pod's network namespace",
+	"PodSpec":                               "PodSpec is the desired state of a pod.",
+	"PodSpec.NodeName":                      "empty until scheduled",
+	"PodSpec.Replicas":                      "for deployment",
+	"PodStatus":                             "PodStatus is the observed state of a pod, reported by its node.",
+	"PodStatus.AssignedPort":                "host port the container port is published on",
+	"PodStatus.Phase":                       "Pending, Running, Failed",
+	"PodTemplate":                           "PodTemplate represents the template for creating new pods",
+	"PodTemplateMetadata":                   "PodTemplateMetadata contains metadata for pod template",
+	"PolicyRule":                            "PolicyRule grants Verbs on Resources (optionally limited to ResourceNames), or on NonResourceURLs such as /healthz. \"*\" matches anything.",
+	"PolicyRule.Resources":                  "e.g. pods, pods/status",
+	"Quantity":                              "Quantity is a Kubernetes style resource amount such as \"250m\", \"1.5Gi\" or \"2e3\". Values are kept as an integer number of billionths (nano units) of arbitrary size, so that arithmetic and comparison are exact from 1n up to and beyond 1Ei; anything finer than 1n is rounded up.",
+	"ReplicaSet":                            "ReplicaSet keeps Replicas pods matching Selector running, creating them from Template.",
+	"ResourceAttributes":                    "ResourceAttributes describe a resource request for an access review.",
+	"ResourceList":                          "ResourceList maps a resource name to its amount, e.g. {\"cpu\": 250m, \"memory\": 64Mi}.",
+	"ResourceRequirements":                  "ResourceRequirements are the compute resources a container asks for and may not exceed.",
+	"ResourceRequirements.Limits":           "e.g., {\"cpu\": \"500m\", \"memory\": \"128Mi\"}",
+	"ResourceRequirements.Requests":         "e.g., {\"cpu\": \"250m\", \"memory\": \"64Mi\"}",
+	"Role":                                  "Role grants rules within a single namespace.",
+	"RoleBinding":                           "RoleBinding grants a Role, or a ClusterRole's rules, within its own namespace.",
+	"RoleRef":                               "RoleRef points a binding at a Role or ClusterRole.",
+	"RoleRef.Kind":                          "Role, ClusterRole",
+	"SelfSubjectAccessReview":               "SelfSubjectAccessReview asks whether the caller may perform an action.",
+	"Service":                               "Service exposes the pods matching its selector behind one set of ports.",
+	"ServiceMetadata":                       "ServiceMetadata is the metadata of a service.",
+	"ServicePort":                           "ServicePort maps a service port to the pods' target port.",
+	"ServiceSpec":                           "ServiceSpec is the desired state of a service.",
+	"Subject":                               "Subject is who a binding applies to.",
+	"Subject.Kind":                          "User, Group, ServiceAccount",
+	"Subject.Namespace":                     "ServiceAccount only",
+	"Volume":                                "Volume is storage the pod's containers can mount. Exactly one source is set.",
+	"VolumeMount":                           "VolumeMount mounts a volume at MountPath inside a container.",
+}
@@ -0,0 +1,122 @@
+// Command gen extracts the doc comments of the models package into the
+// descriptions used by the OpenAPI schemas. Run it through go generate in
+// the openapi package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	modelsDir := flag.String("models", "../models", "Directory of the models package")
+	out := flag.String("out", "descriptions.go", "File to write")
+	flag.Parse()
+
+	descriptions, err := collect(*modelsDir)
+	if err != nil {
+		log.Fatalf("gen: %v", err)
+	}
+
+	var keys []string
+	for key := range descriptions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	buf.WriteString("package openapi\n\n")
+	buf.WriteString("// descriptions holds the doc comments of the models types, keyed by type\n")
+	buf.WriteString("// name and by type name and Go field name, e.g. \"Pod.Spec\".\n")
+	buf.WriteString("var descriptions = map[string]string{\n")
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", key, descriptions[key])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("gen: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		log.Fatalf("gen: %v", err)
+	}
+}
+
+func collect(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := map[string]string{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if !typeSpec.Name.IsExported() {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				if text := commentText(doc); text != "" {
+					descriptions[typeSpec.Name.Name] = text
+				}
+
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range structType.Fields.List {
+					comment := field.Doc
+					if comment == nil {
+						comment = field.Comment
+					}
+					text := commentText(comment)
+					if text == "" {
+						continue
+					}
+					for _, name := range field.Names {
+						if name.IsExported() {
+							descriptions[typeSpec.Name.Name+"."+name.Name] = text
+						}
+					}
+				}
+			}
+		}
+	}
+	return descriptions, nil
+}
+
+// commentText joins a comment into one line.
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
@@ -0,0 +1,181 @@
+// Package openapi builds the OpenAPI v3 document the API server publishes
+// at /openapi/v3. Schemas are generated from the Go types of the models
+// package by reflection, with descriptions taken from their doc comments.
+package openapi
+
+//go:generate go run ./gen -models ../models -out descriptions.go
+
+import "strings"
+
+const refPrefix = "#/components/schemas/"
+
+// Document is an OpenAPI v3 document, limited to what the API server uses.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem holds the operations served at one path.
+type PathItem struct {
+	Parameters []Parameter `json:"parameters,omitempty"`
+	Get        *Operation  `json:"get,omitempty"`
+	Put        *Operation  `json:"put,omitempty"`
+	Post       *Operation  `json:"post,omitempty"`
+	Patch      *Operation  `json:"patch,omitempty"`
+	Delete     *Operation  `json:"delete,omitempty"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+
+	// Kubernetes extensions naming the verb and the kind acted on
+	Action           string            `json:"x-kubernetes-action,omitempty"`
+	GroupVersionKind *GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// GroupVersionKind identifies the kind a schema or operation belongs to.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Schema is a JSON schema. Fields referring to a named type carry a $ref,
+// wrapped in allOf when the field has its own description.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	GroupVersionKinds []GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// NewDocument returns an empty document.
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI:    "3.0.0",
+		Info:       Info{Title: title, Version: version},
+		Paths:      map[string]*PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+}
+
+// Ref returns a schema referring to the named component.
+func Ref(name string) *Schema {
+	return &Schema{Ref: refPrefix + name}
+}
+
+// Resolve follows $ref and single allOf wrappers to the schema they name.
+// Descriptions on the wrapper win over the referenced type's.
+func (d *Document) Resolve(s *Schema) *Schema {
+	description := ""
+	for s != nil {
+		if s.Description != "" && description == "" {
+			description = s.Description
+		}
+		switch {
+		case s.Ref != "":
+			s = d.Components.Schemas[strings.TrimPrefix(s.Ref, refPrefix)]
+			continue
+		case len(s.AllOf) == 1 && s.Type == "":
+			s = s.AllOf[0]
+			continue
+		}
+		break
+	}
+	if s == nil || description == "" || description == s.Description {
+		return s
+	}
+	resolved := *s
+	resolved.Description = description
+	return &resolved
+}
+
+// SchemaForKind finds the component schema of a kind.
+func (d *Document) SchemaForKind(group, kind string) (string, *Schema, bool) {
+	for name, s := range d.Components.Schemas {
+		for _, gvk := range s.GroupVersionKinds {
+			if gvk.Group == group && gvk.Kind == kind {
+				return name, s, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// TypeString describes the type of a schema for people, e.g. string,
+// []Container or map[string]Quantity.
+func (d *Document) TypeString(s *Schema) string {
+	name := ""
+	for s != nil {
+		if s.Ref != "" {
+			name = strings.TrimPrefix(s.Ref, refPrefix)
+			s = d.Components.Schemas[name]
+			continue
+		}
+		if len(s.AllOf) == 1 && s.Type == "" {
+			s = s.AllOf[0]
+			continue
+		}
+		break
+	}
+	if s == nil {
+		return name
+	}
+	switch {
+	case s.Type == "array":
+		return "[]" + d.TypeString(s.Items)
+	case s.Type == "object" && s.AdditionalProperties != nil:
+		return "map[string]" + d.TypeString(s.AdditionalProperties)
+	case s.Type == "object" && name != "", s.Format != "" && name != "":
+		return name
+	case s.Type == "object":
+		return "Object"
+	case s.Type == "":
+		return "any"
+	}
+	return s.Type
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// overrides are types whose JSON form is not what reflection suggests.
+var overrides = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(models.Quantity{}): {Type: "string", Format: "quantity"},
+	reflect.TypeOf(json.RawMessage{}): {Type: "object"},
+}
+
+// Define adds the schema of v's type to the components under name, along
+// with every named struct type it refers to, and returns a reference to
+// it. gvk marks the schema as the one of a kind.
+func (d *Document) Define(name string, v interface{}, gvk *GroupVersionKind) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	d.define(name, t)
+	if gvk == nil {
+		return Ref(name)
+	}
+	s := d.Components.Schemas[name]
+	for _, existing := range s.GroupVersionKinds {
+		if existing == *gvk {
+			return Ref(name)
+		}
+	}
+	s.GroupVersionKinds = append(s.GroupVersionKinds, *gvk)
+	return Ref(name)
+}
+
+func (d *Document) define(name string, t reflect.Type) {
+	if _, ok := d.Components.Schemas[name]; ok {
+		return
+	}
+	// Reserve the name first so recursive types terminate
+	s := &Schema{}
+	d.Components.Schemas[name] = s
+	if o, ok := overrides[t]; ok {
+		*s = o
+	} else {
+		*s = *d.structSchema(t)
+	}
+	if s.Description == "" {
+		s.Description = descriptions[baseName(t)]
+	}
+}
+
+// schemaOf returns a fresh schema for t, defining the named structs it
+// uses as components.
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	if o, ok := overrides[t]; ok {
+		if t.Kind() == reflect.Struct {
+			d.define(componentName(t), t)
+			return Ref(componentName(t))
+		}
+		return &o
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return d.schemaOf(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		d.define(componentName(t), t)
+		return Ref(componentName(t))
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	}
+	// interface{} and anything else may hold any value
+	return &Schema{}
+}
+
+// structSchema follows encoding/json: exported fields named by their json
+// tag, "-" skipped and untagged embedded structs inlined.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, value := range d.structSchema(embedded).Properties {
+					s.Properties[key] = value
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := d.schemaOf(field.Type)
+		if description := descriptions[baseName(t)+"."+field.Name]; description != "" {
+			if property.Ref != "" {
+				property = &Schema{AllOf: []*Schema{property}}
+			}
+			property.Description = description
+		}
+		s.Properties[name] = property
+	}
+	return s
+}
+
+// componentName names the component of a named type. Instantiated
+// generics such as List[Pod] become ListOfPod unless Define gave them a
+// name first.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name
+	}
+	var args []string
+	for _, arg := range strings.Split(name[i+1:len(name)-1], ",") {
+		args = append(args, arg[strings.LastIndex(arg, ".")+1:])
+	}
+	return name[:i] + "Of" + strings.Join(args, "And")
+}
+
+// baseName is the name descriptions are keyed by, without type arguments.
+func baseName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
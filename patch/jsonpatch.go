@@ -0,0 +1,262 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// operation is one step of an RFC 6902 JSON patch.
+type operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from,omitempty"`
+	Value *json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 patch, a list of add, remove, replace,
+// move, copy and test operations. The patch is all or nothing.
+func ApplyJSONPatch(original, patch []byte) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %v", err)
+	}
+
+	for i, op := range ops {
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func applyOperation(doc interface{}, op operation) (interface{}, error) {
+	value := func() (interface{}, error) {
+		if op.Value == nil {
+			return nil, fmt.Errorf("value is required")
+		}
+		return decode(*op.Value)
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "remove":
+		doc, _, err := remove(doc, op.Path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(doc, op.Path); err != nil {
+			return nil, err
+		}
+		doc, _, err = remove(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into itself", op.From)
+		}
+		doc, v, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "copy":
+		v, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		// Copy through JSON so the two locations do not share maps
+		data, _ := json.Marshal(v)
+		v, _ = decode(data)
+		return add(doc, op.Path, v)
+	case "test":
+		want, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(got, want) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// parsePointer splits an RFC 6901 pointer into unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+			current = next
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[i]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+	}
+	return current, nil
+}
+
+// add sets the value at pointer, inserting into arrays ("-" appends), and
+// returns the possibly replaced root.
+func add(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(doc, tokens, pointer, func(parent interface{}, last string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[last] = value
+			return node, nil
+		case []interface{}:
+			i := len(node)
+			if last != "-" {
+				if i, err = arrayIndex(last, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("path %q does not exist", pointer)
+	})
+}
+
+// remove deletes the value at pointer and returns the new root and the
+// removed value.
+func remove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	var removed interface{}
+	doc, err = update(doc, tokens, pointer, func(parent interface{}, last string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[last]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+			removed = value
+			delete(node, last)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(last, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("path %q does not exist", pointer)
+	})
+	return doc, removed, err
+}
+
+// update walks to the parent of the last token, lets change modify it and
+// stores the result back, since appending to a slice may move it.
+func update(doc interface{}, tokens []string, pointer string, change func(parent interface{}, last string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return change(doc, tokens[0])
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+		updated, err := update(child, tokens[1:], pointer, change)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+		return node, nil
+	case []interface{}:
+		i, err := arrayIndex(tokens[0], len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(node[i], tokens[1:], pointer, change)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = updated
+		return node, nil
+	}
+	return nil, fmt.Errorf("path %q does not exist", pointer)
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+// Equal reports whether two decoded JSON values are the same document.
+func Equal(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	var va, vb interface{}
+	json.Unmarshal(ja, &va)
+	json.Unmarshal(jb, &vb)
+	return reflect.DeepEqual(va, vb)
+}
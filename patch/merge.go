@@ -0,0 +1,124 @@
+// Package patch applies JSON merge patches (RFC 7386), JSON patches
+// (RFC 6902) and Kubernetes style strategic merge patches to JSON
+// documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Type is a patch format, named by its content type.
+type Type string
+
+const (
+	MergePatch          Type = "application/merge-patch+json"
+	JSONPatch           Type = "application/json-patch+json"
+	StrategicMergePatch Type = "application/strategic-merge-patch+json"
+
+	// ApplyPatch is a server-side apply configuration. It is not a patch
+	// in the sense of Apply; see package fieldmanager.
+	ApplyPatch Type = "application/apply-patch+yaml"
+)
+
+// Apply applies a patch of type t to original. mergeKeys is only used by
+// strategic merge patches.
+func Apply(t Type, original, patch []byte, mergeKeys MergeKeys) ([]byte, error) {
+	switch t {
+	case MergePatch:
+		return Merge(original, patch)
+	case JSONPatch:
+		return ApplyJSONPatch(original, patch)
+	case StrategicMergePatch:
+		return StrategicMerge(original, patch, mergeKeys)
+	}
+	return nil, fmt.Errorf("unsupported patch type %q", t)
+}
+
+// Merge applies an RFC 7386 merge patch: objects are merged recursively,
+// null removes a field and anything else, lists included, replaces.
+func Merge(original, patch []byte) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %v", err)
+	}
+	return json.Marshal(mergeValue(doc, p))
+}
+
+func mergeValue(doc, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	docObj, ok := doc.(map[string]interface{})
+	if !ok {
+		docObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(docObj, key)
+		} else {
+			docObj[key] = mergeValue(docObj[key], value)
+		}
+	}
+	return docObj
+}
+
+// CreateMergePatch returns the merge patch that turns original into
+// modified: removed fields are set to null and changed lists are sent
+// whole. Both documents must be objects.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("invalid original document: %v", err)
+	}
+	mod, err := decode(modified)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modified document: %v", err)
+	}
+	docObj, ok := doc.(map[string]interface{})
+	modObj, ok2 := mod.(map[string]interface{})
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("merge patches can only be created between objects")
+	}
+	return json.Marshal(diffObjects(docObj, modObj))
+}
+
+func diffObjects(original, modified map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key := range original {
+		if _, ok := modified[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range modified {
+		old, ok := original[key]
+		if ok && Equal(old, value) {
+			continue
+		}
+		oldObj, oldIsObj := old.(map[string]interface{})
+		newObj, newIsObj := value.(map[string]interface{})
+		if ok && oldIsObj && newIsObj {
+			patch[key] = diffObjects(oldObj, newObj)
+		} else {
+			patch[key] = value
+		}
+	}
+	return patch
+}
+
+// decode parses JSON keeping numbers exact.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
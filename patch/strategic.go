@@ -0,0 +1,170 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergeKeys maps a dotted field path, ignoring list indices, to the field
+// that identifies items of the list at that path. Lists without a merge key
+// are replaced like in a merge patch.
+//
+//	"spec.containers":       "name",
+//	"spec.containers.ports": "containerPort",
+type MergeKeys map[string]string
+
+const directive = "$patch"
+
+// StrategicMerge applies a strategic merge patch. It behaves like a merge
+// patch except that lists with a merge key are merged item by item, and
+// items or objects may carry a "$patch" directive:
+//
+//	{"$patch": "delete", "name": "sidecar"} removes the matching list item
+//	{"$patch": "replace", ...}              replaces instead of merging
+func StrategicMerge(original, patch []byte, mergeKeys MergeKeys) ([]byte, error) {
+	doc, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid strategic merge patch: %v", err)
+	}
+	patchObj, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("strategic merge patch must be an object")
+	}
+
+	merged, err := strategicMergeObject(doc, patchObj, "", mergeKeys)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+func strategicMergeObject(doc interface{}, patch map[string]interface{}, path string, mergeKeys MergeKeys) (interface{}, error) {
+	switch patch[directive] {
+	case nil:
+	case "replace":
+		return withoutDirective(patch), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported %s directive %v", displayPath(path), directive, patch[directive])
+	}
+
+	docObj, ok := doc.(map[string]interface{})
+	if !ok {
+		docObj = map[string]interface{}{}
+	}
+
+	for key, value := range patch {
+		if key == directive {
+			continue
+		}
+		fieldPath := joinPath(path, key)
+
+		switch v := value.(type) {
+		case nil:
+			delete(docObj, key)
+		case map[string]interface{}:
+			merged, err := strategicMergeObject(docObj[key], v, fieldPath, mergeKeys)
+			if err != nil {
+				return nil, err
+			}
+			docObj[key] = merged
+		case []interface{}:
+			mergeKey, ok := mergeKeys[fieldPath]
+			if !ok {
+				docObj[key] = v
+				continue
+			}
+			docList, _ := docObj[key].([]interface{})
+			merged, err := mergeList(docList, v, fieldPath, mergeKey, mergeKeys)
+			if err != nil {
+				return nil, err
+			}
+			docObj[key] = merged
+		default:
+			docObj[key] = v
+		}
+	}
+	return docObj, nil
+}
+
+// mergeList merges patch items into doc items that share the same value
+// of mergeKey, appending new items in patch order.
+func mergeList(doc, patch []interface{}, path, mergeKey string, mergeKeys MergeKeys) ([]interface{}, error) {
+	// A {"$patch": "replace"} item replaces the list with the other items
+	for _, item := range patch {
+		if obj, ok := item.(map[string]interface{}); ok && obj[directive] == "replace" && len(obj) == 1 {
+			var replaced []interface{}
+			for _, other := range patch {
+				if other, ok := other.(map[string]interface{}); ok && other[directive] == "replace" && len(other) == 1 {
+					continue
+				}
+				replaced = append(replaced, other)
+			}
+			return replaced, nil
+		}
+	}
+
+	result := append([]interface{}(nil), doc...)
+	for i, item := range patch {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d]: list items must be objects", displayPath(path), i)
+		}
+		key, ok := obj[mergeKey]
+		if !ok {
+			return nil, fmt.Errorf("%s[%d]: missing merge key %q", displayPath(path), i, mergeKey)
+		}
+
+		index := -1
+		for j, existing := range result {
+			if existingObj, ok := existing.(map[string]interface{}); ok && Equal(existingObj[mergeKey], key) {
+				index = j
+				break
+			}
+		}
+
+		if obj[directive] == "delete" {
+			if index >= 0 {
+				result = append(result[:index], result[index+1:]...)
+			}
+			continue
+		}
+		if index < 0 {
+			result = append(result, withoutDirective(obj))
+			continue
+		}
+		merged, err := strategicMergeObject(result[index], obj, path, mergeKeys)
+		if err != nil {
+			return nil, err
+		}
+		result[index] = merged
+	}
+	return result, nil
+}
+
+func withoutDirective(obj map[string]interface{}) map[string]interface{} {
+	clean := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if key != directive {
+			clean[key] = value
+		}
+	}
+	return clean
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
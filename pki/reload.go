@@ -0,0 +1,227 @@
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloadInterval is how often certificate files are checked for changes.
+const reloadInterval = 10 * time.Second
+
+// KeyPairReloader serves a certificate and key from disk, picking up new
+// files when they are replaced so certificates can be rotated without a
+// restart.
+type KeyPairReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func NewKeyPairReloader(certFile, keyFile string) (*KeyPairReloader, error) {
+	r := &KeyPairReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *KeyPairReloader) load() error {
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair %s: %v", r.certFile, err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// current returns the loaded certificate, reloading it if the files have
+// changed since the last check. A pair that fails to load is logged and the
+// previous certificate is kept.
+func (r *KeyPairReloader) current() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastCheck) < reloadInterval {
+		return r.cert
+	}
+	r.lastCheck = time.Now()
+
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil || !modTime.After(r.modTime) {
+		return r.cert
+	}
+	if err := r.load(); err != nil {
+		log.Printf("⚠️ Keeping previous certificate: %v", err)
+		return r.cert
+	}
+	log.Printf("🔄 Reloaded certificate %s", r.certFile)
+	return r.cert
+}
+
+// GetCertificate is for tls.Config.GetCertificate.
+func (r *KeyPairReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+// GetClientCertificate is for tls.Config.GetClientCertificate.
+func (r *KeyPairReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+// CAReloader serves a CA bundle from disk, picking up changes like
+// KeyPairReloader.
+type CAReloader struct {
+	file string
+
+	mu        sync.Mutex
+	pool      *x509.CertPool
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func NewCAReloader(file string) (*CAReloader, error) {
+	r := &CAReloader{file: file}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CAReloader) load() error {
+	modTime, err := latestModTime(r.file)
+	if err != nil {
+		return err
+	}
+	pool, err := LoadCertPool(r.file)
+	if err != nil {
+		return err
+	}
+	r.pool = pool
+	r.modTime = modTime
+	return nil
+}
+
+// Pool returns the current CA pool.
+func (r *CAReloader) Pool() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastCheck) < reloadInterval {
+		return r.pool
+	}
+	r.lastCheck = time.Now()
+
+	modTime, err := latestModTime(r.file)
+	if err != nil || !modTime.After(r.modTime) {
+		return r.pool
+	}
+	if err := r.load(); err != nil {
+		log.Printf("⚠️ Keeping previous CA bundle: %v", err)
+		return r.pool
+	}
+	log.Printf("🔄 Reloaded CA bundle %s", r.file)
+	return r.pool
+}
+
+// LoadCertPool reads a PEM bundle of CA certificates.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// ServerConfig returns a TLS config serving certFile/keyFile. When
+// clientCAFile is set, client certificates signed by it are verified, and
+// required if requireClientCert is true. All files are reloaded on change.
+func ServerConfig(certFile, keyFile, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	keyPair, err := NewKeyPairReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: keyPair.GetCertificate,
+	}
+	if clientCAFile == "" {
+		return config, nil
+	}
+
+	clientCAs, err := NewCAReloader(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth := tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	// Build a fresh config per handshake so a rotated client CA applies to
+	// new connections.
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := config.Clone()
+		c.GetConfigForClient = nil
+		c.ClientCAs = clientCAs.Pool()
+		c.ClientAuth = clientAuth
+		return c, nil
+	}
+	return config, nil
+}
+
+// ClientConfig returns a TLS config that trusts caFile and, if certFile is
+// set, presents that client certificate, reloading it on change.
+func ClientConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := LoadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" {
+		if keyFile == "" {
+			return nil, fmt.Errorf("client certificate given without a key")
+		}
+		keyPair, err := NewKeyPairReloader(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		config.GetClientCertificate = keyPair.GetClientCertificate
+	}
+	return config, nil
+}
+
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
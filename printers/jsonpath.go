@@ -0,0 +1,562 @@
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed JSONPath template such as
+// "{range .items[*]}{.metadata.name}{"\n"}{end}". It runs on decoded JSON:
+// maps, slices, strings, float64s, bools and nil.
+//
+// Supported are field names (.name or ['name']), recursive descent
+// (..name), wildcards (.* and [*]), indexes and slices ([0], [-1], [1:3]),
+// filters ([?(@.status.phase=="Running")]), range/end and quoted text.
+// Missing fields print nothing.
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+type jsonPathNode struct {
+	text    string    // literal text, when path is nil
+	path    []segment // an expression to print, or the list to range over
+	body    []jsonPathNode
+	isRange bool
+}
+
+type segmentKind int
+
+const (
+	segmentField segmentKind = iota
+	segmentRecursive
+	segmentWildcard
+	segmentIndex
+	segmentSlice
+	segmentFilter
+)
+
+type segment struct {
+	kind       segmentKind
+	name       string // field and recursive
+	index      int    // index
+	start, end *int   // slice
+	filter     *filter
+	root       bool // the expression started with $
+}
+
+type filter struct {
+	path  []segment
+	op    string // "" tests that path exists
+	value interface{}
+}
+
+// ParseJSONPath parses a template. Text outside braces is printed as is.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	nodes, rest, err := parseNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("jsonpath: unexpected {end}")
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// RelaxedJSONPath turns an expression such as .metadata.name, as used by
+// --sort-by and custom columns, into a template.
+func RelaxedJSONPath(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		return expr
+	}
+	if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "$") && !strings.HasPrefix(expr, "[") {
+		expr = "." + expr
+	}
+	return "{" + expr + "}"
+}
+
+// parseNodes parses until the end of s or, inside a range, until {end},
+// returning what follows it.
+func parseNodes(s string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+	for s != "" {
+		open := strings.Index(s, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: s})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: s[:open]})
+		}
+		close := matchingBrace(s, open)
+		if close < 0 {
+			return nil, "", fmt.Errorf("jsonpath: unclosed { in %q", s)
+		}
+		expr := strings.TrimSpace(s[open+1 : close])
+		s = s[close+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("jsonpath: {end} without {range}")
+			}
+			return nodes, s, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseNodes(s, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path, body: body, isRange: true})
+			s = rest
+			continue
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, "", fmt.Errorf("jsonpath: invalid text %s: %v", expr, err)
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path})
+		}
+	}
+	if inRange {
+		return nil, "", fmt.Errorf("jsonpath: {range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// matchingBrace finds the } closing the { at open, skipping quoted text.
+func matchingBrace(s string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+// parsePath parses an expression such as .items[*].metadata.name, @.x or
+// $.items.
+func parsePath(expr string) ([]segment, error) {
+	segments := []segment{} // never nil: {@} is a path to the current value
+	root := false
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		root, expr = true, expr[1:]
+	case strings.HasPrefix(expr, "@"):
+		expr = expr[1:]
+	}
+
+	for expr != "" {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			name, rest := identifier(expr[2:])
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: expected a field name after ..")
+			}
+			segments = append(segments, segment{kind: segmentRecursive, name: name})
+			expr = rest
+		case strings.HasPrefix(expr, "."):
+			if strings.HasPrefix(expr, ".*") {
+				segments = append(segments, segment{kind: segmentWildcard})
+				expr = expr[2:]
+				continue
+			}
+			name, rest := identifier(expr[1:])
+			if name != "" {
+				segments = append(segments, segment{kind: segmentField, name: name})
+			}
+			expr = rest
+		case strings.HasPrefix(expr, "["):
+			end := matchingBracket(expr)
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed [ in %q", expr)
+			}
+			seg, err := parseBracket(strings.TrimSpace(expr[1:end]))
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+			expr = expr[end+1:]
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected %q", expr)
+		}
+	}
+	if root {
+		segments = append([]segment{{root: true}}, segments...)
+	}
+	return segments, nil
+}
+
+// identifier reads a field name up to the next . or [.
+func identifier(s string) (string, string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// matchingBracket finds the ] closing the [ that starts s.
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(inner string) (segment, error) {
+	switch {
+	case inner == "*":
+		return segment{kind: segmentWildcard}, nil
+	case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+		name, err := unquote(inner)
+		if err != nil {
+			return segment{}, fmt.Errorf("jsonpath: invalid field %s", inner)
+		}
+		return segment{kind: segmentField, name: name}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		f, err := parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+		if err != nil {
+			return segment{}, err
+		}
+		return segment{kind: segmentFilter, filter: f}, nil
+	case strings.Contains(inner, ":"):
+		parts := strings.SplitN(inner, ":", 2)
+		seg := segment{kind: segmentSlice}
+		for i, part := range parts {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return segment{}, fmt.Errorf("jsonpath: invalid slice [%s]", inner)
+			}
+			if i == 0 {
+				seg.start = &n
+			} else {
+				seg.end = &n
+			}
+		}
+		return seg, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, fmt.Errorf("jsonpath: invalid index [%s]", inner)
+	}
+	return segment{kind: segmentIndex, index: n}, nil
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses "@.path op value" or "@.path".
+func parseFilter(expr string) (*filter, error) {
+	if !strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("jsonpath: filter %q must start with @", expr)
+	}
+	for _, op := range filterOperators {
+		i := strings.Index(expr, op)
+		if i < 0 {
+			continue
+		}
+		path, err := parsePath(strings.TrimSpace(expr[:i]))
+		if err != nil {
+			return nil, err
+		}
+		raw := strings.TrimSpace(expr[i+len(op):])
+		var value interface{}
+		if strings.HasPrefix(raw, "'") || strings.HasPrefix(raw, `"`) {
+			if value, err = unquote(raw); err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid value %s", raw)
+			}
+		} else if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("jsonpath: invalid value %s", raw)
+		}
+		return &filter{path: path, op: op, value: value}, nil
+	}
+	path, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &filter{path: path}, nil
+}
+
+// Execute writes the template evaluated against data to w.
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	return j.execute(w, j.nodes, data, data)
+}
+
+func (j *JSONPath) execute(w io.Writer, nodes []jsonPathNode, root, current interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			for _, item := range evaluate(node.path, root, current) {
+				if err := j.execute(w, node.body, root, item); err != nil {
+					return err
+				}
+			}
+		case node.path != nil:
+			values := evaluate(node.path, root, current)
+			texts := make([]string, len(values))
+			for i, value := range values {
+				texts[i] = FormatValue(value)
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		default:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FindResults evaluates the template's single expression and returns the
+// values it selects, e.g. one per item for {.items[*].metadata.name}.
+func (j *JSONPath) FindResults(data interface{}) ([]interface{}, error) {
+	var paths [][]segment
+	for _, node := range j.nodes {
+		if node.path != nil && !node.isRange {
+			paths = append(paths, node.path)
+		}
+	}
+	if len(paths) != 1 {
+		return nil, fmt.Errorf("jsonpath: expected a single expression")
+	}
+	return evaluate(paths[0], data, data), nil
+}
+
+func evaluate(path []segment, root, current interface{}) []interface{} {
+	values := []interface{}{current}
+	for _, seg := range path {
+		if seg.root {
+			values = []interface{}{root}
+			continue
+		}
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, step(seg, root, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func step(seg segment, root, value interface{}) []interface{} {
+	switch seg.kind {
+	case segmentField:
+		if m, ok := value.(map[string]interface{}); ok {
+			if v, ok := m[seg.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case segmentRecursive:
+		var found []interface{}
+		walk(value, func(m map[string]interface{}) {
+			if v, ok := m[seg.name]; ok {
+				found = append(found, v)
+			}
+		})
+		return found
+	case segmentWildcard:
+		return children(value)
+	case segmentIndex:
+		if list, ok := value.([]interface{}); ok {
+			i := seg.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []interface{}{list[i]}
+			}
+		}
+	case segmentSlice:
+		if list, ok := value.([]interface{}); ok {
+			start, end := 0, len(list)
+			if seg.start != nil {
+				start = clamp(*seg.start, len(list))
+			}
+			if seg.end != nil {
+				end = clamp(*seg.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case segmentFilter:
+		var matched []interface{}
+		for _, child := range children(value) {
+			if seg.filter.matches(root, child) {
+				matched = append(matched, child)
+			}
+		}
+		return matched
+	}
+	return nil
+}
+
+func clamp(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// children returns a list's items or a map's values in key order.
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = v[key]
+		}
+		return values
+	}
+	return nil
+}
+
+// walk calls fn for value and every map nested in it.
+func walk(value interface{}, fn func(map[string]interface{})) {
+	if m, ok := value.(map[string]interface{}); ok {
+		fn(m)
+	}
+	for _, child := range children(value) {
+		walk(child, fn)
+	}
+}
+
+func (f *filter) matches(root, item interface{}) bool {
+	values := evaluate(f.path, root, item)
+	if f.op == "" {
+		return len(values) > 0
+	}
+	for _, value := range values {
+		if compare(value, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(left interface{}, op string, right interface{}) bool {
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			switch op {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case "<":
+				return l < r
+			case ">":
+				return l > r
+			case "<=":
+				return l <= r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+	switch op {
+	case "==":
+		return reflect.DeepEqual(left, right) || FormatValue(left) == FormatValue(right)
+	case "!=":
+		return !(reflect.DeepEqual(left, right) || FormatValue(left) == FormatValue(right))
+	}
+	l, r := FormatValue(left), FormatValue(right)
+	switch op {
+	case "<":
+		return l < r
+	case ">":
+		return l > r
+	case "<=":
+		return l <= r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+// FormatValue prints a decoded JSON value: strings as they are, numbers
+// without exponents, nil as nothing and maps and lists as JSON.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,367 @@
+// Package printers prints lists of API objects for the CLI: as tables, as
+// JSON or YAML, by name, or through JSONPath, Go templates and custom
+// columns.
+package printers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options choose how a list is printed. They come from the -o,
+// --sort-by, --no-headers and --show-labels flags of the get commands.
+type Options struct {
+	Output     string // "", wide, json, yaml, name, jsonpath=, go-template= or custom-columns=
+	SortBy     string // a JSONPath expression such as .metadata.name
+	NoHeaders  bool
+	ShowLabels bool
+
+	// Single is set when one object was asked for by name. json, yaml,
+	// jsonpath and go-template then print the object instead of a List.
+	Single bool
+}
+
+// Column is a table column. Wide columns are only shown with -o wide.
+type Column struct {
+	Name string
+	Wide bool
+}
+
+// Table is the human-readable form of a list, one row per object.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// Printer prints the objects of one resource, decoded from JSON, together
+// with their table.
+type Printer interface {
+	Print(w io.Writer, objects []interface{}, table Table) error
+}
+
+// IsTable reports whether the output is a table, for which commands
+// print a message instead of an empty list.
+func (o Options) IsTable() bool {
+	return o.Output == "" || o.Output == "wide"
+}
+
+// NewPrinter returns the printer for opts. resource is the prefix printed
+// by -o name, e.g. pod or role.rbac.authorization.k8s.io.
+func NewPrinter(opts Options, resource string) (Printer, error) {
+	format, arg, hasArg := strings.Cut(opts.Output, "=")
+	if hasArg && arg == "" {
+		return nil, fmt.Errorf("output format %s needs a template", format)
+	}
+
+	switch format {
+	case "", "wide":
+		if hasArg {
+			break
+		}
+		return &tablePrinter{wide: format == "wide", noHeaders: opts.NoHeaders, showLabels: opts.ShowLabels}, nil
+	case "json", "yaml":
+		if hasArg {
+			break
+		}
+		return &listPrinter{yaml: format == "yaml", single: opts.Single}, nil
+	case "name":
+		if hasArg {
+			break
+		}
+		return &namePrinter{resource: resource}, nil
+	case "jsonpath":
+		if !hasArg {
+			return nil, fmt.Errorf("output format jsonpath needs a template, e.g. -o jsonpath='{.items[*].metadata.name}'")
+		}
+		path, err := ParseJSONPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathPrinter{path: path, single: opts.Single}, nil
+	case "go-template":
+		if !hasArg {
+			return nil, fmt.Errorf("output format go-template needs a template, e.g. -o go-template='{{range .items}}{{.metadata.name}} {{end}}'")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid go-template: %v", err)
+		}
+		return &templatePrinter{template: tmpl, single: opts.Single}, nil
+	case "custom-columns":
+		if !hasArg {
+			return nil, fmt.Errorf("output format custom-columns needs a spec, e.g. -o custom-columns=NAME:.metadata.name")
+		}
+		return newCustomColumnsPrinter(arg, opts.NoHeaders)
+	}
+	return nil, fmt.Errorf("unknown output format %q: use json, yaml, wide, name, jsonpath=<template>, go-template=<template> or custom-columns=<spec>", opts.Output)
+}
+
+// Decode turns an object into the maps and slices JSONPath and templates
+// work on.
+func Decode(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// SortOrder returns the order of objects sorted by the value of the
+// JSONPath expression sortBy. Numbers sort numerically, everything else as
+// text; objects missing the field come first.
+func SortOrder(objects []interface{}, sortBy string) ([]int, error) {
+	path, err := ParseJSONPath(RelaxedJSONPath(sortBy))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --sort-by %q: %v", sortBy, err)
+	}
+	keys := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		values, err := path.FindResults(obj)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --sort-by %q: %v", sortBy, err)
+		}
+		if len(values) > 0 {
+			keys[i] = values[0]
+		}
+	}
+
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(keys[order[i]], keys[order[j]])
+	})
+	return order, nil
+}
+
+func less(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return x < y
+		}
+	}
+	return FormatValue(a) < FormatValue(b)
+}
+
+// objectName and objectLabels read metadata.name and metadata.labels, or
+// name and labels for objects without metadata such as nodes.
+func objectName(obj interface{}) string {
+	m, _ := obj.(map[string]interface{})
+	if meta, ok := m["metadata"].(map[string]interface{}); ok {
+		m = meta
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+func objectLabels(obj interface{}) map[string]interface{} {
+	m, _ := obj.(map[string]interface{})
+	if meta, ok := m["metadata"].(map[string]interface{}); ok {
+		m = meta
+	}
+	labels, _ := m["labels"].(map[string]interface{})
+	return labels
+}
+
+// list is the envelope -o json and -o yaml print, as kubectl does;
+// newList is the same for JSONPath and templates.
+type list struct {
+	APIVersion string        `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string        `json:"kind" yaml:"kind"`
+	Items      []interface{} `json:"items" yaml:"items"`
+}
+
+// newList returns what JSONPath and templates see: the object itself for
+// a single object, a List otherwise.
+func newList(objects []interface{}, single bool) interface{} {
+	if single && len(objects) == 1 {
+		return objects[0]
+	}
+	if objects == nil {
+		objects = []interface{}{}
+	}
+	return map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": objects}
+}
+
+type tablePrinter struct {
+	wide, noHeaders, showLabels bool
+}
+
+func (p *tablePrinter) Print(w io.Writer, objects []interface{}, table Table) error {
+	var shown []int
+	for i, column := range table.Columns {
+		if p.wide || !column.Wide {
+			shown = append(shown, i)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if !p.noHeaders {
+		var headers []string
+		for _, i := range shown {
+			headers = append(headers, table.Columns[i].Name)
+		}
+		if p.showLabels {
+			headers = append(headers, "LABELS")
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+	for r, row := range table.Rows {
+		var cells []string
+		for _, i := range shown {
+			if i < len(row) {
+				cells = append(cells, row[i])
+			} else {
+				cells = append(cells, "")
+			}
+		}
+		if p.showLabels && r < len(objects) {
+			cells = append(cells, formatLabels(objectLabels(objects[r])))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatLabels prints labels as k=v pairs sorted by key.
+func formatLabels(labels map[string]interface{}) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+FormatValue(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+type listPrinter struct {
+	yaml, single bool
+}
+
+func (p *listPrinter) Print(w io.Writer, objects []interface{}, _ Table) error {
+	if objects == nil {
+		objects = []interface{}{}
+	}
+	var l interface{} = list{APIVersion: "v1", Kind: "List", Items: objects}
+	if p.single && len(objects) == 1 {
+		l = objects[0]
+	}
+	if p.yaml {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(l); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	data, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type namePrinter struct {
+	resource string
+}
+
+func (p *namePrinter) Print(w io.Writer, objects []interface{}, _ Table) error {
+	for _, obj := range objects {
+		if _, err := fmt.Fprintf(w, "%s/%s\n", p.resource, objectName(obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonPathPrinter struct {
+	path   *JSONPath
+	single bool
+}
+
+func (p *jsonPathPrinter) Print(w io.Writer, objects []interface{}, _ Table) error {
+	return p.path.Execute(w, newList(objects, p.single))
+}
+
+type templatePrinter struct {
+	template *template.Template
+	single   bool
+}
+
+func (p *templatePrinter) Print(w io.Writer, objects []interface{}, _ Table) error {
+	if err := p.template.Execute(w, newList(objects, p.single)); err != nil {
+		return fmt.Errorf("error executing go-template: %v", err)
+	}
+	return nil
+}
+
+type customColumnsPrinter struct {
+	headers   []string
+	paths     []*JSONPath
+	noHeaders bool
+}
+
+// newCustomColumnsPrinter parses a spec such as
+// NAME:.metadata.name,NODE:.spec.nodeName.
+func newCustomColumnsPrinter(spec string, noHeaders bool) (*customColumnsPrinter, error) {
+	p := &customColumnsPrinter{noHeaders: noHeaders}
+	for _, column := range strings.Split(spec, ",") {
+		header, expr, ok := strings.Cut(column, ":")
+		if !ok || header == "" || expr == "" {
+			return nil, fmt.Errorf("invalid custom-columns %q: expected <header>:<jsonpath>[,...]", column)
+		}
+		path, err := ParseJSONPath(RelaxedJSONPath(expr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom column %s: %v", header, err)
+		}
+		p.headers = append(p.headers, header)
+		p.paths = append(p.paths, path)
+	}
+	return p, nil
+}
+
+func (p *customColumnsPrinter) Print(w io.Writer, objects []interface{}, _ Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if !p.noHeaders {
+		fmt.Fprintln(tw, strings.Join(p.headers, "\t"))
+	}
+	for _, obj := range objects {
+		cells := make([]string, len(p.paths))
+		for i, path := range p.paths {
+			values, err := path.FindResults(obj)
+			if err != nil {
+				return err
+			}
+			texts := make([]string, 0, len(values))
+			for _, value := range values {
+				if value != nil {
+					texts = append(texts, FormatValue(value))
+				}
+			}
+			cells[i] = "<none>"
+			if len(texts) > 0 {
+				cells[i] = strings.Join(texts, ",")
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
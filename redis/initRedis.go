@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -15,19 +14,9 @@ var (
 	Ctx         = context.Background()
 )
 
-func InitRedis() {
-	// Get Redis configuration from environment variables
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-
-	// Use default values if environment variables are not set
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-
+// InitRedis connects to Redis at host:port, retrying for a while before
+// giving up.
+func InitRedis(redisHost, redisPort string) {
 	RedisClient = redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", redisHost, redisPort),
 		Password: "", // No password by default
@@ -0,0 +1,171 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseLabelSelector parses a label selector. Terms are separated by commas
+// and each is one of:
+//
+//	key=value, key==value, key!=value
+//	key in (v1,v2), key notin (v1,v2)
+//	key, !key
+func ParseLabelSelector(s string) (Selector, error) {
+	terms, err := splitTerms(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var selector Selector
+	for _, term := range terms {
+		r, err := parseLabelTerm(term)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %v", s, err)
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// ParseFieldSelector parses a field selector, which only supports =, ==
+// and !=, e.g. "spec.nodeName=worker1,status.phase!=Failed".
+func ParseFieldSelector(s string) (Selector, error) {
+	terms, err := splitTerms(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var selector Selector
+	for _, term := range terms {
+		key, op, value, ok := splitEquality(term)
+		if !ok {
+			return nil, fmt.Errorf("invalid field selector %q: %q is not of the form field=value or field!=value", s, term)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid field selector %q: empty field name", s)
+		}
+		selector = append(selector, Requirement{Key: key, Operator: op, Values: []string{value}})
+	}
+	return selector, nil
+}
+
+// splitTerms splits on commas that are not inside parentheses.
+func splitTerms(s string) ([]string, error) {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				terms = appendTerm(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+	}
+	return appendTerm(terms, s[start:]), nil
+}
+
+func appendTerm(terms []string, term string) []string {
+	if term = strings.TrimSpace(term); term != "" {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func parseLabelTerm(term string) (Requirement, error) {
+	if strings.HasPrefix(term, "!") {
+		key := strings.TrimSpace(term[1:])
+		if err := validateKey(key); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	if open := strings.Index(term, "("); open >= 0 {
+		if !strings.HasSuffix(term, ")") {
+			return Requirement{}, fmt.Errorf("%q: missing closing parenthesis", term)
+		}
+		fields := strings.Fields(term[:open])
+		if len(fields) != 2 || (fields[1] != string(In) && fields[1] != string(NotIn)) {
+			return Requirement{}, fmt.Errorf("%q: expected \"key in (...)\" or \"key notin (...)\"", term)
+		}
+		if err := validateKey(fields[0]); err != nil {
+			return Requirement{}, err
+		}
+		var values []string
+		for _, value := range strings.Split(term[open+1:len(term)-1], ",") {
+			value = strings.TrimSpace(value)
+			if err := validateValue(value); err != nil {
+				return Requirement{}, err
+			}
+			values = append(values, value)
+		}
+		return Requirement{Key: fields[0], Operator: Operator(fields[1]), Values: values}, nil
+	}
+
+	if key, op, value, ok := splitEquality(term); ok {
+		if err := validateKey(key); err != nil {
+			return Requirement{}, err
+		}
+		if err := validateValue(value); err != nil {
+			return Requirement{}, err
+		}
+		return Requirement{Key: key, Operator: op, Values: []string{value}}, nil
+	}
+
+	if err := validateKey(term); err != nil {
+		return Requirement{}, err
+	}
+	return Requirement{Key: term, Operator: Exists}, nil
+}
+
+// splitEquality splits "k=v", "k==v" or "k!=v".
+func splitEquality(term string) (key string, op Operator, value string, ok bool) {
+	if i := strings.Index(term, "!="); i >= 0 {
+		return strings.TrimSpace(term[:i]), NotEquals, strings.TrimSpace(term[i+2:]), true
+	}
+	if i := strings.Index(term, "=="); i >= 0 {
+		return strings.TrimSpace(term[:i]), Equals, strings.TrimSpace(term[i+2:]), true
+	}
+	if i := strings.Index(term, "="); i >= 0 {
+		return strings.TrimSpace(term[:i]), Equals, strings.TrimSpace(term[i+1:]), true
+	}
+	return "", "", "", false
+}
+
+// validateKey accepts label keys such as "app" or "example.com/tier".
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty label key")
+	}
+	for _, c := range key {
+		if !isLabelChar(c) && c != '/' {
+			return fmt.Errorf("invalid character %q in label key %q", c, key)
+		}
+	}
+	return nil
+}
+
+func validateValue(value string) error {
+	for _, c := range value {
+		if !isLabelChar(c) {
+			return fmt.Errorf("invalid character %q in label value %q", c, value)
+		}
+	}
+	return nil
+}
+
+func isLabelChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.'
+}
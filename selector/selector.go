@@ -0,0 +1,120 @@
+// Package selector parses and evaluates Kubernetes style label and field
+// selectors such as "app=web,tier in (frontend,cache),!canary" or
+// "spec.nodeName=worker1,status.phase!=Failed".
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single condition on one key.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string // one value for Equals/NotEquals, any number for In/NotIn
+}
+
+// Matches reports whether the key/value set satisfies the requirement.
+func (r Requirement) Matches(set map[string]string) bool {
+	value, ok := set[r.Key]
+	switch r.Operator {
+	case Equals:
+		return ok && value == r.Values[0]
+	case NotEquals:
+		// Like Kubernetes, a missing key satisfies "!="
+		return !ok || value != r.Values[0]
+	case In:
+		return ok && contains(r.Values, value)
+	case NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	}
+	return r.Key + string(r.Operator) + r.Values[0]
+}
+
+// Selector is a list of requirements that must all match. The empty
+// selector matches everything.
+type Selector []Requirement
+
+func (s Selector) Matches(set map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// RequiresExactMatch returns the value key must equal, if the selector pins
+// it with "=". Callers use it to narrow a lookup before filtering.
+func (s Selector) RequiresExactMatch(key string) (string, bool) {
+	for _, r := range s {
+		if r.Key == key && (r.Operator == Equals || r.Operator == In && len(r.Values) == 1) {
+			return r.Values[0], true
+		}
+	}
+	return "", false
+}
+
+// Keys returns the keys the selector refers to, sorted.
+func (s Selector) Keys() []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, r := range s {
+		if !seen[r.Key] {
+			seen[r.Key] = true
+			keys = append(keys, r.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// templateMergeKeys are podMergeKeys inside spec.template.
+var templateMergeKeys = func() patch.MergeKeys {
+	keys := patch.MergeKeys{}
+	for path, key := range podMergeKeys {
+		keys["spec.template."+path] = key
+	}
+	return keys
+}()
+
+// appsResources are the workload kinds of the apps group. They are stored
+// as they are; no controller acts on them yet.
+var appsResources = []objectResource{
+	{
+		group: appsGroup, name: "replicasets", kind: "ReplicaSet", namespaced: true, mergeKeys: templateMergeKeys,
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var rs models.ReplicaSet
+			err := json.NewDecoder(body).Decode(&rs)
+			return &rs, &rs.Metadata, err
+		},
+		validate: func(obj interface{}) error {
+			rs := obj.(*models.ReplicaSet)
+			rs.APIVersion, rs.Kind = appsGroup+"/v1", "ReplicaSet"
+			return validateWorkload(rs.Spec.Replicas, rs.Spec.Selector, rs.Spec.Template)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveReplicaSet(*obj.(*models.ReplicaSet)) },
+		get:  func(ns, name string) (interface{}, bool, error) { return store.GetReplicaSet(ns, name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, appsGroup+"/v1", "ReplicaSetList", metadataFields,
+				func(o models.ReplicaSet) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.ReplicaSet) bool) (store.ListResult[models.ReplicaSet], error) {
+					return store.ListReplicaSetsPage(mux.Vars(r)["namespace"], opts, filter)
+				})
+		},
+		remove: store.DeleteReplicaSet,
+	},
+	{
+		group: appsGroup, name: "deployments", kind: "Deployment", namespaced: true, mergeKeys: templateMergeKeys,
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var deployment models.Deployment
+			err := json.NewDecoder(body).Decode(&deployment)
+			return &deployment, &deployment.Metadata, err
+		},
+		validate: func(obj interface{}) error {
+			deployment := obj.(*models.Deployment)
+			deployment.APIVersion, deployment.Kind = appsGroup+"/v1", "Deployment"
+			switch deployment.Spec.Strategy.Type {
+			case "":
+				deployment.Spec.Strategy.Type = "RollingUpdate"
+			case "RollingUpdate", "Recreate":
+			default:
+				return fmt.Errorf("spec.strategy.type must be RollingUpdate or Recreate")
+			}
+			return validateWorkload(deployment.Spec.Replicas, deployment.Spec.Selector, deployment.Spec.Template)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveDeployment(*obj.(*models.Deployment)) },
+		get:  func(ns, name string) (interface{}, bool, error) { return store.GetDeployment(ns, name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, appsGroup+"/v1", "DeploymentList", metadataFields,
+				func(o models.Deployment) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.Deployment) bool) (store.ListResult[models.Deployment], error) {
+					return store.ListDeploymentsPage(mux.Vars(r)["namespace"], opts, filter)
+				})
+		},
+		remove: store.DeleteDeployment,
+	},
+}
+
+// validateWorkload checks what replica sets and deployments have in
+// common: a selector that matches the labels of a runnable pod template.
+func validateWorkload(replicas int, selector models.LabelSelector, template models.PodTemplate) error {
+	if replicas < 0 {
+		return fmt.Errorf("spec.replicas must not be negative")
+	}
+	if len(selector.MatchLabels) == 0 {
+		return fmt.Errorf("spec.selector.matchLabels is required")
+	}
+	if !matchLabels(template.Metadata.Labels, selector.MatchLabels) {
+		return fmt.Errorf("spec.selector does not match spec.template.metadata.labels")
+	}
+	if len(template.Spec.Containers) == 0 {
+		return fmt.Errorf("spec.template.spec.containers is required")
+	}
+	for i, container := range template.Spec.Containers {
+		if container.Name == "" || container.Image == "" {
+			return fmt.Errorf("spec.template.spec.containers[%d]: name and image are required", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/audit"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+)
+
+// maxAuditBodySize caps how much of a request or response body is kept in
+// an audit event.
+const maxAuditBodySize = 1 << 20
+
+// withAudit records every request the policy selects once its response is
+// written. It runs after authenticate so the user is known, and before
+// authorize so denied requests are recorded too.
+func (s *APIServer) withAudit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.auditBackend == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, _ := auth.UserFrom(r.Context())
+		attrs := requestAttributes(r, user)
+		level := s.auditPolicy.LevelFor(attrs)
+		if level == audit.LevelNone {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		event := &audit.Event{
+			Kind:                     "Event",
+			Level:                    level,
+			AuditID:                  newAuditID(),
+			RequestURI:               r.URL.RequestURI(),
+			Verb:                     attrs.Verb,
+			User:                     *user,
+			SourceIPs:                sourceIPs(r),
+			UserAgent:                r.UserAgent(),
+			RequestReceivedTimestamp: time.Now().UTC(),
+		}
+		if attrs.IsResourceRequest {
+			event.ObjectRef = &audit.ObjectRef{
+				Resource:    attrs.Resource,
+				Subresource: attrs.Subresource,
+				Namespace:   attrs.Namespace,
+				Name:        attrs.Name,
+			}
+		}
+
+		if !level.Less(audit.LevelRequest) && r.Body != nil {
+			body, complete := peekBody(r)
+			if complete && json.Valid(body) {
+				event.RequestObject = json.RawMessage(body)
+			}
+		}
+
+		recorder := &auditResponseWriter{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+			captureBody:    level == audit.LevelRequestResponse,
+		}
+		w.Header().Set("Audit-ID", event.AuditID)
+
+		defer func() {
+			event.ResponseStatus.Code = recorder.status
+			if recorder.captureBody && !recorder.truncated && json.Valid(recorder.body.Bytes()) {
+				event.ResponseObject = json.RawMessage(bytes.TrimSpace(recorder.body.Bytes()))
+			}
+			event.StageTimestamp = time.Now().UTC()
+			s.auditBackend.ProcessEvent(event)
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// peekBody reads up to maxAuditBodySize of the request body and puts it
+// back so handlers see the whole body. complete is false if the body was
+// larger than the limit.
+func peekBody(r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBodySize+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxAuditBodySize {
+		return nil, false
+	}
+	return body, true
+}
+
+func newAuditID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// sourceIPs lists the X-Forwarded-For chain followed by the peer address.
+func sourceIPs(r *http.Request) []string {
+	var ips []string
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		for _, ip := range strings.Split(forwarded, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return append(ips, host)
+}
+
+// auditResponseWriter records the status code and, if asked, the body of a
+// response. It passes Flush and Hijack through so streaming handlers keep
+// working.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	captureBody bool
+	body        bytes.Buffer
+	truncated   bool
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	if w.captureBody && !w.truncated {
+		if w.body.Len()+len(data) > maxAuditBodySize {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// storeRBAC and storePods adapt the store package to the auth interfaces.
+type storeRBAC struct{}
+
+func (storeRBAC) GetRole(namespace, name string) (models.Role, bool, error) {
+	return store.GetRole(namespace, name)
+}
+
+func (storeRBAC) GetClusterRole(name string) (models.ClusterRole, bool, error) {
+	return store.GetClusterRole(name)
+}
+
+func (storeRBAC) ListRoleBindings(namespace string) ([]models.RoleBinding, error) {
+	return store.ListRoleBindings(namespace)
+}
+
+func (storeRBAC) ListClusterRoleBindings() ([]models.ClusterRoleBinding, error) {
+	return store.ListClusterRoleBindings()
+}
+
+type storePods struct{}
+
+func (storePods) GetPod(namespace, name string) (models.Pod, bool) {
+	return store.GetPod(namespace, name)
+}
+
+// newAuthorizer builds the authorizer chain for modes such as
+// ["Node", "RBAC"]. No modes means everything is allowed.
+func newAuthorizer(modes []string) (auth.Authorizer, error) {
+	if len(modes) == 0 {
+		return auth.AlwaysAllow{}, nil
+	}
+
+	var chain auth.Chain
+	for _, mode := range modes {
+		switch strings.TrimSpace(mode) {
+		case "AlwaysAllow":
+			chain = append(chain, auth.AlwaysAllow{})
+		case "Node":
+			chain = append(chain, auth.NewNodeAuthorizer(storePods{}))
+		case "RBAC":
+			chain = append(chain, auth.NewRBACAuthorizer(storeRBAC{}))
+		default:
+			return nil, fmt.Errorf("unknown authorization mode %q", mode)
+		}
+	}
+	return chain, nil
+}
+
+func (s *APIServer) rbacEnabled() bool {
+	for _, mode := range s.options.AuthorizationModes {
+		if strings.TrimSpace(mode) == "RBAC" {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize rejects requests the authorizer does not allow. It runs after
+// authenticate, so the user is always in the context.
+func (s *APIServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := auth.UserFrom(r.Context())
+		attrs := requestAttributes(r, user)
+
+		decision, reason, err := s.authorizer.Authorize(attrs)
+		if err != nil {
+			fmt.Printf("❌ Authorization error for %s %s: %v\n", r.Method, r.URL.Path, err)
+			respondError(w, http.StatusInternalServerError, "authorization failed: "+err.Error())
+			return
+		}
+		if decision != auth.DecisionAllow {
+			msg := "Forbidden: " + attrs.String()
+			if reason != "" {
+				msg += ": " + reason
+			}
+			fmt.Printf("🚫 %s\n", msg)
+			respondError(w, http.StatusForbidden, msg)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *APIServer) handleSelfSubjectAccessReview(w http.ResponseWriter, r *http.Request) {
+	var review models.SelfSubjectAccessReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	user, _ := auth.UserFrom(r.Context())
+	attrs := auth.Attributes{User: user}
+	switch {
+	case review.Spec.ResourceAttributes != nil:
+		ra := review.Spec.ResourceAttributes
+		attrs.IsResourceRequest = true
+		attrs.Verb = ra.Verb
+		attrs.Resource = ra.Resource
+		attrs.Subresource = ra.Subresource
+		attrs.Name = ra.Name
+		attrs.Namespace = ra.Namespace
+	case review.Spec.NonResourceAttributes != nil:
+		attrs.Verb = review.Spec.NonResourceAttributes.Verb
+		attrs.Path = review.Spec.NonResourceAttributes.Path
+	default:
+		respondError(w, http.StatusBadRequest, "resourceAttributes or nonResourceAttributes is required")
+		return
+	}
+
+	decision, reason, err := s.authorizer.Authorize(attrs)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	review.Status = models.SubjectAccessReviewStatus{
+		Allowed: decision == auth.DecisionAllow,
+		Denied:  decision == auth.DecisionDeny,
+		Reason:  reason,
+	}
+	respondJSON(w, http.StatusCreated, review)
+}
+
+// ensureBootstrapPolicy creates the default cluster roles and bindings the
+// first time RBAC is turned on. Existing objects are left alone so they
+// can be customised.
+func ensureBootstrapPolicy() {
+	readVerbs := []string{"get", "list", "watch"}
+	writeVerbs := []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+	workloads := []string{"pods", "pods/status", "services", "replicasets", "deployments"}
+
+	roles := []models.ClusterRole{
+		{
+			Metadata: models.Metadata{Name: "cluster-admin"},
+			Rules: []models.PolicyRule{
+				{Verbs: []string{"*"}, Resources: []string{"*"}},
+				{Verbs: []string{"*"}, NonResourceURLs: []string{"*"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "admin"},
+			Rules: []models.PolicyRule{
+				{Verbs: writeVerbs, Resources: workloads},
+				{Verbs: writeVerbs, Resources: []string{"roles", "rolebindings"}},
+				{Verbs: readVerbs, Resources: []string{"events", "namespaces"}},
+				{Verbs: []string{"get"}, Resources: []string{"pods/log"}},
+				{Verbs: []string{"create"}, Resources: []string{"pods/exec", "pods/portforward"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "edit"},
+			Rules: []models.PolicyRule{
+				{Verbs: writeVerbs, Resources: workloads},
+				{Verbs: readVerbs, Resources: []string{"events", "namespaces"}},
+				{Verbs: []string{"get"}, Resources: []string{"pods/log"}},
+				{Verbs: []string{"create"}, Resources: []string{"pods/exec", "pods/portforward"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "view"},
+			Rules: []models.PolicyRule{
+				{Verbs: readVerbs, Resources: workloads},
+				{Verbs: readVerbs, Resources: []string{"events", "namespaces"}},
+				{Verbs: []string{"get"}, Resources: []string{"pods/log"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:basic-user"},
+			Rules: []models.PolicyRule{
+				{Verbs: []string{"create"}, Resources: []string{"selfsubjectaccessreviews"}},
+				{Verbs: []string{"get"}, NonResourceURLs: []string{"/api/v1/whoami"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:discovery"},
+			Rules: []models.PolicyRule{
+				{Verbs: []string{"get"}, NonResourceURLs: []string{"/api", "/api/*", "/apis", "/apis/*", "/openapi/*"}},
+			},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:public-info-viewer"},
+			Rules: []models.PolicyRule{
+				{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz", "/healthz/*", "/livez", "/livez/*", "/readyz", "/readyz/*"}},
+			},
+		},
+	}
+	for _, role := range roles {
+		if _, found, err := store.GetClusterRole(role.Metadata.Name); err != nil || found {
+			continue
+		}
+		role.APIVersion = rbacGroup + "/v1"
+		role.Kind = "ClusterRole"
+		if _, err := store.SaveClusterRole(role); err != nil {
+			fmt.Printf("❌ Failed to create ClusterRole '%s': %v\n", role.Metadata.Name, err)
+			continue
+		}
+		fmt.Printf("🔐 Created default ClusterRole '%s'\n", role.Metadata.Name)
+	}
+
+	bindings := []models.ClusterRoleBinding{
+		{
+			Metadata: models.Metadata{Name: "cluster-admin"},
+			Subjects: []models.Subject{{Kind: models.SubjectGroup, Name: auth.MastersGroup}},
+			RoleRef:  models.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:basic-user"},
+			Subjects: []models.Subject{
+				{Kind: models.SubjectGroup, Name: auth.AuthenticatedGroup},
+				{Kind: models.SubjectGroup, Name: auth.UnauthenticatedGroup},
+			},
+			RoleRef: models.RoleRef{Kind: "ClusterRole", Name: "system:basic-user"},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:discovery"},
+			Subjects: []models.Subject{
+				{Kind: models.SubjectGroup, Name: auth.AuthenticatedGroup},
+				{Kind: models.SubjectGroup, Name: auth.UnauthenticatedGroup},
+			},
+			RoleRef: models.RoleRef{Kind: "ClusterRole", Name: "system:discovery"},
+		},
+		{
+			Metadata: models.Metadata{Name: "system:public-info-viewer"},
+			Subjects: []models.Subject{
+				{Kind: models.SubjectGroup, Name: auth.AuthenticatedGroup},
+				{Kind: models.SubjectGroup, Name: auth.UnauthenticatedGroup},
+			},
+			RoleRef: models.RoleRef{Kind: "ClusterRole", Name: "system:public-info-viewer"},
+		},
+	}
+	for _, binding := range bindings {
+		if _, found, err := store.GetClusterRoleBinding(binding.Metadata.Name); err != nil || found {
+			continue
+		}
+		binding.APIVersion = rbacGroup + "/v1"
+		binding.Kind = "ClusterRoleBinding"
+		if _, err := store.SaveClusterRoleBinding(binding); err != nil {
+			fmt.Printf("❌ Failed to create ClusterRoleBinding '%s': %v\n", binding.Metadata.Name, err)
+			continue
+		}
+		fmt.Printf("🔐 Created default ClusterRoleBinding '%s'\n", binding.Metadata.Name)
+	}
+}
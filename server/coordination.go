@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// coordinationResources are the leases components elect their leader with.
+// Writes carry the resourceVersion they read, so two replicas cannot both
+// take a lease.
+var coordinationResources = []objectResource{
+	{
+		group: coordinationGroup, name: "leases", kind: "Lease", namespaced: true,
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var lease models.Lease
+			err := json.NewDecoder(body).Decode(&lease)
+			return &lease, &lease.Metadata, err
+		},
+		validate: func(obj interface{}) error {
+			lease := obj.(*models.Lease)
+			lease.APIVersion, lease.Kind = coordinationGroup+"/v1", "Lease"
+			return validateLease(lease.Spec)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveLease(*obj.(*models.Lease)) },
+		get:  func(ns, name string) (interface{}, bool, error) { return store.GetLease(ns, name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, coordinationGroup+"/v1", "LeaseList", metadataFields,
+				func(o models.Lease) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.Lease) bool) (store.ListResult[models.Lease], error) {
+					return store.ListLeasesPage(mux.Vars(r)["namespace"], opts, filter)
+				})
+		},
+		remove: store.DeleteLease,
+	},
+}
+
+func validateLease(spec models.LeaseSpec) error {
+	if spec.LeaseDurationSeconds < 0 {
+		return fmt.Errorf("spec.leaseDurationSeconds must not be negative")
+	}
+	for field, value := range map[string]string{"acquireTime": spec.AcquireTime, "renewTime": spec.RenewTime} {
+		if _, err := time.Parse(time.RFC3339Nano, value); value != "" && err != nil {
+			return fmt.Errorf("spec.%s must be an RFC 3339 time", field)
+		}
+	}
+	if spec.LeaseTransitions < 0 {
+		return fmt.Errorf("spec.leaseTransitions must not be negative")
+	}
+	return nil
+}
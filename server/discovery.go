@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// Discovery documents let clients learn which groups, versions and
+// resources the server offers:
+//
+//	/api                       core group versions
+//	/api/v1                    core resources
+//	/apis                      named groups
+//	/apis/{group}              one named group
+//	/apis/{group}/{version}    resources of a named group
+func (s *APIServer) setupDiscoveryRoutes() {
+	s.router.HandleFunc("/api", s.handleAPIVersions).Methods("GET")
+	s.router.HandleFunc("/api/v1", s.handleAPIResources).Methods("GET")
+	s.router.HandleFunc("/apis", s.handleAPIGroupList).Methods("GET")
+	s.router.HandleFunc("/apis/{group}", s.handleAPIGroup).Methods("GET")
+	s.router.HandleFunc("/apis/{group}/{version}", s.handleAPIResources).Methods("GET")
+	s.router.HandleFunc("/openapi/v3", s.handleOpenAPI).Methods("GET")
+}
+
+// apiGroups returns the named groups in the order their resources are
+// listed.
+func apiGroups() []string {
+	var groups []string
+	for _, r := range apiResources {
+		if r.Group != "" && !contains(groups, r.Group) {
+			groups = append(groups, r.Group)
+		}
+	}
+	return groups
+}
+
+func apiGroup(name string) models.APIGroup {
+	version := models.GroupVersionForDiscovery{GroupVersion: name + "/v1", Version: "v1"}
+	return models.APIGroup{
+		Name:             name,
+		Versions:         []models.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+// apiResourceList lists the resources of a group, or nil for an unknown
+// group.
+func apiResourceList(group string) *models.APIResourceList {
+	list := &models.APIResourceList{Kind: "APIResourceList", APIVersion: "v1", GroupVersion: "v1"}
+	if group != "" {
+		list.GroupVersion = group + "/v1"
+	}
+	for _, r := range apiResources {
+		if r.Group != group {
+			continue
+		}
+		list.Resources = append(list.Resources, models.APIResource{
+			Name:         r.Name,
+			SingularName: r.Singular,
+			Namespaced:   r.Namespaced,
+			Kind:         r.Kind,
+			Verbs:        r.Verbs,
+			ShortNames:   r.ShortNames,
+		})
+	}
+	if list.Resources == nil {
+		return nil
+	}
+	return list
+}
+
+func (s *APIServer) handleAPIVersions(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.APIVersions{Kind: "APIVersions", Versions: []string{"v1"}})
+}
+
+func (s *APIServer) handleAPIGroupList(w http.ResponseWriter, r *http.Request) {
+	list := models.APIGroupList{Kind: "APIGroupList", APIVersion: "v1", Groups: []models.APIGroup{}}
+	for _, group := range apiGroups() {
+		list.Groups = append(list.Groups, apiGroup(group))
+	}
+	respondJSON(w, http.StatusOK, list)
+}
+
+func (s *APIServer) handleAPIGroup(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["group"]
+	if !contains(apiGroups(), name) {
+		respondError(w, http.StatusNotFound, "group "+name+" not found")
+		return
+	}
+	group := apiGroup(name)
+	group.Kind, group.APIVersion = "APIGroup", "v1"
+	respondJSON(w, http.StatusOK, group)
+}
+
+// handleAPIResources serves /api/v1 and /apis/{group}/{version}.
+func (s *APIServer) handleAPIResources(w http.ResponseWriter, r *http.Request) {
+	group, version := mux.Vars(r)["group"], mux.Vars(r)["version"]
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		version = "v1"
+	}
+	list := apiResourceList(group)
+	if list == nil || version != "v1" {
+		respondError(w, http.StatusNotFound, "group version "+strings.TrimPrefix(group+"/"+version, "/")+" not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Write requests with ?dryRun=All are decoded, defaulted and validated as
+// usual and answered with the object that would have been stored, but
+// nothing is persisted and nothing else, such as the service proxy, is
+// changed. Deletes only check that the object exists.
+
+// isDryRun reports whether the request is a dry run.
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dryRun") == "All"
+}
+
+// checkDryRun rejects dryRun values other than All, so that a misspelt dry
+// run is not carried out for real.
+func checkDryRun(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if value := r.URL.Query().Get("dryRun"); value != "" && value != "All" {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("invalid dryRun %q, the only supported value is All", value))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// respondDryRunDelete answers a dry-run delete of an object that exists
+// when found is set.
+func respondDryRunDelete(w http.ResponseWriter, kind string, found bool) {
+	if !found {
+		respondError(w, http.StatusNotFound, kind+" not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": kind + " would be deleted (dry run)"})
+}
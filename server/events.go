@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+var eventSelectableFields = []string{
+	"metadata.name", "metadata.namespace",
+	"involvedObject.kind", "involvedObject.namespace", "involvedObject.name", "involvedObject.uid",
+	"reason", "type", "source",
+}
+
+func eventAttrs(event models.Event) (map[string]string, map[string]string) {
+	return event.Metadata.Labels, map[string]string{
+		"metadata.name":            event.Metadata.Name,
+		"metadata.namespace":       event.Metadata.Namespace,
+		"involvedObject.kind":      event.InvolvedObject.Kind,
+		"involvedObject.namespace": event.InvolvedObject.Namespace,
+		"involvedObject.name":      event.InvolvedObject.Name,
+		"involvedObject.uid":       event.InvolvedObject.UID,
+		"reason":                   event.Reason,
+		"type":                     event.Type,
+		"source":                   event.Source.Component,
+	}
+}
+
+func (s *APIServer) setupEventRoutes() {
+	// Cluster-wide list across all namespaces
+	s.router.HandleFunc("/api/v1/events", s.handleListEvents).Methods("GET")
+
+	collection := "/api/v1/namespaces/{namespace}/events"
+	s.router.HandleFunc(collection, s.handleListEvents).Methods("GET")
+	s.router.HandleFunc(collection, s.handleCreateEvent).Methods("POST")
+	s.router.HandleFunc(collection+"/{name}", s.handleGetEvent).Methods("GET")
+	s.router.HandleFunc(collection+"/{name}", s.handleUpdateEvent).Methods("PUT")
+	s.router.HandleFunc(collection+"/{name}", s.handlePatchEvent).Methods("PATCH")
+	s.router.HandleFunc(collection+"/{name}", s.handleDeleteEvent).Methods("DELETE")
+}
+
+func (s *APIServer) handleListEvents(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	serveList(w, r, "v1", "EventList", eventSelectableFields, eventAttrs,
+		func(opts store.ListOptions, filter func(models.Event) bool) (store.ListResult[models.Event], error) {
+			return store.ListEventsPage(namespace, opts, filter)
+		})
+}
+
+// prepareEvent fills in the defaults of a new or updated event and checks
+// it.
+func prepareEvent(event *models.Event) error {
+	event.APIVersion, event.Kind = "v1", "Event"
+	if event.InvolvedObject.Kind == "" || event.InvolvedObject.Name == "" {
+		return fmt.Errorf("involvedObject.kind and involvedObject.name are required")
+	}
+	if event.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if event.Type == "" {
+		event.Type = models.EventTypeNormal
+	}
+	if event.Type != models.EventTypeNormal && event.Type != models.EventTypeWarning {
+		return fmt.Errorf("type must be %s or %s", models.EventTypeNormal, models.EventTypeWarning)
+	}
+	if event.Count < 1 {
+		event.Count = 1
+	}
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = time.Now()
+	}
+	if event.FirstTimestamp.IsZero() {
+		event.FirstTimestamp = event.LastTimestamp
+	}
+	if event.Metadata.UID == "" {
+		event.Metadata.UID = uuid.New().String()
+	}
+	return nil
+}
+
+func (s *APIServer) handleCreateEvent(w http.ResponseWriter, r *http.Request) {
+	var event models.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := matchNamespace(r, &event.Metadata.Namespace); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := prepareEvent(&event); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if event.Metadata.Name == "" {
+		// Unique per occurrence, like <object>.<hex nanoseconds>
+		event.Metadata.Name = event.InvolvedObject.Name + "." + strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	if _, found, err := store.GetEvent(event.Metadata.Namespace, event.Metadata.Name); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if found {
+		respondError(w, http.StatusConflict, fmt.Sprintf("Event '%s' already exists", event.Metadata.Name))
+		return
+	}
+
+	if isDryRun(r) {
+		respondJSON(w, http.StatusCreated, event)
+		return
+	}
+	saved, err := store.SaveEvent(event)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, saved)
+}
+
+func (s *APIServer) handleGetEvent(w http.ResponseWriter, r *http.Request) {
+	event, found, err := store.GetEvent(requestNamespace(r), mux.Vars(r)["name"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, "Event not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, event)
+}
+
+func (s *APIServer) handleUpdateEvent(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestNamespace(r), mux.Vars(r)["name"]
+
+	var event models.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := matchNamespace(r, &event.Metadata.Namespace); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if event.Metadata.Name != name {
+		respondError(w, http.StatusBadRequest, "Event name mismatch")
+		return
+	}
+
+	// Updates only count repeats of an event that is still stored
+	current, found, err := store.GetEvent(namespace, name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !found {
+		respondError(w, http.StatusNotFound, "Event not found")
+		return
+	}
+	if err := prepareEvent(&event); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := recordUpdate(r, current, &event, nil); err != nil {
+		respondPatchError(w, err)
+		return
+	}
+
+	if !isDryRun(r) {
+		if event, err = store.SaveEvent(event); err != nil {
+			respondSaveError(w, err)
+			return
+		}
+	}
+	respondJSON(w, http.StatusOK, event)
+}
+
+func (s *APIServer) handlePatchEvent(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestNamespace(r), mux.Vars(r)["name"]
+	servePatch(w, r, "Event", nil,
+		func() (models.Event, bool, error) { return store.GetEvent(namespace, name) },
+		func(event *models.Event) error {
+			if event.Metadata.Namespace == "" {
+				event.Metadata.Namespace = namespace
+			}
+			if err := immutableName(name, namespace, event.Metadata.Name, event.Metadata.Namespace); err != nil {
+				return err
+			}
+			return prepareEvent(event)
+		},
+		store.SaveEvent)
+}
+
+func (s *APIServer) handleDeleteEvent(w http.ResponseWriter, r *http.Request) {
+	if isDryRun(r) {
+		_, found, err := store.GetEvent(requestNamespace(r), mux.Vars(r)["name"])
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondDryRunDelete(w, "Event", found)
+		return
+	}
+	deleted, err := store.DeleteEvent(requestNamespace(r), mux.Vars(r)["name"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "Event not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Event deleted successfully"})
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/healthz"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// storageTimeout bounds the Redis round trip of the storage check, so a
+// hung connection fails the probe instead of hanging it.
+const storageTimeout = 2 * time.Second
+
+var storageCheck = healthz.NamedCheck("storage", func(r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), storageTimeout)
+	defer cancel()
+	return store.Ping(ctx)
+})
+
+// setupHealthRoutes serves the health endpoints:
+//
+//	/livez     the process is serving requests; restart it if not
+//	/readyz    storage answers, startup work is done and the server is not
+//	           shutting down; send traffic
+//	/healthz   the /readyz checks except shutdown, kept for older clients
+//
+// Each check can also be fetched alone, e.g. /readyz/storage. The API
+// server does not elect a leader; the scheduler does and serves its own
+// leaderElection check.
+func (s *APIServer) setupHealthRoutes() {
+	healthy := []healthz.Checker{healthz.Ping, storageCheck, s.servicesSynced}
+	if s.rbacEnabled() {
+		healthy = append(healthy, s.rbacBootstrapped)
+	}
+	shutdown := healthz.NamedCheck("shutdown", func(*http.Request) error {
+		if s.shuttingDown.Load() {
+			return fmt.Errorf("server is shutting down")
+		}
+		return nil
+	})
+	ready := append(append([]healthz.Checker{}, healthy...), shutdown)
+
+	s.router.PathPrefix("/livez").Handler(healthz.Handler("livez", healthz.Ping)).Methods("GET")
+	s.router.PathPrefix("/readyz").Handler(healthz.Handler("readyz", ready...)).Methods("GET")
+	s.router.PathPrefix("/healthz").Handler(healthz.Handler("healthz", healthy...)).Methods("GET")
+}
+
+// isHealthPath reports whether a request is for one of the health
+// endpoints, which answer anonymous callers such as container probes.
+func isHealthPath(path string) bool {
+	for _, endpoint := range []string{"/healthz", "/livez", "/readyz"} {
+		if path == endpoint || strings.HasPrefix(path, endpoint+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// syncServices registers every stored service with the proxy, so NodePorts
+// of services created before a restart are served again. servicesSynced
+// passes once this is done. It retries until it succeeds or ctx is
+// cancelled, and reports which.
+func (s *APIServer) syncServices(ctx context.Context) bool {
+	for {
+		services, err := store.ListServicesPage("", store.ListOptions{}, nil)
+		if err == nil {
+			for i := range services.Items {
+				s.registerServiceWithProxy(&services.Items[i])
+			}
+			log.Printf("✅ Synced %d services into the proxy", len(services.Items))
+			s.servicesSynced.Set()
+			return true
+		}
+		log.Printf("⚠️ Failed to list services for the proxy, retrying: %v", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// parseListOptions reads the limit and continue query parameters.
+func parseListOptions(r *http.Request) (store.ListOptions, error) {
+	var opts store.ListOptions
+	query := r.URL.Query()
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("limit must be a non-negative integer")
+		}
+		opts.Limit = n
+	}
+	opts.Continue = query.Get("continue")
+	return opts, nil
+}
+
+// serveList answers a list request: it parses the selectors and paging
+// options, fetches one page with list and writes it as a List envelope.
+// fields are the field selectors the resource supports and attrs returns an
+// item's labels and fields.
+func serveList[T any](w http.ResponseWriter, r *http.Request, apiVersion, kind string, fields []string,
+	attrs func(T) (map[string]string, map[string]string),
+	list func(store.ListOptions, func(T) bool) (store.ListResult[T], error)) {
+
+	sel, err := parseListSelector(r, fields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts, err := parseListOptions(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var filter func(T) bool
+	if !sel.labels.Empty() || !sel.fields.Empty() {
+		filter = func(item T) bool { return sel.matches(attrs(item)) }
+	}
+
+	result, err := list(opts, filter)
+	if errors.Is(err, store.ErrInvalidContinue) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if errors.Is(err, store.ErrContinueExpired) {
+		respondError(w, http.StatusGone, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.List[T]{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Metadata: models.ListMeta{
+			ResourceVersion:    result.ResourceVersion,
+			Continue:           result.Continue,
+			RemainingItemCount: result.RemainingItemCount,
+		},
+		Items: result.Items,
+	})
+}
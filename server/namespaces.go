@@ -0,0 +1,35 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// namespaceResource serves namespaces. Objects may be created in a
+// namespace whether or not it exists, and deleting one leaves its objects.
+var namespaceResource = objectResource{
+	name: "namespaces", kind: "Namespace",
+	decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+		var ns models.Namespace
+		err := json.NewDecoder(body).Decode(&ns)
+		return &ns, &ns.Metadata, err
+	},
+	validate: func(obj interface{}) error {
+		ns := obj.(*models.Namespace)
+		ns.APIVersion, ns.Kind = "v1", "Namespace"
+		ns.Status.Phase = "Active"
+		return nil
+	},
+	save: func(obj interface{}) (interface{}, error) { return store.SaveNamespace(*obj.(*models.Namespace)) },
+	get:  func(_, name string) (interface{}, bool, error) { return store.GetNamespace(name) },
+	list: func(w http.ResponseWriter, r *http.Request) {
+		serveList(w, r, "v1", "NamespaceList", metadataFields,
+			func(o models.Namespace) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+			store.ListNamespacesPage)
+	},
+	remove: func(_, name string) (bool, error) { return store.DeleteNamespace(name) },
+}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os/exec"
 	"strings"
@@ -10,74 +13,123 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/selimhanmrl/Own-Kubernetes/agent"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/config"
+	"github.com/selimhanmrl/Own-Kubernetes/healthz"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
+	"github.com/selimhanmrl/Own-Kubernetes/wsstream"
 )
 
 type NodeServer struct {
-	router *mux.Router
-	name   string
-	port   string
-	nodeIP string // Add nodeIP field
-	agent  *agent.NodeAgent
+	router  *mux.Router
+	name    string
+	nodeIP  string // Add nodeIP field
+	serving component.ServingOptions
+	agent   *agent.NodeAgent
+
+	containerCheckInterval time.Duration
+
+	tlsCertFile  string
+	tlsKeyFile   string
+	clientCAFile string
+
+	allowedClientNames  []string
+	allowedClientGroups []string
 }
 
-func NewNodeServer(name, port, nodeIP, apiHost, apiPort string) *NodeServer {
-	return &NodeServer{
-		router: mux.NewRouter(),
-		name:   name,
-		port:   port,
-		nodeIP: nodeIP,
-		agent:  agent.NewNodeAgent(name, nodeIP, apiHost, apiPort),
+// NewNodeServer creates the server for the node cfg describes. It listens
+// on the node IP unless cfg names another bind address, and serves https
+// when cfg has a certificate; the files are reloaded when they change.
+func NewNodeServer(cfg *config.NodeAgentConfiguration) (*NodeServer, error) {
+	serving := cfg.Serving
+	if serving.BindAddress == "" {
+		serving.BindAddress = cfg.NodeIP
+	}
+	intervals := agent.Intervals{Heartbeat: cfg.HeartbeatInterval, PodSync: cfg.PodSyncInterval}
+	nodeAgent, err := agent.NewNodeAgent(cfg.NodeName, cfg.NodeIP, cfg.ClientConnection.ClientConfig(), intervals)
+	if err != nil {
+		return nil, err
 	}
+	return &NodeServer{
+		router:  mux.NewRouter(),
+		name:    cfg.NodeName,
+		nodeIP:  cfg.NodeIP,
+		serving: serving,
+		agent:   nodeAgent,
+
+		containerCheckInterval: cfg.ContainerCheckInterval,
+
+		tlsCertFile:  cfg.TLS.CertFile,
+		tlsKeyFile:   cfg.TLS.KeyFile,
+		clientCAFile: cfg.TLS.ClientCAFile,
+
+		allowedClientNames:  cfg.TLS.AllowedClientNames,
+		allowedClientGroups: cfg.TLS.AllowedClientGroups,
+	}, nil
 }
 
-func (s *NodeServer) Start() error {
+// Run registers the node and serves its API until ctx is cancelled or the
+// process receives SIGTERM, then drains in-flight requests and stops the
+// agent and pod watcher.
+func (s *NodeServer) Run(ctx context.Context) error {
 	// Validate connection to API server first
-	apiAddr := fmt.Sprintf("http://%s:%s", s.agent.GetClient().GetConfig().Host, s.agent.GetClient().GetConfig().Port)
+	apiAddr := fmt.Sprintf("%s:%s", s.agent.GetClient().GetConfig().Host, s.agent.GetClient().GetConfig().Port)
 	fmt.Printf("📡 Checking API server connection at %s...\n", apiAddr)
 
-	// Try to connect to API server with timeout
-	client := http.Client{
-		Timeout: 5 * time.Second,
-	}
-	_, err := client.Get(fmt.Sprintf("%s/healthz", apiAddr))
-	if err != nil {
-		return fmt.Errorf("❌ cannot connect to API server at %s: %v\n"+
-			apiAddr, err)
+	if err := s.agent.GetClient().Ping(5 * time.Second); err != nil {
+		return fmt.Errorf("❌ cannot connect to API server at %s: %v", apiAddr, err)
 	}
 
 	fmt.Printf("✅ Successfully connected to API server\n")
 
-	// Start the node agent
-	if err := s.agent.Start(); err != nil {
-		return fmt.Errorf("failed to start node agent: %v", err)
-	}
-
 	s.setupRoutes()
 
-	// Start pod watcher in background
-	go s.watchForPods()
+	runner := component.NewRunner("node server "+s.name, s.serving.ShutdownTimeout)
+	runner.Go("node agent", s.agent.Run)
+	runner.Go("pod watcher", func(ctx context.Context) error {
+		s.watchForPods(ctx)
+		return nil
+	})
 
-	// Bind to specific IP and port
-	addr := fmt.Sprintf("%s:%s", s.nodeIP, s.port)
-	fmt.Printf("🚀 Starting node server %s on %s\n", s.name, addr)
-	return http.ListenAndServe(addr, s.router)
+	httpServer := &http.Server{
+		Addr:    s.serving.Address(),
+		Handler: s.router,
+	}
+	if s.tlsCertFile == "" {
+		fmt.Printf("🚀 Starting node server %s on %s\n", s.name, httpServer.Addr)
+		runner.Serve(httpServer, false)
+		return runner.Run(ctx)
+	}
+
+	tlsConfig, err := pki.ServerConfig(s.tlsCertFile, s.tlsKeyFile, s.clientCAFile, true)
+	if err != nil {
+		return fmt.Errorf("failed to set up TLS: %v", err)
+	}
+	httpServer.TLSConfig = tlsConfig
+	fmt.Printf("🚀 Starting node server %s on %s (TLS)\n", s.name, httpServer.Addr)
+	runner.Serve(httpServer, true)
+	return runner.Run(ctx)
 }
 
 func (s *NodeServer) setupRoutes() {
-	s.router.HandleFunc("/healthz", s.handleHealth).Methods("GET")
+	apiServer := healthz.NamedCheck("apiserver", func(*http.Request) error {
+		return s.agent.GetClient().Ping(2 * time.Second)
+	})
+	s.router.PathPrefix("/livez").Handler(healthz.Handler("livez", healthz.Ping)).Methods("GET")
+	s.router.PathPrefix("/readyz").Handler(healthz.Handler("readyz", healthz.Ping, apiServer)).Methods("GET")
+	s.router.PathPrefix("/healthz").Handler(healthz.Handler("healthz", healthz.Ping, apiServer)).Methods("GET")
 	s.router.HandleFunc("/pods", s.handleListPods).Methods("GET")
 	s.router.HandleFunc("/pods", s.handleCreatePod).Methods("POST")
 	s.router.HandleFunc("/pods/{name}", s.handleDeletePod).Methods("DELETE")
 	s.router.HandleFunc("/pods/{name}/status", s.handleUpdatePodStatus).Methods("PUT")
+	s.router.HandleFunc("/containerLogs/{namespace}/{name}/{container}", s.requireAllowedClient(s.handleContainerLogs)).Methods("GET")
+	s.router.HandleFunc("/exec/{namespace}/{name}/{container}", s.requireAllowedClient(s.handleExec)).Methods("GET")
+	s.router.HandleFunc("/portForward/{namespace}/{name}", s.requireAllowedClient(s.handlePortForward)).Methods("GET")
 	s.router.HandleFunc("/metrics", s.handleMetrics).Methods("GET")
 }
 
-func (s *NodeServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "Node %s is healthy", s.name)
-}
-
 func (s *NodeServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	// TODO: Add node metrics collection
 	w.WriteHeader(http.StatusOK)
@@ -143,17 +195,166 @@ func (s *NodeServer) handleUpdatePodStatus(w http.ResponseWriter, r *http.Reques
 	respondJSON(w, http.StatusOK, status)
 }
 
-func (s *NodeServer) watchForPods() {
+// handleContainerLogs streams a container's log for the API server's
+// pods/log subresource. A followed log ends when the node server stops.
+func (s *NodeServer) handleContainerLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	opts, err := models.ParsePodLogOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Container = vars["container"]
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	out := &flushWriter{w: w}
+	ctx, cancel := component.LongRunning(r)
+	defer cancel()
+	err = s.agent.ContainerLogs(ctx, vars["namespace"], vars["name"], opts, out)
+	switch {
+	case err == nil:
+	case out.written:
+		fmt.Printf("⚠️ Log stream of pod %s ended: %v\n", vars["name"], err)
+	default:
+		respondAgentError(w, err)
+	}
+}
+
+// handleExec runs a command in a pod's container over an exec session.
+func (s *NodeServer) handleExec(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	opts, err := models.ParsePodExecOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Checked before upgrading, while errors can still be answered
+	name, err := s.agent.RunningContainer(vars["namespace"], vars["name"], vars["container"])
+	if err != nil {
+		respondAgentError(w, err)
+		return
+	}
+
+	conn, err := wsstream.Upgrade(w, r, wsstream.ExecProtocol)
+	if err != nil {
+		fmt.Printf("⚠️ Exec in pod %s: %v\n", vars["name"], err)
+		return
+	}
+	// Stopping the node server ends the command and the session
+	ctx, cancel := component.LongRunning(r)
+	defer cancel()
+	wsstream.ServeExec(ctx, conn, opts.Stdin, opts.TTY, func(ctx context.Context, streams wsstream.ExecStreams) error {
+		if !opts.Stdout {
+			streams.Stdout = io.Discard
+		}
+		if !opts.Stderr {
+			streams.Stderr = io.Discard
+		}
+		return s.agent.ExecInContainer(ctx, name, opts.Command, opts.TTY, streams)
+	})
+}
+
+// handlePortForward relays one connection to a pod's port over a port
+// forwarding session.
+func (s *NodeServer) handlePortForward(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	opts, err := models.ParsePodPortForwardOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Connected before upgrading, while errors can still be answered
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	stream, err := s.agent.DialPod(ctx, vars["namespace"], vars["name"], opts.Port)
+	if err != nil {
+		respondAgentError(w, err)
+		return
+	}
+
+	conn, err := wsstream.Upgrade(w, r, wsstream.PortForwardProtocol)
+	if err != nil {
+		stream.Close()
+		fmt.Printf("⚠️ Port forward to pod %s: %v\n", vars["name"], err)
+		return
+	}
+	// Stopping the node server closes the session, which ends the tunnel
+	streamCtx, stop := component.LongRunning(r)
+	defer stop()
+	go func() {
+		<-streamCtx.Done()
+		conn.Close()
+	}()
+	if err := wsstream.Tunnel(conn, stream); err != nil {
+		fmt.Printf("⚠️ Port forward to port %d of pod %s ended: %v\n", opts.Port, vars["name"], err)
+	}
+}
+
+// requireAllowedClient refuses requests without a client certificate the
+// node server verified for one of the allowed names or groups, by default
+// only the API server's. Logs, exec and port forwarding reach into pods, so
+// they are only served when the node requires client certificates
+// (tls.clientCAFile), and only to callers that authorized the request.
+func (s *NodeServer) requireAllowedClient(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			respondError(w, http.StatusForbidden, "this node server only serves pod logs, exec and port forwarding to callers with a verified client certificate; start it with tls.clientCAFile")
+			return
+		}
+		user, _, _ := auth.NewX509Authenticator().AuthenticateRequest(r)
+		if user == nil || !s.allowedClient(user) {
+			respondError(w, http.StatusForbidden, "this node server only serves pod logs, exec and port forwarding to the API server")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// allowedClient reports whether user is one of the allowed client names or
+// in one of the allowed groups.
+func (s *NodeServer) allowedClient(user *auth.UserInfo) bool {
+	for _, name := range s.allowedClientNames {
+		if user.Name == name {
+			return true
+		}
+	}
+	for _, group := range s.allowedClientGroups {
+		if user.InGroup(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondAgentError answers a request the node agent could not serve.
+func respondAgentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, agent.ErrNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, agent.ErrInvalid):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (s *NodeServer) watchForPods(ctx context.Context) {
 	fmt.Printf("👀 Starting pod watcher for node %s\n", s.name)
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(s.containerCheckInterval)
+	defer ticker.Stop()
 	previousPods := make(map[string]bool)
+	recorder := s.agent.Recorder()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 		currentPods := make(map[string]bool)
 
 		// Get assigned pods from API server
-		client := s.agent.GetClient()
-		pods, err := client.ListPods(fmt.Sprintf("?fieldSelector=spec.nodeName=%s", s.name))
+		pods, err := s.agent.ListPods()
 		if err != nil {
 			//fmt.Printf("❌ Failed to list pods: %v\n", err)
 			continue
@@ -179,7 +380,7 @@ func (s *NodeServer) watchForPods() {
 						pod.Metadata.Name, err)
 					// Update pod status to Failed
 					pod.Status.Phase = "Failed"
-					if err := client.UpdatePodStatus(&pod); err != nil {
+					if err := s.agent.GetClient().UpdatePodStatus(&pod); err != nil {
 						fmt.Printf("❌ Failed to update pod status: %v\n", err)
 					}
 					continue
@@ -193,8 +394,10 @@ func (s *NodeServer) watchForPods() {
 				if err != nil || strings.TrimSpace(string(output)) != "true" {
 					fmt.Printf("⚠️ Pod %s marked as Running but container is not running\n",
 						pod.Metadata.Name)
+					recorder.Eventf(models.PodReference(pod), models.EventTypeWarning, "ContainerDied",
+						"Container %s is no longer running", pod.Metadata.Name)
 					pod.Status.Phase = "Failed"
-					if err := client.UpdatePodStatus(&pod); err != nil {
+					if err := s.agent.GetClient().UpdatePodStatus(&pod); err != nil {
 						fmt.Printf("❌ Failed to update pod status: %v\n", err)
 					}
 				}
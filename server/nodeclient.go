@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// NodeClientOptions configure how the API server calls node servers.
+type NodeClientOptions struct {
+	Port     int // default 8081
+	CertFile string
+	KeyFile  string
+	// CAFile verifies node servers. Setting it or a client certificate
+	// switches to https.
+	CAFile string
+}
+
+// nodeClient calls node servers for the pod subresources they serve.
+type nodeClient struct {
+	port   int
+	scheme string
+	http   *http.Client
+}
+
+func newNodeClient(options NodeClientOptions) (*nodeClient, error) {
+	c := &nodeClient{port: options.Port, scheme: "http"}
+	if c.port == 0 {
+		c.port = 8081
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.CAFile != "" || options.CertFile != "" {
+		tlsConfig, err := pki.ClientConfig(options.CAFile, options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+		c.scheme = "https"
+	}
+	c.http = &http.Client{Transport: transport}
+	return c, nil
+}
+
+// url returns the URL of a node server endpoint about a pod's container,
+// such as /containerLogs/{namespace}/{name}/{container}.
+func (c *nodeClient) url(node models.Node, endpoint string, pod models.Pod, container string, query url.Values) string {
+	segments := []string{endpoint, pod.Metadata.Namespace, pod.Metadata.Name}
+	if container != "" {
+		segments = append(segments, container)
+	}
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     net.JoinHostPort(node.IP, strconv.Itoa(c.port)),
+		Path:     "/" + strings.Join(segments, "/"),
+		RawPath:  "/" + strings.Join(escaped, "/"),
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// proxy passes a request upgrading to a stream, such as an exec session,
+// on to the node server at target and relays the stream both ways until
+// either side ends it or the API server stops. The caller's credentials
+// are not passed on.
+func (c *nodeClient) proxy(w http.ResponseWriter, r *http.Request, node models.Node, target string) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL = targetURL
+			req.Host = targetURL.Host
+			req.Header.Del("Authorization")
+			req.Header.Del("Cookie")
+		},
+		Transport: c.http.Transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			respondError(w, http.StatusBadGateway, fmt.Sprintf("failed to reach node %q: %v", node.Name, err))
+		},
+	}
+	// Closes the connection to the node, and so the stream, on shutdown
+	ctx, cancel := component.LongRunning(r)
+	defer cancel()
+	proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// podNode finds the pod a subresource request names and the node running
+// it. When there is none it answers the request and returns false.
+func podNode(w http.ResponseWriter, r *http.Request) (models.Pod, models.Node, bool) {
+	name := mux.Vars(r)["name"]
+	pod, found := store.GetPod(requestNamespace(r), name)
+	if !found {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("pod %q not found", name))
+		return pod, models.Node{}, false
+	}
+	if pod.Spec.NodeName == "" {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("pod %q is not scheduled to a node yet", name))
+		return pod, models.Node{}, false
+	}
+	node, found, err := store.GetNode(pod.Spec.NodeName)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return pod, node, false
+	}
+	if !found || node.IP == "" {
+		respondError(w, http.StatusServiceUnavailable, fmt.Sprintf("node %q of pod %q cannot be reached", pod.Spec.NodeName, name))
+		return pod, node, false
+	}
+	return pod, node, true
+}
+
+// podContainer returns the container of pod called name, or the pod's
+// first container when name is empty.
+func podContainer(pod models.Pod, name string) (string, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return "", fmt.Errorf("pod %q has no containers", pod.Metadata.Name)
+	}
+	if name == "" {
+		return pod.Spec.Containers[0].Name, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("container %q is not valid for pod %q", name, pod.Metadata.Name)
+}
@@ -0,0 +1,311 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+)
+
+// objectResource wires a kind that is stored as it is, such as the RBAC
+// kinds or replica sets, to its store functions so that these kinds can
+// share handlers.
+type objectResource struct {
+	group      string // "" for the core /api/v1 group
+	name       string // URL plural, e.g. roles
+	kind       string
+	namespaced bool
+	// mergeKeys are the strategic merge patch keys of its lists; lists
+	// without one are replaced whole
+	mergeKeys patch.MergeKeys
+	decode    func(body io.Reader) (interface{}, *models.Metadata, error)
+	validate  func(obj interface{}) error
+	// confirm, if set, checks with RBAC that user may store obj, beyond
+	// being allowed the request
+	confirm func(rbac *auth.RBACAuthorizer, user *auth.UserInfo, obj interface{}) error
+	// save stores obj and returns it as stored
+	save   func(obj interface{}) (interface{}, error)
+	get    func(namespace, name string) (interface{}, bool, error)
+	list   http.HandlerFunc
+	remove func(namespace, name string) (bool, error)
+}
+
+func (s *APIServer) setupObjectRoutes(resources []objectResource) {
+	for _, res := range resources {
+		res := res
+		base := "/api/v1"
+		if res.group != "" {
+			base = "/apis/" + res.group + "/v1"
+		}
+		collection := base + "/" + res.name
+		if res.namespaced {
+			// Cluster-wide list across all namespaces
+			s.router.HandleFunc(collection, res.list).Methods("GET")
+			collection = base + "/namespaces/{namespace}/" + res.name
+		}
+		s.router.HandleFunc(collection, res.list).Methods("GET")
+		s.router.HandleFunc(collection, s.objectCreate(res)).Methods("POST")
+		s.router.HandleFunc(collection+"/{name}", s.objectGet(res)).Methods("GET")
+		s.router.HandleFunc(collection+"/{name}", s.objectUpdate(res)).Methods("PUT")
+		s.router.HandleFunc(collection+"/{name}", s.objectPatch(res)).Methods("PATCH")
+		s.router.HandleFunc(collection+"/{name}", s.objectDelete(res)).Methods("DELETE")
+	}
+}
+
+func (s *APIServer) objectGet(res objectResource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, found, err := res.get(mux.Vars(r)["namespace"], mux.Vars(r)["name"])
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !found {
+			respondError(w, http.StatusNotFound, res.kind+" not found")
+			return
+		}
+		respondJSON(w, http.StatusOK, obj)
+	}
+}
+
+// objectDecode reads and validates an object from the request body.
+func objectDecode(res objectResource, r *http.Request) (interface{}, *models.Metadata, error) {
+	obj, meta, err := res.decode(r.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid request payload")
+	}
+	if meta.Name == "" {
+		return nil, nil, fmt.Errorf("metadata.name is required")
+	}
+	if res.namespaced {
+		if err := matchNamespace(r, &meta.Namespace); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		meta.Namespace = ""
+	}
+	if err := res.validate(obj); err != nil {
+		return nil, nil, err
+	}
+	return obj, meta, nil
+}
+
+// confirmWrite runs res.confirm for the requesting user when RBAC is on.
+func (s *APIServer) confirmWrite(res objectResource, r *http.Request, obj interface{}) error {
+	if res.confirm == nil || s.rbac == nil {
+		return nil
+	}
+	user, ok := auth.UserFrom(r.Context())
+	if !ok {
+		return fmt.Errorf("no user to check %s permissions for", res.kind)
+	}
+	return res.confirm(s.rbac, user, obj)
+}
+
+// storedMetadata returns the metadata of an object returned by res.get.
+func storedMetadata(res objectResource, obj interface{}) *models.Metadata {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return &models.Metadata{}
+	}
+	_, meta, err := res.decode(bytes.NewReader(data))
+	if err != nil {
+		return &models.Metadata{}
+	}
+	return meta
+}
+
+func (s *APIServer) objectCreate(res objectResource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, meta, err := objectDecode(res, r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.confirmWrite(res, r, obj); err != nil {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if _, found, err := res.get(meta.Namespace, meta.Name); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		} else if found {
+			respondError(w, http.StatusConflict, fmt.Sprintf("%s '%s' already exists", res.kind, meta.Name))
+			return
+		}
+		meta.CreationTimestamp = time.Now().UTC().Format(time.RFC3339)
+
+		if isDryRun(r) {
+			respondJSON(w, http.StatusCreated, obj)
+			return
+		}
+		saved, err := res.save(obj)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		fmt.Printf("✅ %s '%s' created\n", res.kind, meta.Name)
+		respondJSON(w, http.StatusCreated, saved)
+	}
+}
+
+func (s *APIServer) objectUpdate(res objectResource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, meta, err := objectDecode(res, r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if meta.Name != mux.Vars(r)["name"] {
+			respondError(w, http.StatusBadRequest, res.kind+" name mismatch")
+			return
+		}
+		if err := s.confirmWrite(res, r, obj); err != nil {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+
+		current, found, err := res.get(meta.Namespace, meta.Name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		var live interface{}
+		if found {
+			stored := storedMetadata(res, current)
+			if err := checkResourceVersion(stored.ResourceVersion, meta.ResourceVersion); err != nil {
+				respondError(w, http.StatusConflict, err.Error())
+				return
+			}
+			meta.CreationTimestamp = stored.CreationTimestamp
+			live = current
+		} else {
+			meta.CreationTimestamp = time.Now().UTC().Format(time.RFC3339)
+		}
+		if err := recordUpdate(r, live, obj, res.mergeKeys); err != nil {
+			respondPatchError(w, err)
+			return
+		}
+
+		if isDryRun(r) {
+			respondJSON(w, http.StatusOK, obj)
+			return
+		}
+		saved, err := res.save(obj)
+		if err != nil {
+			respondSaveError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, saved)
+	}
+}
+
+func (s *APIServer) objectPatch(res objectResource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace, name := mux.Vars(r)["namespace"], mux.Vars(r)["name"]
+		current, found, err := res.get(namespace, name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !found && requestPatchType(r) != patch.ApplyPatch {
+			respondError(w, http.StatusNotFound, res.kind+" not found")
+			return
+		}
+
+		var original []byte
+		created := time.Now().UTC().Format(time.RFC3339)
+		if found {
+			if original, err = json.Marshal(current); err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			created = storedMetadata(res, current).CreationTimestamp
+		}
+		schema, _, _ := res.decode(strings.NewReader("{}"))
+		patched, err := patchJSON(r, res.kind, original, res.mergeKeys, schema)
+		if err != nil {
+			respondPatchError(w, err)
+			return
+		}
+
+		obj, meta, err := res.decode(bytes.NewReader(patched))
+		if err != nil {
+			respondError(w, http.StatusUnprocessableEntity, "patched object is invalid: "+err.Error())
+			return
+		}
+		if res.namespaced && namespace == "" {
+			namespace = "default"
+		}
+		if meta.Namespace == "" {
+			meta.Namespace = namespace
+		}
+		if err := immutableName(name, namespace, meta.Name, meta.Namespace); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := res.validate(obj); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := s.confirmWrite(res, r, obj); err != nil {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		meta.CreationTimestamp = created
+
+		if isDryRun(r) {
+			status := http.StatusOK
+			if !found {
+				status = http.StatusCreated
+			}
+			respondJSON(w, status, obj)
+			return
+		}
+		saved, err := res.save(obj)
+		if err != nil {
+			respondSaveError(w, err)
+			return
+		}
+		if !found {
+			fmt.Printf("✅ %s '%s' created\n", res.kind, meta.Name)
+			respondJSON(w, http.StatusCreated, saved)
+			return
+		}
+		respondJSON(w, http.StatusOK, saved)
+	}
+}
+
+func (s *APIServer) objectDelete(res objectResource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+		if isDryRun(r) {
+			_, found, err := res.get(mux.Vars(r)["namespace"], name)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			respondDryRunDelete(w, res.kind, found)
+			return
+		}
+		deleted, err := res.remove(mux.Vars(r)["namespace"], name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !deleted {
+			respondError(w, http.StatusNotFound, res.kind+" not found")
+			return
+		}
+		fmt.Printf("🗑️ %s '%s' deleted\n", res.kind, name)
+		respondJSON(w, http.StatusOK, map[string]string{"message": res.kind + " deleted successfully"})
+	}
+}
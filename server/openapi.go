@@ -0,0 +1,202 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/openapi"
+)
+
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument *openapi.Document
+)
+
+func (s *APIServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	openAPIOnce.Do(func() { openAPIDocument = buildOpenAPI() })
+	respondJSON(w, http.StatusOK, openAPIDocument)
+}
+
+// buildOpenAPI describes every resource in apiResources, plus the
+// discovery documents, as an OpenAPI v3 document.
+func buildOpenAPI() *openapi.Document {
+	doc := openapi.NewDocument("mykube", "v1")
+
+	for _, r := range apiResources {
+		gvk := &openapi.GroupVersionKind{Group: r.Group, Version: "v1", Kind: r.Kind}
+		object := doc.Define(r.Kind, r.Object, gvk)
+
+		name, subresource := r.Name, ""
+		if i := strings.Index(name, "/"); i >= 0 {
+			name, subresource = name[:i], name[i+1:]
+		}
+
+		base := "/api/v1"
+		if r.Group != "" {
+			base = "/apis/" + r.Group + "/v1"
+		}
+		params := []openapi.Parameter{}
+		if r.Namespaced {
+			base += "/namespaces/{namespace}"
+			params = append(params, pathParameter("namespace", "object name and auth scope, such as for teams and projects"))
+		}
+		collection := base + "/" + name
+		item := &openapi.PathItem{Parameters: append(params, pathParameter("name", "name of the "+r.Kind))}
+		itemPath := collection + "/{name}"
+		if subresource != "" {
+			itemPath += "/" + subresource
+		}
+
+		op := func(verb, action string) *openapi.Operation {
+			suffix := ""
+			if subresource != "" {
+				suffix = strings.ToUpper(subresource[:1]) + subresource[1:]
+			}
+			return &openapi.Operation{
+				OperationID:      verb + r.Kind + suffix,
+				Tags:             []string{r.groupVersion()},
+				Action:           action,
+				GroupVersionKind: gvk,
+				Responses:        map[string]openapi.Response{"200": jsonResponse("OK", object)},
+			}
+		}
+
+		for _, verb := range r.Verbs {
+			switch verb {
+			case "list":
+				list := doc.Define(r.Kind+"List", r.List,
+					&openapi.GroupVersionKind{Group: r.Group, Version: "v1", Kind: r.Kind + "List"})
+				o := op("list", "list")
+				o.Parameters = listParameters
+				o.Responses = map[string]openapi.Response{"200": jsonResponse("OK", list)}
+				collectionItem(doc, collection, params).Get = o
+			case "create":
+				if connectSubresources[subresource] {
+					item.Get = op("connect", "connect")
+					item.Get.Parameters = connectParameters[subresource]
+					item.Get.Responses = map[string]openapi.Response{"101": {Description: "Switching Protocols"}}
+					continue
+				}
+				o := op("create", "post")
+				o.RequestBody = jsonBody(object)
+				o.Responses = map[string]openapi.Response{
+					"200": jsonResponse("OK", object),
+					"201": jsonResponse("Created", object),
+				}
+				collectionItem(doc, collection, params).Post = o
+			case "get":
+				item.Get = op("read", "get")
+				if subresource == "log" {
+					item.Get.Parameters = logParameters
+					item.Get.Responses = map[string]openapi.Response{"200": textResponse("OK")}
+				}
+			case "update":
+				item.Put = op("replace", "put")
+				item.Put.RequestBody = jsonBody(object)
+			case "patch":
+				item.Patch = op("patch", "patch")
+				item.Patch.Parameters = patchParameters
+				item.Patch.RequestBody = patchBody
+				item.Patch.Responses["201"] = jsonResponse("Created", object)
+			case "delete":
+				item.Delete = op("delete", "delete")
+			}
+		}
+		if item.Get != nil || item.Put != nil || item.Patch != nil || item.Delete != nil {
+			doc.Paths[itemPath] = item
+		}
+	}
+
+	discovery := func(path, name string, v interface{}) {
+		doc.Paths[path] = &openapi.PathItem{Get: &openapi.Operation{
+			OperationID: "get" + name,
+			Tags:        []string{"discovery"},
+			Responses:   map[string]openapi.Response{"200": jsonResponse("OK", doc.Define(name, v, nil))},
+		}}
+	}
+	discovery("/api", "APIVersions", models.APIVersions{})
+	discovery("/apis", "APIGroupList", models.APIGroupList{})
+	discovery("/api/v1", "APIResourceList", models.APIResourceList{})
+	for _, group := range apiGroups() {
+		discovery("/apis/"+group+"/v1", "APIResourceList", models.APIResourceList{})
+	}
+	return doc
+}
+
+func collectionItem(doc *openapi.Document, path string, params []openapi.Parameter) *openapi.PathItem {
+	item, ok := doc.Paths[path]
+	if !ok {
+		item = &openapi.PathItem{Parameters: params}
+		doc.Paths[path] = item
+	}
+	return item
+}
+
+func pathParameter(name, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "path", Required: true, Description: description, Schema: &openapi.Schema{Type: "string"}}
+}
+
+func queryParameter(name, typ, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: typ}}
+}
+
+var listParameters = []openapi.Parameter{
+	queryParameter("labelSelector", "string", "Only return objects whose labels match, e.g. app=web,tier in (frontend)"),
+	queryParameter("fieldSelector", "string", "Only return objects whose fields match, e.g. status.phase=Running"),
+	queryParameter("limit", "integer", "Maximum number of items to return; metadata.continue is set when more remain"),
+	queryParameter("continue", "string", "Token from a previous page's metadata.continue"),
+}
+
+var logParameters = []openapi.Parameter{
+	queryParameter("container", "string", "Container to read; defaults to the pod's first container"),
+	queryParameter("follow", "boolean", "Keep the stream open, sending lines as they are written"),
+	queryParameter("previous", "boolean", "Read the last terminated instance of the container"),
+	queryParameter("sinceSeconds", "integer", "Only return lines written in the last seconds"),
+	queryParameter("sinceTime", "string", "Only return lines written after this RFC 3339 time"),
+	queryParameter("timestamps", "boolean", "Prefix each line with the RFC 3339 time it was written"),
+	queryParameter("tailLines", "integer", "Only return the last lines of the log"),
+}
+
+// connectParameters are the query parameters of connectSubresources.
+var connectParameters = map[string][]openapi.Parameter{
+	"exec": {
+		queryParameter("container", "string", "Container to run in; defaults to the pod's first container"),
+		queryParameter("command", "string", "Command to run, repeated once for each argument"),
+		queryParameter("stdin", "boolean", "Pass the client's input to the command"),
+		queryParameter("stdout", "boolean", "Return the command's standard output"),
+		queryParameter("stderr", "boolean", "Return the command's standard error; not with tty"),
+		queryParameter("tty", "boolean", "Run the command in a terminal"),
+	},
+	"portforward": {
+		queryParameter("port", "integer", "Port of the pod to connect to; each connection is a request of its own"),
+	},
+}
+
+var patchParameters = []openapi.Parameter{
+	queryParameter("fieldManager", "string", "Name of the manager making the change; required for apply patches"),
+	queryParameter("force", "boolean", "Take over fields owned by other managers; apply patches only"),
+}
+
+var patchBody = &openapi.RequestBody{
+	Required: true,
+	Content: map[string]openapi.MediaType{
+		"application/merge-patch+json":           {Schema: &openapi.Schema{Type: "object"}},
+		"application/json-patch+json":            {Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "object"}}},
+		"application/strategic-merge-patch+json": {Schema: &openapi.Schema{Type: "object"}},
+		"application/apply-patch+yaml":           {Schema: &openapi.Schema{Type: "object"}},
+	},
+}
+
+func jsonBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}}}
+}
+
+func textResponse(description string) openapi.Response {
+	return openapi.Response{Description: description, Content: map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}}}
+}
+
+func jsonResponse(description string, schema *openapi.Schema) openapi.Response {
+	return openapi.Response{Description: description, Content: map[string]openapi.MediaType{"application/json": {Schema: schema}}}
+}
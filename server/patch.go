@@ -0,0 +1,444 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/fieldmanager"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/patch"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+	"gopkg.in/yaml.v3"
+)
+
+// maxPatchSize bounds the patch documents the server accepts.
+const maxPatchSize = 3 << 20
+
+// Merge keys used by strategic merge patches.
+var (
+	podMergeKeys = patch.MergeKeys{
+		"spec.containers":              "name",
+		"spec.containers.ports":        "containerPort",
+		"spec.containers.volumeMounts": "mountPath",
+		"spec.volumes":                 "name",
+	}
+	serviceMergeKeys = patch.MergeKeys{
+		"spec.ports": "port",
+	}
+	nodeMergeKeys = patch.MergeKeys{
+		"status.conditions": "type",
+	}
+)
+
+// patchError carries the status code a failed patch should be answered with.
+type patchError struct {
+	status int
+	err    error
+}
+
+func (e *patchError) Error() string { return e.err.Error() }
+
+// applyPatch applies the patch in the request body to current, which is the
+// zero T when the object does not exist yet, and decodes the result into a
+// new T.
+func applyPatch[T any](r *http.Request, kind string, current T, exists bool, mergeKeys patch.MergeKeys) (T, error) {
+	var patched T
+
+	var original []byte
+	if exists {
+		var err error
+		if original, err = json.Marshal(current); err != nil {
+			return patched, &patchError{http.StatusInternalServerError, err}
+		}
+	}
+	result, err := patchJSON(r, kind, original, mergeKeys, new(T))
+	if err != nil {
+		return patched, err
+	}
+	if err := json.Unmarshal(result, &patched); err != nil {
+		return patched, &patchError{http.StatusUnprocessableEntity, fmt.Errorf("patched object is invalid: %v", err)}
+	}
+	return patched, nil
+}
+
+// requestPatchType returns the patch format named by the Content-Type.
+func requestPatchType(r *http.Request) patch.Type {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return patch.Type(contentType)
+}
+
+// patchJSON applies the patch in the request body, picking the format from
+// its Content-Type, to the JSON document original and records the change
+// in the object's managed fields. original is nil when an apply patch
+// creates the object. schema points to a zero value of the object's type
+// and is used to reject unknown fields in applied configurations.
+func patchJSON(r *http.Request, kind string, original []byte, mergeKeys patch.MergeKeys, schema interface{}) ([]byte, error) {
+	patchType := requestPatchType(r)
+	switch patchType {
+	case patch.MergePatch, patch.JSONPatch, patch.StrategicMergePatch, patch.ApplyPatch:
+	default:
+		return nil, &patchError{http.StatusUnsupportedMediaType, fmt.Errorf(
+			"unsupported patch type %q, use %s, %s, %s or %s", r.Header.Get("Content-Type"),
+			patch.MergePatch, patch.JSONPatch, patch.StrategicMergePatch, patch.ApplyPatch)}
+	}
+
+	manager, err := fieldManager(r, patchType)
+	if err != nil {
+		return nil, &patchError{http.StatusBadRequest, err}
+	}
+	force := r.URL.Query().Get("force") == "true"
+	if force && patchType != patch.ApplyPatch {
+		return nil, &patchError{http.StatusBadRequest, fmt.Errorf("force is only allowed for apply patches")}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPatchSize+1))
+	if err != nil {
+		return nil, &patchError{http.StatusBadRequest, fmt.Errorf("failed to read patch: %v", err)}
+	}
+	if len(body) > maxPatchSize {
+		return nil, &patchError{http.StatusRequestEntityTooLarge, fmt.Errorf("patch is too large")}
+	}
+
+	if patchType == patch.ApplyPatch {
+		applied, err := appliedConfiguration(body, kind, schema)
+		if err != nil {
+			return nil, &patchError{http.StatusBadRequest, err}
+		}
+		result, err := fieldmanager.Apply(original, applied, manager, force, mergeKeys, time.Now())
+		if conflicts, ok := err.(fieldmanager.ConflictError); ok {
+			return nil, &patchError{http.StatusConflict, conflicts}
+		}
+		if err != nil {
+			return nil, &patchError{http.StatusUnprocessableEntity, err}
+		}
+		if err := checkResourceVersion(resourceVersionOf(original), resourceVersionOf(result)); err != nil {
+			return nil, &patchError{http.StatusConflict, err}
+		}
+		return result, nil
+	}
+
+	result, err := patch.Apply(patchType, original, body, mergeKeys)
+	if err != nil {
+		return nil, &patchError{http.StatusUnprocessableEntity, err}
+	}
+	// A patch may carry the resourceVersion it was made against
+	if err := checkResourceVersion(resourceVersionOf(original), resourceVersionOf(result)); err != nil {
+		return nil, &patchError{http.StatusConflict, err}
+	}
+	if result, err = fieldmanager.Update(original, result, manager, mergeKeys, time.Now()); err != nil {
+		return nil, &patchError{http.StatusInternalServerError, err}
+	}
+	return result, nil
+}
+
+// fieldManager names the manager a patch is recorded for: the fieldManager
+// query parameter, required for apply patches, or else the client name
+// from the User-Agent.
+func fieldManager(r *http.Request, patchType patch.Type) (string, error) {
+	manager := r.URL.Query().Get("fieldManager")
+	if len(manager) > 128 {
+		return "", fmt.Errorf("fieldManager must be at most 128 characters")
+	}
+	if manager != "" {
+		return manager, nil
+	}
+	if patchType == patch.ApplyPatch {
+		return "", fmt.Errorf("fieldManager is required for apply patches")
+	}
+	if agent := strings.SplitN(r.UserAgent(), "/", 2)[0]; agent != "" {
+		return agent, nil
+	}
+	return "unknown", nil
+}
+
+// recordUpdate records in the managed fields of updated, a pointer to the
+// object a PUT stores, which fields the request's field manager changed
+// from current, the stored object or nil. Managed fields the client left
+// out are carried over from current; ones it sent replace them.
+func recordUpdate(r *http.Request, current, updated interface{}, mergeKeys patch.MergeKeys) error {
+	manager, err := fieldManager(r, "")
+	if err != nil {
+		return &patchError{http.StatusBadRequest, err}
+	}
+	live := []byte("{}")
+	if current != nil {
+		if live, err = json.Marshal(current); err != nil {
+			return &patchError{http.StatusInternalServerError, err}
+		}
+	}
+	sent, err := json.Marshal(updated)
+	if err != nil {
+		return &patchError{http.StatusInternalServerError, err}
+	}
+	if entries, ok := managedFieldsOf(sent); ok {
+		if live, err = withManagedFields(live, entries); err != nil {
+			return &patchError{http.StatusInternalServerError, err}
+		}
+	}
+
+	recorded, err := fieldmanager.Update(live, sent, manager, mergeKeys, time.Now())
+	if err != nil {
+		return &patchError{http.StatusBadRequest, err}
+	}
+	if err := json.Unmarshal(recorded, updated); err != nil {
+		return &patchError{http.StatusInternalServerError, err}
+	}
+	return nil
+}
+
+// managedFieldsOf returns metadata.managedFields of a JSON object, if set.
+func managedFieldsOf(obj []byte) (json.RawMessage, bool) {
+	var object struct {
+		Metadata struct {
+			ManagedFields json.RawMessage `json:"managedFields"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(obj, &object)
+	entries := object.Metadata.ManagedFields
+	return entries, len(entries) > 0 && string(entries) != "null"
+}
+
+// withManagedFields replaces metadata.managedFields of a JSON object.
+func withManagedFields(obj []byte, entries json.RawMessage) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(obj, &object); err != nil {
+		return nil, err
+	}
+	metadata := map[string]json.RawMessage{}
+	if raw, ok := object["metadata"]; ok {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, err
+		}
+	}
+	metadata["managedFields"] = entries
+	var err error
+	if object["metadata"], err = json.Marshal(metadata); err != nil {
+		return nil, err
+	}
+	return json.Marshal(object)
+}
+
+// appliedConfiguration converts an apply patch from YAML, or JSON, to JSON
+// and checks it against the object's kind and fields.
+func appliedConfiguration(body []byte, kind string, schema interface{}) ([]byte, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("invalid apply patch: %v", err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("apply patch must be an object")
+	}
+	if k, ok := config["kind"]; ok && k != kind {
+		return nil, fmt.Errorf("apply patch kind %v does not match %s", k, kind)
+	}
+	applied, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apply patch: %v", err)
+	}
+
+	// Fields the object does not have would be owned but never stored
+	delete(config, "apiVersion")
+	delete(config, "kind")
+	typed, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(typed))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(schema); err != nil {
+		return nil, fmt.Errorf("invalid apply patch: %v", err)
+	}
+	return applied, nil
+}
+
+// respondPatchError answers with the status carried by a patchError.
+func respondPatchError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if pe, ok := err.(*patchError); ok {
+		status = pe.status
+	}
+	respondError(w, status, err.Error())
+}
+
+// servePatch loads an object with get, patches it, lets prepare fill in and
+// check the result and stores it with save, which returns it as stored. An
+// apply patch for an object that does not exist creates it.
+func servePatch[T any](w http.ResponseWriter, r *http.Request, kind string, mergeKeys patch.MergeKeys,
+	get func() (T, bool, error), prepare func(*T) error, save func(T) (T, error)) {
+
+	current, found, err := get()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found && requestPatchType(r) != patch.ApplyPatch {
+		respondError(w, http.StatusNotFound, kind+" not found")
+		return
+	}
+
+	patched, err := applyPatch(r, kind, current, found, mergeKeys)
+	if err != nil {
+		respondPatchError(w, err)
+		return
+	}
+	if err := prepare(&patched); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !isDryRun(r) {
+		if patched, err = save(patched); err != nil {
+			respondSaveError(w, err)
+			return
+		}
+	}
+
+	status := http.StatusOK
+	if !found {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, patched)
+}
+
+// checkResourceVersion rejects a write that carries a resourceVersion other
+// than the stored one: the object changed since the writer read it. Writes
+// without one, and objects stored before resource versions were recorded,
+// are not checked. This answers early; the store repeats the check
+// atomically with the write.
+func checkResourceVersion(stored, sent string) error {
+	if sent == "" || stored == "" || sent == stored {
+		return nil
+	}
+	return store.ErrConflict
+}
+
+// respondSaveError answers a write the store refused, with a conflict if
+// the object changed since the request read it.
+func respondSaveError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrConflict) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	respondError(w, http.StatusInternalServerError, err.Error())
+}
+
+// resourceVersionOf reads metadata.resourceVersion from a JSON object.
+func resourceVersionOf(obj []byte) string {
+	var object struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(obj, &object)
+	return object.Metadata.ResourceVersion
+}
+
+// immutableName rejects patches that rename an object or move it to another
+// namespace.
+func immutableName(name, namespace, newName, newNamespace string) error {
+	if newName != name {
+		return fmt.Errorf("metadata.name cannot be changed")
+	}
+	if newNamespace != namespace {
+		return fmt.Errorf("metadata.namespace cannot be changed")
+	}
+	return nil
+}
+
+// defaultPod fills in what the API server sets on pods it creates.
+func defaultPod(pod *models.Pod) {
+	if pod.Metadata.UID == "" {
+		pod.Metadata.UID = uuid.New().String()
+	}
+	if pod.Status.Phase == "" {
+		pod.Status.Phase = "Pending"
+		pod.Status.StartTime = time.Now().Format(time.RFC3339)
+	}
+}
+
+func (s *APIServer) handlePatchPod(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestNamespace(r), mux.Vars(r)["name"]
+	servePatch(w, r, "Pod", podMergeKeys,
+		func() (models.Pod, bool, error) {
+			pod, found := store.GetPod(namespace, name)
+			return pod, found, nil
+		},
+		func(pod *models.Pod) error {
+			if pod.Metadata.Namespace == "" {
+				pod.Metadata.Namespace = namespace
+			}
+			defaultPod(pod)
+			return immutableName(name, namespace, pod.Metadata.Name, pod.Metadata.Namespace)
+		},
+		store.SavePod)
+}
+
+func (s *APIServer) handlePatchService(w http.ResponseWriter, r *http.Request) {
+	namespace, name := requestNamespace(r), mux.Vars(r)["name"]
+	servePatch(w, r, "Service", serviceMergeKeys,
+		func() (models.Service, bool, error) { return store.GetService(namespace, name) },
+		func(service *models.Service) error {
+			if service.Metadata.Namespace == "" {
+				service.Metadata.Namespace = namespace
+			}
+			return immutableName(name, namespace, service.Metadata.Name, service.Metadata.Namespace)
+		},
+		func(service models.Service) (models.Service, error) {
+			saved, err := store.SaveService(service)
+			if err != nil {
+				return saved, err
+			}
+			s.registerServiceWithProxy(&saved)
+			return saved, nil
+		})
+}
+
+func (s *APIServer) handlePatchNode(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	// Nodes register themselves and have no metadata to track owners in
+	if requestPatchType(r) == patch.ApplyPatch {
+		respondError(w, http.StatusUnsupportedMediaType, "apply patches are not supported for nodes")
+		return
+	}
+	servePatch(w, r, "Node", nodeMergeKeys,
+		func() (models.Node, bool, error) { return store.GetNode(name) },
+		func(node *models.Node) error { return immutableName(name, "", node.Name, "") },
+		store.SaveNode)
+}
+
+func (s *APIServer) handleGetService(w http.ResponseWriter, r *http.Request) {
+	service, found, err := store.GetService(requestNamespace(r), mux.Vars(r)["name"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, "Service not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, service)
+}
+
+func (s *APIServer) handleGetNode(w http.ResponseWriter, r *http.Request) {
+	node, found, err := store.GetNode(mux.Vars(r)["name"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, "Node not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, node)
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// handlePodExec serves pods/{name}/exec by passing the exec session on to
+// the node server running the pod.
+func (s *APIServer) handlePodExec(w http.ResponseWriter, r *http.Request) {
+	opts, err := models.ParsePodExecOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pod, node, ok := podNode(w, r)
+	if !ok {
+		return
+	}
+	container, err := podContainer(pod, opts.Container)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The container is part of the node server's path
+	opts.Container = ""
+
+	s.nodes.proxy(w, r, node, s.nodes.url(node, "exec", pod, container, opts.Query()))
+}
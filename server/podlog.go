@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// handlePodLog serves pods/{name}/log by streaming the log from the node
+// server running the pod. A followed log ends when the API server stops.
+func (s *APIServer) handlePodLog(w http.ResponseWriter, r *http.Request) {
+	opts, err := models.ParsePodLogOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pod, node, ok := podNode(w, r)
+	if !ok {
+		return
+	}
+	container, err := podContainer(pod, opts.Container)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The container is part of the node server's path
+	opts.Container = ""
+
+	ctx, cancel := component.LongRunning(r)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		s.nodes.url(node, "containerLogs", pod, container, opts.Query()), nil)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp, err := s.nodes.http.Do(req)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("failed to reach node %q: %v", node.Name, err))
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(&flushWriter{w: w}, resp.Body); err != nil && ctx.Err() == nil {
+		log.Printf("⚠️ Log stream of pod %s/%s ended: %v", pod.Metadata.Namespace, pod.Metadata.Name, err)
+	}
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// handlePodPortForward serves pods/{name}/portforward by passing the port
+// forwarding session on to the node server running the pod.
+func (s *APIServer) handlePodPortForward(w http.ResponseWriter, r *http.Request) {
+	opts, err := models.ParsePodPortForwardOptions(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pod, node, ok := podNode(w, r)
+	if !ok {
+		return
+	}
+
+	s.nodes.proxy(w, r, node, s.nodes.url(node, "portForward", pod, "", opts.Query()))
+}
@@ -1,10 +1,14 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
@@ -62,22 +66,40 @@ func (p *ProxyServer) RegisterService(service *models.Service, pods []models.Pod
 	}
 }
 
-func (p *ProxyServer) Start() error {
-	// Handle all NodePort services
+// Run listens on bindAddress for every NodePort registered so far and
+// forwards requests to the services' backends. It returns once ctx is
+// cancelled and the listeners have drained.
+func (p *ProxyServer) Run(ctx context.Context, bindAddress string) error {
+	p.mu.RLock()
+	var servers []*http.Server
 	for nodePort, serviceProxy := range p.nodePortMap {
-		go func(port int, proxy *ServiceProxy) {
-			addr := fmt.Sprintf(":%d", port)
-			fmt.Printf("🔄 Starting proxy for NodePort %d\n", port)
-
-			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy := serviceProxy
+		servers = append(servers, &http.Server{
+			Addr: net.JoinHostPort(bindAddress, strconv.Itoa(nodePort)),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				p.handleRequest(w, r, proxy)
-			})
-
-			if err := http.ListenAndServe(addr, handler); err != nil {
-				fmt.Printf("❌ Failed to start proxy on port %d: %v\n", port, err)
+			}),
+		})
+	}
+	p.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func(server *http.Server) {
+			defer wg.Done()
+			fmt.Printf("🔄 Starting proxy on %s\n", server.Addr)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fmt.Printf("❌ Failed to start proxy on %s: %v\n", server.Addr, err)
 			}
-		}(nodePort, serviceProxy)
+		}(server)
+	}
+
+	<-ctx.Done()
+	for _, server := range servers {
+		server.Shutdown(context.Background())
 	}
+	wg.Wait()
 	return nil
 }
 
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/store"
+)
+
+// rbacResources are the four RBAC kinds, which share the object handlers.
+var rbacResources = []objectResource{
+	{
+		group: rbacGroup, name: "roles", kind: "Role", namespaced: true,
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var role models.Role
+			err := json.NewDecoder(body).Decode(&role)
+			return &role, &role.Metadata, err
+		},
+		validate: func(obj interface{}) error { return validateRules(obj.(*models.Role).Rules) },
+		confirm: func(rbac *auth.RBACAuthorizer, user *auth.UserInfo, obj interface{}) error {
+			role := obj.(*models.Role)
+			return rbac.ConfirmRoleWrite(user, "roles", role.Metadata.Namespace, role.Metadata.Name, role.Rules)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveRole(*obj.(*models.Role)) },
+		get:  func(ns, name string) (interface{}, bool, error) { return store.GetRole(ns, name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, rbacGroup+"/v1", "RoleList", metadataFields,
+				func(o models.Role) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.Role) bool) (store.ListResult[models.Role], error) {
+					return store.ListRolesPage(mux.Vars(r)["namespace"], opts, filter)
+				})
+		},
+		remove: store.DeleteRole,
+	},
+	{
+		group: rbacGroup, name: "rolebindings", kind: "RoleBinding", namespaced: true,
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var binding models.RoleBinding
+			err := json.NewDecoder(body).Decode(&binding)
+			return &binding, &binding.Metadata, err
+		},
+		validate: func(obj interface{}) error {
+			binding := obj.(*models.RoleBinding)
+			if binding.RoleRef.Kind != "Role" && binding.RoleRef.Kind != "ClusterRole" {
+				return fmt.Errorf("roleRef.kind must be Role or ClusterRole")
+			}
+			return validateBinding(binding.Subjects, binding.RoleRef)
+		},
+		confirm: func(rbac *auth.RBACAuthorizer, user *auth.UserInfo, obj interface{}) error {
+			binding := obj.(*models.RoleBinding)
+			return rbac.ConfirmBinding(user, binding.Metadata.Namespace, binding.RoleRef)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveRoleBinding(*obj.(*models.RoleBinding)) },
+		get:  func(ns, name string) (interface{}, bool, error) { return store.GetRoleBinding(ns, name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, rbacGroup+"/v1", "RoleBindingList", metadataFields,
+				func(o models.RoleBinding) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.RoleBinding) bool) (store.ListResult[models.RoleBinding], error) {
+					return store.ListRoleBindingsPage(mux.Vars(r)["namespace"], opts, filter)
+				})
+		},
+		remove: store.DeleteRoleBinding,
+	},
+	{
+		group: rbacGroup, name: "clusterroles", kind: "ClusterRole",
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var role models.ClusterRole
+			err := json.NewDecoder(body).Decode(&role)
+			return &role, &role.Metadata, err
+		},
+		validate: func(obj interface{}) error { return validateRules(obj.(*models.ClusterRole).Rules) },
+		confirm: func(rbac *auth.RBACAuthorizer, user *auth.UserInfo, obj interface{}) error {
+			role := obj.(*models.ClusterRole)
+			return rbac.ConfirmRoleWrite(user, "clusterroles", "", role.Metadata.Name, role.Rules)
+		},
+		save: func(obj interface{}) (interface{}, error) { return store.SaveClusterRole(*obj.(*models.ClusterRole)) },
+		get:  func(_, name string) (interface{}, bool, error) { return store.GetClusterRole(name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, rbacGroup+"/v1", "ClusterRoleList", metadataFields,
+				func(o models.ClusterRole) (map[string]string, map[string]string) { return metadataAttrs(o.Metadata) },
+				func(opts store.ListOptions, filter func(models.ClusterRole) bool) (store.ListResult[models.ClusterRole], error) {
+					return store.ListClusterRolesPage(opts, filter)
+				})
+		},
+		remove: func(_, name string) (bool, error) { return store.DeleteClusterRole(name) },
+	},
+	{
+		group: rbacGroup, name: "clusterrolebindings", kind: "ClusterRoleBinding",
+		decode: func(body io.Reader) (interface{}, *models.Metadata, error) {
+			var binding models.ClusterRoleBinding
+			err := json.NewDecoder(body).Decode(&binding)
+			return &binding, &binding.Metadata, err
+		},
+		validate: func(obj interface{}) error {
+			binding := obj.(*models.ClusterRoleBinding)
+			if binding.RoleRef.Kind != "ClusterRole" {
+				return fmt.Errorf("roleRef.kind must be ClusterRole")
+			}
+			return validateBinding(binding.Subjects, binding.RoleRef)
+		},
+		confirm: func(rbac *auth.RBACAuthorizer, user *auth.UserInfo, obj interface{}) error {
+			return rbac.ConfirmBinding(user, "", obj.(*models.ClusterRoleBinding).RoleRef)
+		},
+		save: func(obj interface{}) (interface{}, error) {
+			return store.SaveClusterRoleBinding(*obj.(*models.ClusterRoleBinding))
+		},
+		get: func(_, name string) (interface{}, bool, error) { return store.GetClusterRoleBinding(name) },
+		list: func(w http.ResponseWriter, r *http.Request) {
+			serveList(w, r, rbacGroup+"/v1", "ClusterRoleBindingList", metadataFields,
+				func(o models.ClusterRoleBinding) (map[string]string, map[string]string) {
+					return metadataAttrs(o.Metadata)
+				},
+				func(opts store.ListOptions, filter func(models.ClusterRoleBinding) bool) (store.ListResult[models.ClusterRoleBinding], error) {
+					return store.ListClusterRoleBindingsPage(opts, filter)
+				})
+		},
+		remove: func(_, name string) (bool, error) { return store.DeleteClusterRoleBinding(name) },
+	},
+}
+
+func validateRules(rules []models.PolicyRule) error {
+	for i, rule := range rules {
+		if len(rule.Verbs) == 0 {
+			return fmt.Errorf("rules[%d]: at least one verb is required", i)
+		}
+		if len(rule.Resources) == 0 && len(rule.NonResourceURLs) == 0 {
+			return fmt.Errorf("rules[%d]: resources or nonResourceURLs is required", i)
+		}
+	}
+	return nil
+}
+
+func validateBinding(subjects []models.Subject, ref models.RoleRef) error {
+	if ref.Name == "" {
+		return fmt.Errorf("roleRef.name is required")
+	}
+	for i, subject := range subjects {
+		switch subject.Kind {
+		case models.SubjectUser, models.SubjectGroup, models.SubjectServiceAccount:
+		default:
+			return fmt.Errorf("subjects[%d]: unknown kind %q", i, subject.Kind)
+		}
+		if subject.Name == "" {
+			return fmt.Errorf("subjects[%d]: name is required", i)
+		}
+	}
+	return nil
+}
+
+func (s *APIServer) setupRBACRoutes() {
+	s.setupObjectRoutes(rbacResources)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+)
+
+// requestAttributes works out verb, resource, namespace and name from the
+// request path so every route is authorized the same way:
+//
+//	/api/v1/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+//	/apis/{group}/{version}/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+//
+// Anything else, or an unknown resource, is a non-resource request.
+func requestAttributes(r *http.Request, user *auth.UserInfo) auth.Attributes {
+	attrs := auth.Attributes{
+		User: user,
+		Path: r.URL.Path,
+		Verb: strings.ToLower(r.Method),
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var group string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api" && parts[1] == "v1":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		group = parts[1]
+		parts = parts[3:]
+	default:
+		return attrs
+	}
+
+	namespace := ""
+	if parts[0] == "namespaces" && len(parts) >= 3 {
+		namespace = parts[1]
+		parts = parts[2:]
+	}
+
+	resource, ok := lookupResource(group, parts[0])
+	if !ok {
+		return attrs
+	}
+
+	attrs.IsResourceRequest = true
+	attrs.Resource = resource.Name
+	if len(parts) > 1 {
+		attrs.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		attrs.Subresource = strings.Join(parts[2:], "/")
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		attrs.Verb = "get"
+		if connectSubresources[attrs.Subresource] {
+			attrs.Verb = "create"
+		} else if attrs.Name == "" {
+			attrs.Verb = "list"
+			if r.URL.Query().Get("watch") == "true" {
+				attrs.Verb = "watch"
+			}
+		}
+	case http.MethodPost:
+		attrs.Verb = "create"
+	case http.MethodPut:
+		attrs.Verb = "update"
+	case http.MethodPatch:
+		attrs.Verb = "patch"
+	case http.MethodDelete:
+		attrs.Verb = "delete"
+		if attrs.Name == "" {
+			attrs.Verb = "deletecollection"
+		}
+	}
+
+	if resource.Namespaced {
+		attrs.Namespace = namespace
+		// Legacy routes without a namespace act on "default" for single
+		// objects and creates, while lists without one span every namespace
+		if attrs.Namespace == "" && (attrs.Name != "" || attrs.Verb == "create") {
+			attrs.Namespace = "default"
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,88 @@
+package server
+
+import "github.com/selimhanmrl/Own-Kubernetes/models"
+
+// apiResource describes a resource the API server serves. Subresources
+// such as pods/status are listed separately, named "<resource>/<sub>".
+type apiResource struct {
+	Name       string // plural, as used in URLs
+	Singular   string
+	Kind       string
+	Group      string // "" for the core /api/v1 group
+	Namespaced bool
+	ShortNames []string
+	Verbs      []string
+
+	// Object and List are zero values of the Go types served, used to
+	// generate the OpenAPI schemas. List is nil when there is no list.
+	Object interface{}
+	List   interface{}
+}
+
+const (
+	appsGroup          = "apps"
+	rbacGroup          = "rbac.authorization.k8s.io"
+	coordinationGroup  = "coordination.k8s.io"
+	authorizationGroup = "authorization.k8s.io"
+)
+
+// connectSubresources stream over a connection upgraded from a GET, which
+// is authorized as a create since it reaches into the pod.
+var connectSubresources = map[string]bool{"exec": true, "portforward": true}
+
+var (
+	crudVerbs   = []string{"create", "delete", "get", "list", "patch", "update"}
+	updateVerbs = []string{"update"}
+)
+
+var apiResources = []apiResource{
+	{Name: "pods", Singular: "pod", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"},
+		Verbs: crudVerbs, Object: models.Pod{}, List: models.PodList{}},
+	{Name: "pods/status", Kind: "Pod", Namespaced: true, Verbs: updateVerbs, Object: models.Pod{}},
+	{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}, Object: models.Pod{}},
+	{Name: "pods/exec", Kind: "Pod", Namespaced: true, Verbs: []string{"create"}, Object: models.Pod{}},
+	{Name: "pods/portforward", Kind: "Pod", Namespaced: true, Verbs: []string{"create"}, Object: models.Pod{}},
+	{Name: "services", Singular: "service", Kind: "Service", Namespaced: true, ShortNames: []string{"svc"},
+		Verbs: []string{"create", "delete", "get", "list", "patch"}, Object: models.Service{}, List: models.ServiceList{}},
+	{Name: "nodes", Singular: "node", Kind: "Node", ShortNames: []string{"no"},
+		Verbs: []string{"create", "delete", "get", "list", "patch"}, Object: models.Node{}, List: models.NodeList{}},
+	{Name: "nodes/status", Kind: "Node", Verbs: updateVerbs, Object: models.Node{}},
+	{Name: "namespaces", Singular: "namespace", Kind: "Namespace", ShortNames: []string{"ns"},
+		Verbs: crudVerbs, Object: models.Namespace{}, List: models.NamespaceList{}},
+	{Name: "events", Singular: "event", Kind: "Event", Namespaced: true, ShortNames: []string{"ev"},
+		Verbs: crudVerbs, Object: models.Event{}, List: models.EventList{}},
+	{Name: "deployments", Singular: "deployment", Kind: "Deployment", Group: appsGroup, Namespaced: true,
+		ShortNames: []string{"deploy"}, Verbs: crudVerbs, Object: models.Deployment{}, List: models.DeploymentList{}},
+	{Name: "replicasets", Singular: "replicaset", Kind: "ReplicaSet", Group: appsGroup, Namespaced: true,
+		ShortNames: []string{"rs"}, Verbs: crudVerbs, Object: models.ReplicaSet{}, List: models.ReplicaSetList{}},
+	{Name: "roles", Singular: "role", Kind: "Role", Group: rbacGroup, Namespaced: true,
+		Verbs: crudVerbs, Object: models.Role{}, List: models.RoleList{}},
+	{Name: "rolebindings", Singular: "rolebinding", Kind: "RoleBinding", Group: rbacGroup, Namespaced: true,
+		Verbs: crudVerbs, Object: models.RoleBinding{}, List: models.RoleBindingList{}},
+	{Name: "clusterroles", Singular: "clusterrole", Kind: "ClusterRole", Group: rbacGroup,
+		Verbs: crudVerbs, Object: models.ClusterRole{}, List: models.ClusterRoleList{}},
+	{Name: "clusterrolebindings", Singular: "clusterrolebinding", Kind: "ClusterRoleBinding", Group: rbacGroup,
+		Verbs: crudVerbs, Object: models.ClusterRoleBinding{}, List: models.ClusterRoleBindingList{}},
+	{Name: "leases", Singular: "lease", Kind: "Lease", Group: coordinationGroup, Namespaced: true,
+		Verbs: crudVerbs, Object: models.Lease{}, List: models.LeaseList{}},
+	{Name: "selfsubjectaccessreviews", Singular: "selfsubjectaccessreview", Kind: "SelfSubjectAccessReview",
+		Group: authorizationGroup, Verbs: []string{"create"}, Object: models.SelfSubjectAccessReview{}},
+}
+
+func lookupResource(group, name string) (apiResource, bool) {
+	for _, r := range apiResources {
+		if r.Group == group && r.Name == name {
+			return r, true
+		}
+	}
+	return apiResource{}, false
+}
+
+// groupVersion returns the apiVersion of the resource, e.g. v1 or
+// rbac.authorization.k8s.io/v1.
+func (r apiResource) groupVersion() string {
+	if r.Group == "" {
+		return "v1"
+	}
+	return r.Group + "/v1"
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/selector"
+)
+
+// listSelector holds the labelSelector and fieldSelector of a list request.
+type listSelector struct {
+	labels selector.Selector
+	fields selector.Selector
+}
+
+// metadataFields are the fields every resource can be selected by.
+var metadataFields = []string{"metadata.name", "metadata.namespace"}
+
+// parseListSelector reads the selectors from the query string, rejecting
+// field selectors on fields outside supported.
+func parseListSelector(r *http.Request, supported []string) (listSelector, error) {
+	var sel listSelector
+	var err error
+
+	query := r.URL.Query()
+	if sel.labels, err = selector.ParseLabelSelector(query.Get("labelSelector")); err != nil {
+		return sel, err
+	}
+	if sel.fields, err = selector.ParseFieldSelector(query.Get("fieldSelector")); err != nil {
+		return sel, err
+	}
+
+	for _, key := range sel.fields.Keys() {
+		if !contains(supported, key) {
+			return sel, fmt.Errorf("field selector %q is not supported, use one of: %s", key, strings.Join(supported, ", "))
+		}
+	}
+	return sel, nil
+}
+
+func (sel listSelector) matches(labels, fields map[string]string) bool {
+	return sel.labels.Matches(labels) && sel.fields.Matches(fields)
+}
+
+var podSelectableFields = []string{"metadata.name", "metadata.namespace", "spec.nodeName", "status.phase"}
+
+func podAttrs(pod models.Pod) (map[string]string, map[string]string) {
+	return pod.Metadata.Labels, map[string]string{
+		"metadata.name":      pod.Metadata.Name,
+		"metadata.namespace": pod.Metadata.Namespace,
+		"spec.nodeName":      pod.Spec.NodeName,
+		"status.phase":       pod.Status.Phase,
+	}
+}
+
+var serviceSelectableFields = []string{"metadata.name", "metadata.namespace", "spec.type"}
+
+func serviceAttrs(service models.Service) (map[string]string, map[string]string) {
+	return service.Metadata.Labels, map[string]string{
+		"metadata.name":      service.Metadata.Name,
+		"metadata.namespace": service.Metadata.Namespace,
+		"spec.type":          service.Spec.Type,
+	}
+}
+
+var nodeSelectableFields = []string{"metadata.name", "status.phase"}
+
+func nodeAttrs(node models.Node) (map[string]string, map[string]string) {
+	return node.Labels, map[string]string{
+		"metadata.name": node.Name,
+		"status.phase":  node.Status.Phase,
+	}
+}
+
+// metadataAttrs adapts resources that only expose their metadata.
+func metadataAttrs(meta models.Metadata) (map[string]string, map[string]string) {
+	return meta.Labels, map[string]string{
+		"metadata.name":      meta.Name,
+		"metadata.namespace": meta.Namespace,
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,88 +2,215 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/gorilla/mux"
+	"github.com/selimhanmrl/Own-Kubernetes/audit"
+	"github.com/selimhanmrl/Own-Kubernetes/auth"
+	"github.com/selimhanmrl/Own-Kubernetes/component"
+	"github.com/selimhanmrl/Own-Kubernetes/healthz"
 	"github.com/selimhanmrl/Own-Kubernetes/models"
+	"github.com/selimhanmrl/Own-Kubernetes/pki"
 	"github.com/selimhanmrl/Own-Kubernetes/store"
 )
 
 type APIServer struct {
-	router *mux.Router
-	proxy  *ProxyServer
+	router        *mux.Router
+	proxy         *ProxyServer
+	options       APIServerOptions
+	authenticator auth.Authenticator
+	authorizer    auth.Authorizer
+	rbac          *auth.RBACAuthorizer // nil unless RBAC is on; guards role and binding writes
+	auditPolicy   *audit.Policy
+	auditBackend  audit.Backend // nil when auditing is off
+	nodes         *nodeClient
+
+	// Startup and shutdown state reported by /readyz
+	servicesSynced   *healthz.Signal
+	rbacBootstrapped *healthz.Signal
+	shuttingDown     atomic.Bool
 }
 
-func NewAPIServer() *APIServer {
+// APIServerOptions configures listening, authentication, authorization
+// and TLS for the API server.
+type APIServerOptions struct {
+	Serving component.ServingOptions
+	// ProxyBindAddress is the IP NodePort services listen on; empty means
+	// all interfaces.
+	ProxyBindAddress string
+
+	Auth        auth.Config
+	Audit       audit.Config
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// AuthorizationModes are tried in order, e.g. ["Node", "RBAC"]. Empty
+	// means every authenticated request is allowed.
+	AuthorizationModes []string
+
+	// NodeClient is how pod logs are fetched from node servers.
+	NodeClient NodeClientOptions
+}
+
+func NewAPIServer(options APIServerOptions) (*APIServer, error) {
+	if options.Auth.ClientCAFile != "" && options.TLSCertFile == "" {
+		return nil, fmt.Errorf("client certificate authentication requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	authenticator, err := auth.NewAuthenticator(options.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up authentication: %v", err)
+	}
+
+	authorizer, err := newAuthorizer(options.AuthorizationModes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up authorization: %v", err)
+	}
+
+	nodes, err := newNodeClient(options.NodeClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the node server client: %v", err)
+	}
+
 	server := &APIServer{
-		router: mux.NewRouter(),
-		proxy:  NewProxyServer(),
+		router:        mux.NewRouter(),
+		proxy:         NewProxyServer(),
+		options:       options,
+		authenticator: authenticator,
+		authorizer:    authorizer,
+		nodes:         nodes,
+
+		servicesSynced:   healthz.NewSignal("informer-sync"),
+		rbacBootstrapped: healthz.NewSignal("poststarthook/rbac-bootstrap-roles"),
+	}
+	if server.rbacEnabled() {
+		server.rbac = auth.NewRBACAuthorizer(storeRBAC{})
 	}
-	// Start the proxy server
-	go server.proxy.Start()
-	return server
+	if options.Audit.Enabled() {
+		server.auditPolicy, server.auditBackend, err = audit.New(options.Audit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up auditing: %v", err)
+		}
+	}
+	return server, nil
 }
 
-func (s *APIServer) Start() {
+// Run serves the API until ctx is cancelled or the process receives
+// SIGTERM, then drains in-flight requests and stops the service proxy.
+func (s *APIServer) Run(ctx context.Context) error {
 	s.setupRoutes()
-	log.Printf("✅ API Server starting on port 8080")
-	if err := http.ListenAndServe(":8080", s.router); err != nil {
-		log.Fatalf("❌ Failed to start server: %v", err)
+
+	if !s.options.Auth.Enabled() {
+		log.Printf("⚠️ No authenticators configured, all requests are treated as anonymous")
+	}
+	if s.rbacEnabled() {
+		ensureBootstrapPolicy()
+		s.rbacBootstrapped.Set()
 	}
+
+	httpServer := &http.Server{
+		Addr:    s.options.Serving.Address(),
+		Handler: s.router,
+	}
+	runner := component.NewRunner("API server", s.options.Serving.ShutdownTimeout)
+	if s.options.TLSCertFile == "" {
+		log.Printf("✅ API Server starting on %s", httpServer.Addr)
+		runner.Serve(httpServer, false)
+	} else {
+		tlsConfig, err := pki.ServerConfig(s.options.TLSCertFile, s.options.TLSKeyFile, s.options.Auth.ClientCAFile, false)
+		if err != nil {
+			return fmt.Errorf("failed to set up TLS: %v", err)
+		}
+		httpServer.TLSConfig = tlsConfig
+		log.Printf("✅ API Server starting on %s (TLS)", httpServer.Addr)
+		runner.Serve(httpServer, true)
+	}
+	runner.Go("service proxy", func(ctx context.Context) error {
+		if !s.syncServices(ctx) {
+			return nil
+		}
+		return s.proxy.Run(ctx, s.options.ProxyBindAddress)
+	})
+	// Stop sending traffic here while requests drain
+	runner.OnShutdown(func() { s.shuttingDown.Store(true) })
+	err := runner.Run(ctx)
+	// Requests have drained, so no more audit events are coming
+	if s.auditBackend != nil {
+		s.auditBackend.Shutdown()
+	}
+	return err
 }
 
 func (s *APIServer) setupRoutes() {
 	// Pod endpoints
 	fmt.Println("📝 Registering API routes...")
 
+	s.router.Use(s.authenticate, s.withAudit, s.authorize, checkDryRun)
+	s.setupHealthRoutes()
+	s.setupDiscoveryRoutes()
+	s.router.HandleFunc("/api/v1/whoami", s.handleWhoAmI).Methods("GET")
+	s.router.HandleFunc("/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", s.handleSelfSubjectAccessReview).Methods("POST")
+
 	s.router.HandleFunc("/api/v1/pods", s.handleListPods).Methods("GET")
-	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods", s.handleListPodsByNamespace).Methods("GET")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods", s.handleListPods).Methods("GET")
 	s.router.HandleFunc("/api/v1/pods", s.handleCreatePod).Methods("POST")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods", s.handleCreatePod).Methods("POST")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}", s.handleGetPod).Methods("GET")
 	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}", s.handleDeletePod).Methods("DELETE")
 	s.router.HandleFunc("/api/v1/pods/{name}", s.handleDeletePod).Methods("DELETE")
 	s.router.HandleFunc("/api/v1/pods/{name}", s.handleGetPod).Methods("GET")
 
 	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}", s.handleUpdatePod).Methods("PUT")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}", s.handlePatchPod).Methods("PATCH")
+	s.router.HandleFunc("/api/v1/pods/{name}", s.handlePatchPod).Methods("PATCH")
 	s.router.HandleFunc("/api/v1/pods/{name}/status", s.handleUpdatePodStatus).Methods("PUT")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}/log", s.handlePodLog).Methods("GET")
+	s.router.HandleFunc("/api/v1/pods/{name}/log", s.handlePodLog).Methods("GET")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}/exec", s.handlePodExec).Methods("GET")
+	s.router.HandleFunc("/api/v1/pods/{name}/exec", s.handlePodExec).Methods("GET")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/pods/{name}/portforward", s.handlePodPortForward).Methods("GET")
+	s.router.HandleFunc("/api/v1/pods/{name}/portforward", s.handlePodPortForward).Methods("GET")
 
 	// Service endpoints
 	s.router.HandleFunc("/api/v1/services", s.handleListServices).Methods("GET")
-	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services", s.handleListServicesByNamespace).Methods("GET")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services", s.handleListServices).Methods("GET")
 	s.router.HandleFunc("/api/v1/services", s.handleCreateService).Methods("POST")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services", s.handleCreateService).Methods("POST")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services/{name}", s.handleGetService).Methods("GET")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services/{name}", s.handlePatchService).Methods("PATCH")
+	s.router.HandleFunc("/api/v1/namespaces/{namespace}/services/{name}", s.handleDeleteService).Methods("DELETE")
+
+	// RBAC endpoints
+	s.setupRBACRoutes()
+	s.setupObjectRoutes(appsResources)
+	s.setupObjectRoutes([]objectResource{namespaceResource})
+	s.setupObjectRoutes(coordinationResources)
+
+	// Event endpoints
+	s.setupEventRoutes()
 
 	// Node endpoints
 	s.router.HandleFunc("/api/v1/nodes", s.handleListNodes).Methods("GET")
 	s.router.HandleFunc("/api/v1/nodes", s.handleRegisterNode).Methods("POST")
+	s.router.HandleFunc("/api/v1/nodes/{name}", s.handleGetNode).Methods("GET")
+	s.router.HandleFunc("/api/v1/nodes/{name}", s.handlePatchNode).Methods("PATCH")
+	s.router.HandleFunc("/api/v1/nodes/{name}", s.handleDeleteNode).Methods("DELETE")
 	s.router.HandleFunc("/api/v1/nodes/{name}/status", s.handleUpdateNodeStatus).Methods("PUT")
 }
 
 func (s *APIServer) handleListPods(w http.ResponseWriter, r *http.Request) {
-	nodeName := r.URL.Query().Get("fieldSelector")
-
-	pods := store.ListAllPods()
-	if nodeName != "" {
-		// Filter pods by node name
-		nodePods := []models.Pod{}
-		for _, pod := range pods {
-			if pod.Spec.NodeName == nodeName {
-				nodePods = append(nodePods, pod)
-			}
-		}
-		pods = nodePods
-	}
-
-	respondJSON(w, http.StatusOK, pods)
-}
-
-func (s *APIServer) handleListPodsByNamespace(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	namespace := vars["namespace"]
-	pods := store.ListPods(namespace)
-	respondJSON(w, http.StatusOK, pods)
+	// Namespace is empty on /api/v1/pods, listing all namespaces
+	namespace := mux.Vars(r)["namespace"]
+	serveList(w, r, "v1", "PodList", podSelectableFields, podAttrs,
+		func(opts store.ListOptions, filter func(models.Pod) bool) (store.ListResult[models.Pod], error) {
+			return store.ListPodsPage(namespace, opts, filter)
+		})
 }
 
 func (s *APIServer) handleCreatePod(w http.ResponseWriter, r *http.Request) {
@@ -92,22 +219,43 @@ func (s *APIServer) handleCreatePod(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
+	if err := matchNamespace(r, &pod.Metadata.Namespace); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defaultPod(&pod)
 
-	if err := store.SavePod(pod); err != nil {
+	if _, found := store.GetPod(pod.Metadata.Namespace, pod.Metadata.Name); found {
+		respondError(w, http.StatusConflict, fmt.Sprintf("Pod '%s' already exists", pod.Metadata.Name))
+		return
+	}
+
+	if isDryRun(r) {
+		respondJSON(w, http.StatusCreated, pod)
+		return
+	}
+	saved, err := store.SavePod(pod)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, pod)
+	respondJSON(w, http.StatusCreated, saved)
 }
 
 func (s *APIServer) handleDeletePod(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	podName := vars["name"]
 
+	if isDryRun(r) {
+		_, found := store.GetPod(requestNamespace(r), podName)
+		respondDryRunDelete(w, "Pod", found)
+		return
+	}
+
 	fmt.Printf("🗑️ Handling delete request for pod: %s\n", podName)
 
-	if err := store.DeletePod("default", podName); err != nil {
+	if err := store.DeletePod(requestNamespace(r), podName); err != nil {
 		fmt.Printf("❌ Failed to delete pod: %v\n", err)
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -133,25 +281,34 @@ func (s *APIServer) handleUpdatePod(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "Pod name/namespace mismatch")
 		return
 	}
+	var live interface{}
+	if current, found := store.GetPod(namespace, name); found {
+		if err := checkResourceVersion(current.Metadata.ResourceVersion, pod.Metadata.ResourceVersion); err != nil {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		live = current
+	}
+	if err := recordUpdate(r, live, &pod, podMergeKeys); err != nil {
+		respondPatchError(w, err)
+		return
+	}
 
-	if err := store.SavePod(pod); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+	saved, err := store.SavePod(pod)
+	if err != nil {
+		respondSaveError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, pod)
+	respondJSON(w, http.StatusOK, saved)
 }
 
 func (s *APIServer) handleListServices(w http.ResponseWriter, r *http.Request) {
-	services := store.ListServices("")
-	respondJSON(w, http.StatusOK, services)
-}
-
-func (s *APIServer) handleListServicesByNamespace(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	namespace := vars["namespace"]
-	services := store.ListServices(namespace)
-	respondJSON(w, http.StatusOK, services)
+	namespace := mux.Vars(r)["namespace"]
+	serveList(w, r, "v1", "ServiceList", serviceSelectableFields, serviceAttrs,
+		func(opts store.ListOptions, filter func(models.Service) bool) (store.ListResult[models.Service], error) {
+			return store.ListServicesPage(namespace, opts, filter)
+		})
 }
 
 func (s *APIServer) handleCreateService(w http.ResponseWriter, r *http.Request) {
@@ -160,13 +317,64 @@ func (s *APIServer) handleCreateService(w http.ResponseWriter, r *http.Request)
 		respondError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
+	if err := matchNamespace(r, &service.Metadata.Namespace); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, found, err := store.GetService(service.Metadata.Namespace, service.Metadata.Name); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if found {
+		respondError(w, http.StatusConflict, fmt.Sprintf("Service '%s' already exists", service.Metadata.Name))
+		return
+	}
 
-	if err := store.SaveService(service); err != nil {
+	if isDryRun(r) {
+		respondJSON(w, http.StatusCreated, service)
+		return
+	}
+	saved, err := store.SaveService(service)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// After saving the service, register it with the proxy
+	s.registerServiceWithProxy(&saved)
+
+	respondJSON(w, http.StatusCreated, saved)
+}
+
+func (s *APIServer) handleDeleteService(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if isDryRun(r) {
+		_, found, err := store.GetService(requestNamespace(r), name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondDryRunDelete(w, "Service", found)
+		return
+	}
+	deleted, err := store.DeleteService(requestNamespace(r), name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "Service not found")
+		return
+	}
+	s.proxy.RemoveService(name)
+
+	fmt.Printf("🗑️ Service '%s' deleted\n", name)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Service deleted successfully"})
+}
+
+// registerServiceWithProxy points the proxy at the pods matching the
+// service's selector.
+func (s *APIServer) registerServiceWithProxy(service *models.Service) {
 	pods := store.ListPods("") // Get all pods
 	matchingPods := []models.Pod{}
 
@@ -177,14 +385,11 @@ func (s *APIServer) handleCreateService(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	s.proxy.RegisterService(&service, matchingPods)
-
-	respondJSON(w, http.StatusCreated, service)
+	s.proxy.RegisterService(service, matchingPods)
 }
 
 func (s *APIServer) handleListNodes(w http.ResponseWriter, r *http.Request) {
-	nodes := store.ListNodes()
-	respondJSON(w, http.StatusOK, nodes)
+	serveList(w, r, "v1", "NodeList", nodeSelectableFields, nodeAttrs, store.ListNodesPage)
 }
 
 func (s *APIServer) handleRegisterNode(w http.ResponseWriter, r *http.Request) {
@@ -196,7 +401,20 @@ func (s *APIServer) handleRegisterNode(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("🔌 Node '%s' attempting to connect from IP %s\n", node.Name, node.IP)
 
-	if err := store.SaveNode(node); err != nil {
+	// Nodes may only register themselves
+	if user, ok := auth.UserFrom(r.Context()); ok {
+		if nodeName, isNode := user.NodeName(); isNode && nodeName != node.Name {
+			respondError(w, http.StatusForbidden, fmt.Sprintf("node %q cannot register node %q", nodeName, node.Name))
+			return
+		}
+	}
+	if isDryRun(r) {
+		respondJSON(w, http.StatusCreated, node)
+		return
+	}
+
+	node, err := store.SaveNode(node)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -212,11 +430,36 @@ func (s *APIServer) handleRegisterNode(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, node)
 }
 
+func (s *APIServer) handleDeleteNode(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if isDryRun(r) {
+		_, found, err := store.GetNode(name)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondDryRunDelete(w, "Node", found)
+		return
+	}
+	deleted, err := store.DeleteNode(name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "Node not found")
+		return
+	}
+
+	fmt.Printf("🗑️ Node '%s' deleted\n", name)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Node deleted successfully"})
+}
+
 func (s *APIServer) handleGetPod(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	podName := vars["name"]
 
-	pod, found := store.GetPod(podName)
+	pod, found := store.GetPod(requestNamespace(r), podName)
 	if !found {
 		respondError(w, http.StatusNotFound, "Pod not found")
 		return
@@ -245,6 +488,30 @@ func (s *APIServer) handleUpdateNodeStatus(w http.ResponseWriter, r *http.Reques
 	respondJSON(w, http.StatusOK, status)
 }
 
+// requestNamespace returns the namespace in the request path, falling back
+// to "default" for the legacy routes that have none.
+func requestNamespace(r *http.Request) string {
+	if namespace := mux.Vars(r)["namespace"]; namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// matchNamespace fills in an object's namespace from the request path, or
+// rejects the object if it names a different one, so the namespace that was
+// authorized is the one that gets written.
+func matchNamespace(r *http.Request, namespace *string) error {
+	expected := requestNamespace(r)
+	if *namespace == "" {
+		*namespace = expected
+		return nil
+	}
+	if *namespace != expected {
+		return fmt.Errorf("namespace %q does not match the request namespace %q", *namespace, expected)
+	}
+	return nil
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -260,9 +527,8 @@ func (s *APIServer) handleUpdatePodStatus(w http.ResponseWriter, r *http.Request
 	podName := vars["name"]
 	fmt.Printf("🔍 Handling status update for pod: %s\n", podName)
 
-	// Log request method and headers
+	// Log request method (headers are not logged, they carry credentials)
 	fmt.Printf("📝 Request Method: %s\n", r.Method)
-	fmt.Printf("📝 Request Headers: %+v\n", r.Header)
 
 	// Read and log the request body
 	body, err := ioutil.ReadAll(r.Body)
@@ -281,7 +547,7 @@ func (s *APIServer) handleUpdatePodStatus(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	existingPod, found := store.GetPod(podName)
+	existingPod, found := store.GetPod(requestNamespace(r), podName)
 	fmt.Printf("🔍 Pod lookup result - Found: %v\n", found)
 	if !found {
 		fmt.Printf("❌ Pod not found: %s\n", podName)
@@ -294,9 +560,9 @@ func (s *APIServer) handleUpdatePodStatus(w http.ResponseWriter, r *http.Request
 
 	// Update status fields
 	existingPod.Status = pod.Status
-	if err := store.SavePod(existingPod); err != nil {
+	if existingPod, err = store.SavePod(existingPod); err != nil {
 		fmt.Printf("❌ Failed to save pod: %v\n", err)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondSaveError(w, err)
 		return
 	}
 
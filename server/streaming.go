@@ -0,0 +1,19 @@
+package server
+
+import "net/http"
+
+// flushWriter flushes every write, so that streamed output such as a
+// followed log reaches the client as it is produced.
+type flushWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	f.written = true
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// ReplicaSets
+
+func SaveReplicaSet(rs models.ReplicaSet) (models.ReplicaSet, error) {
+	rs.Metadata.Namespace = defaultNamespace(rs.Metadata.Namespace)
+	return saveObject(fmt.Sprintf("replicasets:%s:%s", rs.Metadata.Namespace, rs.Metadata.Name), rs)
+}
+
+func GetReplicaSet(namespace, name string) (models.ReplicaSet, bool, error) {
+	var rs models.ReplicaSet
+	found, err := getObject(fmt.Sprintf("replicasets:%s:%s", defaultNamespace(namespace), name), &rs)
+	return rs, found, err
+}
+
+// ListReplicaSetsPage lists replica sets in namespace, or in all namespaces
+// when it is empty.
+func ListReplicaSetsPage(namespace string, opts ListOptions, filter func(models.ReplicaSet) bool) (ListResult[models.ReplicaSet], error) {
+	return listPage(namespacedPattern("replicasets", namespace), opts, filter)
+}
+
+func DeleteReplicaSet(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("replicasets:%s:%s", defaultNamespace(namespace), name))
+}
+
+// Deployments
+
+func SaveDeployment(deployment models.Deployment) (models.Deployment, error) {
+	deployment.Metadata.Namespace = defaultNamespace(deployment.Metadata.Namespace)
+	return saveObject(fmt.Sprintf("deployments:%s:%s", deployment.Metadata.Namespace, deployment.Metadata.Name), deployment)
+}
+
+func GetDeployment(namespace, name string) (models.Deployment, bool, error) {
+	var deployment models.Deployment
+	found, err := getObject(fmt.Sprintf("deployments:%s:%s", defaultNamespace(namespace), name), &deployment)
+	return deployment, found, err
+}
+
+// ListDeploymentsPage lists deployments in namespace, or in all namespaces
+// when it is empty.
+func ListDeploymentsPage(namespace string, opts ListOptions, filter func(models.Deployment) bool) (ListResult[models.Deployment], error) {
+	return listPage(namespacedPattern("deployments", namespace), opts, filter)
+}
+
+func DeleteDeployment(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("deployments:%s:%s", defaultNamespace(namespace), name))
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// Leases
+
+func SaveLease(lease models.Lease) (models.Lease, error) {
+	lease.Metadata.Namespace = defaultNamespace(lease.Metadata.Namespace)
+	return saveObject(fmt.Sprintf("leases:%s:%s", lease.Metadata.Namespace, lease.Metadata.Name), lease)
+}
+
+func GetLease(namespace, name string) (models.Lease, bool, error) {
+	var lease models.Lease
+	found, err := getObject(fmt.Sprintf("leases:%s:%s", defaultNamespace(namespace), name), &lease)
+	return lease, found, err
+}
+
+// ListLeasesPage lists leases in namespace, or in all namespaces when it is
+// empty.
+func ListLeasesPage(namespace string, opts ListOptions, filter func(models.Lease) bool) (ListResult[models.Lease], error) {
+	return listPage(namespacedPattern("leases", namespace), opts, filter)
+}
+
+func DeleteLease(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("leases:%s:%s", defaultNamespace(namespace), name))
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// eventTTL is how long an event is kept after it was last recorded.
+var eventTTL = time.Hour
+
+// SetEventTTL sets how long events are kept after their last update.
+func SetEventTTL(ttl time.Duration) {
+	eventTTL = ttl
+}
+
+// SaveEvent stores an event, restarting its time to live, and returns it as
+// stored.
+func SaveEvent(event models.Event) (models.Event, error) {
+	event.Metadata.Namespace = defaultNamespace(event.Metadata.Namespace)
+	return saveObjectTTL(fmt.Sprintf("events:%s:%s", event.Metadata.Namespace, event.Metadata.Name), event, eventTTL)
+}
+
+func GetEvent(namespace, name string) (models.Event, bool, error) {
+	var event models.Event
+	found, err := getObject(fmt.Sprintf("events:%s:%s", defaultNamespace(namespace), name), &event)
+	return event, found, err
+}
+
+// ListEventsPage lists events in namespace, or in all namespaces when it is
+// empty.
+func ListEventsPage(namespace string, opts ListOptions, filter func(models.Event) bool) (ListResult[models.Event], error) {
+	return listPage(namespacedPattern("events", namespace), opts, filter)
+}
+
+func DeleteEvent(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("events:%s:%s", defaultNamespace(namespace), name))
+}
@@ -0,0 +1,369 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	own_redis "github.com/selimhanmrl/Own-Kubernetes/redis"
+)
+
+const (
+	// scanCount is the COUNT hint passed to SCAN.
+	scanCount = 1000
+	// mgetBatch is how many keys are fetched per MGET.
+	mgetBatch = 500
+
+	resourceVersionKey = "resourceVersion"
+
+	// snapshotPrefix prefixes the keys holding the rest of a paged list,
+	// kept for listSnapshotTTL after the page that last used them.
+	snapshotPrefix  = "listsnapshots:"
+	listSnapshotTTL = 5 * time.Minute
+)
+
+var (
+	// ErrInvalidContinue is returned for a continue token that was not
+	// issued for the list being requested.
+	ErrInvalidContinue = errors.New("invalid continue token")
+	// ErrContinueExpired is returned for a continue token whose snapshot
+	// is gone; the list has to be started again.
+	ErrContinueExpired = errors.New("continue token has expired, list again without it")
+)
+
+// ListOptions pages through a list. A zero Limit returns everything.
+type ListOptions struct {
+	Limit    int64
+	Continue string
+}
+
+// ListResult is one page of a list. Continue is empty on the last page.
+// RemainingItemCount is only known when the list is not filtered.
+type ListResult[T any] struct {
+	Items              []T
+	Continue           string
+	ResourceVersion    string
+	RemainingItemCount *int64
+}
+
+// continueToken records where the next page starts. The first page of a
+// paged list scans and sorts the keys once and stores those it did not
+// return as a snapshot, so later pages only read their share of it instead
+// of scanning again. Objects created after the first page are not listed
+// and ones deleted since are skipped.
+type continueToken struct {
+	Pattern  string `json:"p"`
+	Snapshot string `json:"s"`
+	Offset   int64  `json:"o"`
+}
+
+func encodeContinue(ct continueToken) string {
+	data, _ := json.Marshal(ct)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeContinue(pattern, token string) (continueToken, error) {
+	var ct continueToken
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ct, ErrInvalidContinue
+	}
+	if err := json.Unmarshal(data, &ct); err != nil || ct.Pattern != pattern || ct.Snapshot == "" || ct.Offset < 0 {
+		return ct, ErrInvalidContinue
+	}
+	return ct, nil
+}
+
+// saveSnapshot stores sorted keys for the following pages of a list and
+// returns the snapshot's name.
+func saveSnapshot(keys []string) (string, error) {
+	name := uuid.NewString()
+	key := snapshotPrefix + name
+	_, err := own_redis.RedisClient.TxPipelined(own_redis.Ctx, func(pipe redis.Pipeliner) error {
+		for start := 0; start < len(keys); start += mgetBatch {
+			end := start + mgetBatch
+			if end > len(keys) {
+				end = len(keys)
+			}
+			values := make([]interface{}, 0, end-start)
+			for _, k := range keys[start:end] {
+				values = append(values, k)
+			}
+			pipe.RPush(own_redis.Ctx, key, values...)
+		}
+		pipe.Expire(own_redis.Ctx, key, listSnapshotTTL)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save list snapshot: %v", err)
+	}
+	return name, nil
+}
+
+// openSnapshot returns how many keys of a snapshot are left from ct's
+// offset and a function reading them by position, and keeps the snapshot
+// for another listSnapshotTTL.
+func openSnapshot(ct continueToken) (int, func(start, end int) ([]string, error), error) {
+	key := snapshotPrefix + ct.Snapshot
+	length, err := own_redis.RedisClient.LLen(own_redis.Ctx, key).Result()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read list snapshot: %v", err)
+	}
+	if length == 0 {
+		return 0, nil, ErrContinueExpired
+	}
+	if ct.Offset > length {
+		return 0, nil, ErrInvalidContinue
+	}
+	own_redis.RedisClient.Expire(own_redis.Ctx, key, listSnapshotTTL)
+
+	read := func(start, end int) ([]string, error) {
+		keys, err := own_redis.RedisClient.LRange(own_redis.Ctx, key, ct.Offset+int64(start), ct.Offset+int64(end)-1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read list snapshot: %v", err)
+		}
+		if len(keys) != end-start {
+			// Expired while paging
+			return nil, ErrContinueExpired
+		}
+		return keys, nil
+	}
+	return int(length - ct.Offset), read, nil
+}
+
+// bumpResourceVersion advances the store's resource version after a write.
+// Lists report it so clients can tell whether anything changed.
+func bumpResourceVersion() {
+	if err := own_redis.RedisClient.Incr(own_redis.Ctx, resourceVersionKey).Err(); err != nil {
+		fmt.Printf("⚠️ Failed to bump resource version: %v\n", err)
+	}
+}
+
+// stampResourceVersion advances the store's resource version before a write
+// and records it as metadata.resourceVersion of the object in value, so
+// writers can tell whether an object changed since they read it. Objects
+// without metadata, such as nodes, are returned as they are.
+func stampResourceVersion(value []byte) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, err
+	}
+	rawMeta, ok := object["metadata"]
+	if !ok {
+		bumpResourceVersion()
+		return value, nil
+	}
+	var meta map[string]json.RawMessage
+	if err := json.Unmarshal(rawMeta, &meta); err != nil {
+		return nil, err
+	}
+
+	rv, err := own_redis.RedisClient.Incr(own_redis.Ctx, resourceVersionKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to bump resource version: %v", err)
+	}
+	if meta["resourceVersion"], err = json.Marshal(strconv.FormatInt(rv, 10)); err != nil {
+		return nil, err
+	}
+	if object["metadata"], err = json.Marshal(meta); err != nil {
+		return nil, err
+	}
+	return json.Marshal(object)
+}
+
+// CurrentResourceVersion returns the store's resource version.
+func CurrentResourceVersion() (string, error) {
+	value, err := own_redis.RedisClient.Get(own_redis.Ctx, resourceVersionKey).Result()
+	if err == redis.Nil {
+		return "0", nil
+	} else if err != nil {
+		return "", err
+	}
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		return "", fmt.Errorf("corrupt resource version %q", value)
+	}
+	return value, nil
+}
+
+// scanKeys returns the keys matching pattern, sorted. SCAN walks the
+// keyspace incrementally instead of blocking Redis like KEYS does.
+func scanKeys(pattern string) ([]string, error) {
+	if own_redis.RedisClient == nil {
+		return nil, fmt.Errorf("RedisClient is not initialized")
+	}
+
+	seen := make(map[string]bool)
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := own_redis.RedisClient.Scan(own_redis.Ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %v", pattern, err)
+		}
+		// SCAN may return a key more than once
+		for _, key := range batch {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// mgetObjects loads the objects stored under keys with batched MGETs. Keys
+// deleted since they were scanned are skipped. keysOut receives the key of
+// each returned object.
+func mgetObjects[T any](keys []string) (objects []T, keysOut []string, err error) {
+	for start := 0; start < len(keys); start += mgetBatch {
+		end := start + mgetBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		values, err := own_redis.RedisClient.MGet(own_redis.Ctx, keys[start:end]...).Result()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load objects: %v", err)
+		}
+		for i, value := range values {
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			var obj T
+			if err := json.Unmarshal([]byte(str), &obj); err != nil {
+				fmt.Printf("❌ Failed to unmarshal %s: %v\n", keys[start+i], err)
+				continue
+			}
+			objects = append(objects, obj)
+			keysOut = append(keysOut, keys[start+i])
+		}
+	}
+	return objects, keysOut, nil
+}
+
+// listPage returns up to opts.Limit objects under pattern that pass filter,
+// starting at the position in opts.Continue. filter may be nil.
+func listPage[T any](pattern string, opts ListOptions, filter func(T) bool) (ListResult[T], error) {
+	var result ListResult[T]
+
+	rv, err := CurrentResourceVersion()
+	if err != nil {
+		return result, err
+	}
+	result.ResourceVersion = rv
+	result.Items = []T{}
+
+	// total keys are left to list, read by position with keysAt
+	var (
+		total  int
+		keysAt func(start, end int) ([]string, error)
+		keys   []string
+		ct     continueToken
+	)
+	if opts.Continue == "" {
+		if keys, err = scanKeys(pattern); err != nil {
+			return result, err
+		}
+		if opts.Limit <= 0 {
+			objects, _, err := mgetObjects[T](keys)
+			if err != nil {
+				return result, err
+			}
+			for _, obj := range objects {
+				if filter == nil || filter(obj) {
+					result.Items = append(result.Items, obj)
+				}
+			}
+			return result, nil
+		}
+		total = len(keys)
+		keysAt = func(start, end int) ([]string, error) { return keys[start:end], nil }
+	} else {
+		if ct, err = decodeContinue(pattern, opts.Continue); err != nil {
+			return result, err
+		}
+		if total, keysAt, err = openSnapshot(ct); err != nil {
+			return result, err
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = int64(total)
+	}
+
+	// Fetch a batch at a time until the page is full, so a selective filter
+	// does not cost a round trip per object.
+	pos := 0
+	for pos < total && int64(len(result.Items)) < limit {
+		batch := int(limit) - len(result.Items)
+		if batch < mgetBatch && filter != nil {
+			batch = mgetBatch
+		}
+		end := pos + batch
+		if end > total {
+			end = total
+		}
+
+		batchKeys, err := keysAt(pos, end)
+		if err != nil {
+			return result, err
+		}
+		objects, objectKeys, err := mgetObjects[T](batchKeys)
+		if err != nil {
+			return result, err
+		}
+		next := end
+		for i, obj := range objects {
+			if filter != nil && !filter(obj) {
+				continue
+			}
+			result.Items = append(result.Items, obj)
+			if int64(len(result.Items)) == limit {
+				// Resume right after this object, not after the batch
+				next = pos + sort.SearchStrings(batchKeys, objectKeys[i]) + 1
+				break
+			}
+		}
+		pos = next
+	}
+
+	if pos >= total {
+		if ct.Snapshot != "" {
+			own_redis.RedisClient.Del(own_redis.Ctx, snapshotPrefix+ct.Snapshot)
+		}
+		return result, nil
+	}
+
+	if ct.Snapshot == "" {
+		if ct.Snapshot, err = saveSnapshot(keys[pos:]); err != nil {
+			return result, err
+		}
+		ct.Pattern = pattern
+	} else {
+		ct.Offset += int64(pos)
+	}
+	result.Continue = encodeContinue(ct)
+	if filter == nil {
+		remaining := int64(total - pos)
+		result.RemainingItemCount = &remaining
+	}
+	return result, nil
+}
+
+// listAll returns every object under pattern.
+func listAll[T any](pattern string) ([]T, error) {
+	result, err := listPage[T](pattern, ListOptions{}, nil)
+	return result.Items, err
+}
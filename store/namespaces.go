@@ -0,0 +1,24 @@
+package store
+
+import (
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+func SaveNamespace(ns models.Namespace) (models.Namespace, error) {
+	ns.Metadata.Namespace = ""
+	return saveObject("namespaces:"+ns.Metadata.Name, ns)
+}
+
+func GetNamespace(name string) (models.Namespace, bool, error) {
+	var ns models.Namespace
+	found, err := getObject("namespaces:"+name, &ns)
+	return ns, found, err
+}
+
+func ListNamespacesPage(opts ListOptions, filter func(models.Namespace) bool) (ListResult[models.Namespace], error) {
+	return listPage("namespaces:*", opts, filter)
+}
+
+func DeleteNamespace(name string) (bool, error) {
+	return deleteObject("namespaces:" + name)
+}
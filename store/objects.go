@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	own_redis "github.com/selimhanmrl/Own-Kubernetes/redis"
+)
+
+// ErrConflict is returned by writes of an object whose resourceVersion is no
+// longer the stored one.
+var ErrConflict = errors.New("the object has been modified; please apply your changes to the latest version and try again")
+
+// Ping checks that Redis answers within the context's deadline.
+func Ping(ctx context.Context) error {
+	if own_redis.RedisClient == nil {
+		return fmt.Errorf("RedisClient is not initialized")
+	}
+	return own_redis.RedisClient.Ping(ctx).Err()
+}
+
+// saveObject stores any API object as JSON under key and returns it as
+// stored, with its new resource version.
+func saveObject[T any](key string, obj T) (T, error) {
+	return saveObjectTTL(key, obj, 0)
+}
+
+// saveObjectTTL stores obj like saveObject, letting Redis drop it after ttl.
+// A zero ttl keeps it forever.
+func saveObjectTTL[T any](key string, obj T, ttl time.Duration) (T, error) {
+	var saved T
+	if own_redis.RedisClient == nil {
+		return saved, fmt.Errorf("RedisClient is not initialized")
+	}
+	value, err := json.Marshal(obj)
+	if err != nil {
+		return saved, fmt.Errorf("failed to marshal object: %v", err)
+	}
+	stamped, err := setObject(key, value, ttl)
+	if err != nil {
+		return saved, fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	if err := json.Unmarshal(stamped, &saved); err != nil {
+		return saved, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+	return saved, nil
+}
+
+// setObject stamps the JSON object in value with a new resource version,
+// stores it under key and returns what it stored. If value carries a
+// resourceVersion, it is only stored while that is still the version under
+// key: the key is watched from the comparison until the write, so a
+// concurrent writer makes it fail with ErrConflict instead of being
+// overwritten.
+func setObject(key string, value []byte, ttl time.Duration) ([]byte, error) {
+	sent := objectResourceVersion(value)
+	if sent == "" {
+		stamped, err := stampResourceVersion(value)
+		if err != nil {
+			return nil, err
+		}
+		return stamped, own_redis.RedisClient.Set(own_redis.Ctx, key, stamped, ttl).Err()
+	}
+
+	var stamped []byte
+	err := own_redis.RedisClient.Watch(own_redis.Ctx, func(tx *redis.Tx) error {
+		stored, err := tx.Get(own_redis.Ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil {
+			if current := objectResourceVersion(stored); current != "" && current != sent {
+				return ErrConflict
+			}
+		}
+		if stamped, err = stampResourceVersion(value); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(own_redis.Ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(own_redis.Ctx, key, stamped, ttl)
+			return nil
+		})
+		return err
+	}, key)
+	if err == redis.TxFailedErr {
+		return nil, ErrConflict
+	}
+	return stamped, err
+}
+
+// objectResourceVersion reads metadata.resourceVersion from a JSON object.
+func objectResourceVersion(value []byte) string {
+	var object struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	json.Unmarshal(value, &object)
+	return object.Metadata.ResourceVersion
+}
+
+// getObject loads the JSON object under key into out.
+func getObject(key string, out interface{}) (bool, error) {
+	if own_redis.RedisClient == nil {
+		return false, fmt.Errorf("RedisClient is not initialized")
+	}
+	value, err := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
+	if err == redis.Nil {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get %s: %v", key, err)
+	}
+	if err := json.Unmarshal([]byte(value), out); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+	return true, nil
+}
+
+// listObjects loads every object whose key matches pattern.
+func listObjects[T any](pattern string) ([]T, error) {
+	return listAll[T](pattern)
+}
+
+func deleteObject(key string) (bool, error) {
+	if own_redis.RedisClient == nil {
+		return false, fmt.Errorf("RedisClient is not initialized")
+	}
+	n, err := own_redis.RedisClient.Del(own_redis.Ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete %s: %v", key, err)
+	}
+	if n > 0 {
+		bumpResourceVersion()
+	}
+	return n > 0, nil
+}
+
+func namespacedPattern(prefix, namespace string) string {
+	if namespace == "" {
+		return prefix + ":*"
+	}
+	return fmt.Sprintf("%s:%s:*", prefix, escapeGlob(namespace))
+}
+
+// globEscaper escapes the characters Redis SCAN MATCH treats as a glob, so
+// a namespace such as "*" lists only itself.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// escapeGlob makes s match only itself in a key pattern.
+func escapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
+
+func defaultNamespace(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
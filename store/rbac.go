@@ -0,0 +1,110 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/selimhanmrl/Own-Kubernetes/models"
+)
+
+// Roles
+
+func SaveRole(role models.Role) (models.Role, error) {
+	role.Metadata.Namespace = defaultNamespace(role.Metadata.Namespace)
+	return saveObject(fmt.Sprintf("roles:%s:%s", role.Metadata.Namespace, role.Metadata.Name), role)
+}
+
+func GetRole(namespace, name string) (models.Role, bool, error) {
+	var role models.Role
+	found, err := getObject(fmt.Sprintf("roles:%s:%s", defaultNamespace(namespace), name), &role)
+	return role, found, err
+}
+
+// ListRoles lists roles in namespace, or in all namespaces when it is empty.
+func ListRoles(namespace string) ([]models.Role, error) {
+	return listObjects[models.Role](namespacedPattern("roles", namespace))
+}
+
+func ListRolesPage(namespace string, opts ListOptions, filter func(models.Role) bool) (ListResult[models.Role], error) {
+	return listPage(namespacedPattern("roles", namespace), opts, filter)
+}
+
+func DeleteRole(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("roles:%s:%s", defaultNamespace(namespace), name))
+}
+
+// ClusterRoles
+
+func SaveClusterRole(role models.ClusterRole) (models.ClusterRole, error) {
+	role.Metadata.Namespace = ""
+	return saveObject("clusterroles:"+role.Metadata.Name, role)
+}
+
+func GetClusterRole(name string) (models.ClusterRole, bool, error) {
+	var role models.ClusterRole
+	found, err := getObject("clusterroles:"+name, &role)
+	return role, found, err
+}
+
+func ListClusterRoles() ([]models.ClusterRole, error) {
+	return listObjects[models.ClusterRole]("clusterroles:*")
+}
+
+func ListClusterRolesPage(opts ListOptions, filter func(models.ClusterRole) bool) (ListResult[models.ClusterRole], error) {
+	return listPage("clusterroles:*", opts, filter)
+}
+
+func DeleteClusterRole(name string) (bool, error) {
+	return deleteObject("clusterroles:" + name)
+}
+
+// RoleBindings
+
+func SaveRoleBinding(binding models.RoleBinding) (models.RoleBinding, error) {
+	binding.Metadata.Namespace = defaultNamespace(binding.Metadata.Namespace)
+	return saveObject(fmt.Sprintf("rolebindings:%s:%s", binding.Metadata.Namespace, binding.Metadata.Name), binding)
+}
+
+func GetRoleBinding(namespace, name string) (models.RoleBinding, bool, error) {
+	var binding models.RoleBinding
+	found, err := getObject(fmt.Sprintf("rolebindings:%s:%s", defaultNamespace(namespace), name), &binding)
+	return binding, found, err
+}
+
+// ListRoleBindings lists bindings in namespace, or in all namespaces when it
+// is empty.
+func ListRoleBindings(namespace string) ([]models.RoleBinding, error) {
+	return listObjects[models.RoleBinding](namespacedPattern("rolebindings", namespace))
+}
+
+func ListRoleBindingsPage(namespace string, opts ListOptions, filter func(models.RoleBinding) bool) (ListResult[models.RoleBinding], error) {
+	return listPage(namespacedPattern("rolebindings", namespace), opts, filter)
+}
+
+func DeleteRoleBinding(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("rolebindings:%s:%s", defaultNamespace(namespace), name))
+}
+
+// ClusterRoleBindings
+
+func SaveClusterRoleBinding(binding models.ClusterRoleBinding) (models.ClusterRoleBinding, error) {
+	binding.Metadata.Namespace = ""
+	return saveObject("clusterrolebindings:"+binding.Metadata.Name, binding)
+}
+
+func GetClusterRoleBinding(name string) (models.ClusterRoleBinding, bool, error) {
+	var binding models.ClusterRoleBinding
+	found, err := getObject("clusterrolebindings:"+name, &binding)
+	return binding, found, err
+}
+
+func ListClusterRoleBindings() ([]models.ClusterRoleBinding, error) {
+	return listObjects[models.ClusterRoleBinding]("clusterrolebindings:*")
+}
+
+func ListClusterRoleBindingsPage(opts ListOptions, filter func(models.ClusterRoleBinding) bool) (ListResult[models.ClusterRoleBinding], error) {
+	return listPage("clusterrolebindings:*", opts, filter)
+}
+
+func DeleteClusterRoleBinding(name string) (bool, error) {
+	return deleteObject("clusterrolebindings:" + name)
+}
@@ -3,7 +3,6 @@ package store
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"sync"
 
 	"github.com/go-redis/redis"
@@ -22,8 +21,22 @@ var (
 		endRange:   105,
 		usedIPs:    make(map[string]bool),
 	}
+	nodePortMin, nodePortMax = 30000, 32767
 )
 
+// SetNodeIPRange sets the addresses given to nodes that register without
+// one: prefix followed by first through last, e.g. "192.168.1." 100-105.
+func SetNodeIPRange(prefix string, first, last int) {
+	ipPool.mu.Lock()
+	defer ipPool.mu.Unlock()
+	ipPool.baseIP, ipPool.startRange, ipPool.endRange = prefix, first, last
+}
+
+// SetNodePortRange sets the inclusive range NodePorts are assigned from.
+func SetNodePortRange(min, max int) {
+	nodePortMin, nodePortMax = min, max
+}
+
 type IPPool struct {
 	baseIP     string
 	startRange int
@@ -46,9 +59,11 @@ func (p *IPPool) AssignIP(nodeName string) (string, error) {
 	return "", fmt.Errorf("no available IPs in pool")
 }
 
-func SavePod(pod models.Pod) error {
+// SavePod stores a pod and returns it as stored, with its new resource
+// version.
+func SavePod(pod models.Pod) (models.Pod, error) {
 	if own_redis.RedisClient == nil {
-		return fmt.Errorf("RedisClient is not initialized")
+		return pod, fmt.Errorf("RedisClient is not initialized")
 	}
 
 	if pod.Metadata.Namespace == "" {
@@ -62,47 +77,25 @@ func SavePod(pod models.Pod) error {
 
 	value, err := json.Marshal(pod)
 	if err != nil {
-		return fmt.Errorf("failed to marshal pod: %v", err)
+		return pod, fmt.Errorf("failed to marshal pod: %v", err)
 	}
-
-	err = own_redis.RedisClient.Set(own_redis.Ctx, key, value, 0).Err()
+	stamped, err := setObject(key, value, 0)
 	if err != nil {
-		return fmt.Errorf("failed to save pod: %v", err)
+		return pod, fmt.Errorf("failed to save pod: %w", err)
+	}
+	var saved models.Pod
+	if err := json.Unmarshal(stamped, &saved); err != nil {
+		return pod, fmt.Errorf("failed to unmarshal pod: %v", err)
 	}
 
 	fmt.Printf("✅ Pod '%s' saved to Redis in namespace '%s'\n",
 		pod.Metadata.Name, pod.Metadata.Namespace)
-	return nil
-}
-
-func SaveReplicaSet(rs models.ReplicaSet) error {
-	if own_redis.RedisClient == nil { // Use RedisClient from the redis package
-		log.Fatalf("❌ RedisClient is not initialized")
-	}
-	if rs.Metadata.Namespace == "" {
-		rs.Metadata.Namespace = "default" // Default to 'default' namespace
-	}
-
-	key := fmt.Sprintf("replicaset:%s:%s", rs.Metadata.Namespace, rs.Metadata.Name)
-
-	// Convert ReplicaSet to JSON
-	data, err := json.Marshal(rs)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ReplicaSet: %v", err)
-	}
-
-	// Save to Redis
-	err = own_redis.RedisClient.Set(own_redis.Ctx, key, data, 0).Err()
-	if err != nil {
-		return fmt.Errorf("failed to save ReplicaSet to Redis: %v", err)
-	}
-
-	fmt.Printf("✅ ReplicaSet '%s' saved to Redis in namespace '%s'\n",
-		rs.Metadata.Name, rs.Metadata.Namespace)
-	return nil
+	return saved, nil
 }
 
-func SaveService(service models.Service) error {
+// SaveService stores a service, assigning node ports it lacks, and returns
+// it as stored.
+func SaveService(service models.Service) (models.Service, error) {
 	if service.Spec.Type == "NodePort" {
 		// Auto-assign NodePort if not specified
 		for i := range service.Spec.Ports {
@@ -113,7 +106,7 @@ func SaveService(service models.Service) error {
 	}
 
 	if own_redis.RedisClient == nil { // Use RedisClient from the redis package
-		return fmt.Errorf("❌ RedisClient is not initialized")
+		return service, fmt.Errorf("❌ RedisClient is not initialized")
 	}
 
 	if service.Metadata.Namespace == "" {
@@ -123,25 +116,31 @@ func SaveService(service models.Service) error {
 	key := fmt.Sprintf("services:%s:%s", service.Metadata.Namespace, service.Metadata.Name) // Include namespace in the key
 	value, err := json.Marshal(service)
 	if err != nil {
-		return fmt.Errorf("failed to marshal service: %v", err)
+		return service, fmt.Errorf("failed to marshal service: %v", err)
 	}
-
-	err = own_redis.RedisClient.Set(own_redis.Ctx, key, value, 0).Err()
+	stamped, err := setObject(key, value, 0)
 	if err != nil {
-		return fmt.Errorf("❌ Failed to save service '%s': %v", service.Metadata.Name, err)
+		return service, fmt.Errorf("❌ Failed to save service '%s': %w", service.Metadata.Name, err)
+	}
+	var saved models.Service
+	if err := json.Unmarshal(stamped, &saved); err != nil {
+		return service, fmt.Errorf("failed to unmarshal service: %v", err)
 	}
 
 	fmt.Printf("✅ Service '%s' saved to Redis in namespace '%s'.\n", service.Metadata.Name, service.Metadata.Namespace)
-	return nil
+	return saved, nil
 }
 
-func GetPod(name string) (models.Pod, bool) {
+func GetPod(namespace, name string) (models.Pod, bool) {
 	if name == "" {
 		return models.Pod{}, false
 	}
+	if namespace == "" {
+		namespace = "default"
+	}
 
 	// Use consistent key format
-	key := fmt.Sprintf("pods:default:%s", name)
+	key := fmt.Sprintf("pods:%s:%s", namespace, name)
 	fmt.Printf("🔍 Looking up pod with key: %s\n", key)
 
 	value, err := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
@@ -164,20 +163,11 @@ func GetPod(name string) (models.Pod, bool) {
 }
 
 func ListAllPods() []models.Pod {
-	pattern := "pods:*" // Match all pods across all namespaces
-	keys, err := own_redis.RedisClient.Keys(own_redis.Ctx, pattern).Result()
+	pods, err := listAll[models.Pod]("pods:*") // Match all pods across all namespaces
 	if err != nil {
 		fmt.Printf("❌ Failed to list pods: %v\n", err)
 		return nil
 	}
-
-	var pods []models.Pod
-	for _, key := range keys {
-		value, _ := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
-		var pod models.Pod
-		json.Unmarshal([]byte(value), &pod)
-		pods = append(pods, pod)
-	}
 	return pods
 }
 
@@ -187,42 +177,32 @@ func ListPods(namespace string) []models.Pod {
 	}
 
 	// Use consistent key pattern
-	pattern := fmt.Sprintf("pods:%s:*", namespace)
+	pattern := namespacedPattern("pods", namespace)
 	fmt.Printf("🔍 Listing pods with pattern: %s\n", pattern)
 
-	keys, err := own_redis.RedisClient.Keys(own_redis.Ctx, pattern).Result()
+	pods, err := listAll[models.Pod](pattern)
 	if err != nil {
 		fmt.Printf("❌ Failed to list pods: %v\n", err)
 		return nil
 	}
 
-	var pods []models.Pod
-	for _, key := range keys {
-		value, err := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
-		if err != nil {
-			fmt.Printf("❌ Error getting pod for key '%s': %v\n", key, err)
-			continue
-		}
-
-		var pod models.Pod
-		if err := json.Unmarshal([]byte(value), &pod); err != nil {
-			fmt.Printf("❌ Error unmarshaling pod for key '%s': %v\n", key, err)
-			continue
-		}
-		pods = append(pods, pod)
-	}
-
 	fmt.Printf("✅ Found %d pods in namespace '%s'\n", len(pods), namespace)
 	return pods
 }
 
+// ListPodsPage returns a page of the pods in namespace, or in all namespaces
+// when it is empty, that pass filter.
+func ListPodsPage(namespace string, opts ListOptions, filter func(models.Pod) bool) (ListResult[models.Pod], error) {
+	return listPage(namespacedPattern("pods", namespace), opts, filter)
+}
+
 func DeletePod(namespace, name string) error {
 	if namespace == "" {
 		namespace = "default"
 	}
 
 	// Get pod before deleting to get container info
-	pod, found := GetPod(name)
+	pod, found := GetPod(namespace, name)
 	if !found {
 		return fmt.Errorf("pod '%s' not found in namespace '%s'", name, namespace)
 	}
@@ -253,21 +233,24 @@ func DeletePod(namespace, name string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete pod: %v", err)
 	}
+	bumpResourceVersion()
 
 	fmt.Printf("✅ Pod '%s' deleted from store\n", name)
 	return nil
 }
 
-func SaveNode(node models.Node) error {
+// SaveNode stores a node, assigning it an IP if it has none, and returns it
+// as stored.
+func SaveNode(node models.Node) (models.Node, error) {
 	if own_redis.RedisClient == nil {
-		return fmt.Errorf("RedisClient is not initialized")
+		return node, fmt.Errorf("RedisClient is not initialized")
 	}
 
 	// Assign IP from pool if not set
 	if node.IP == "" {
 		ip, err := ipPool.AssignIP(node.Name)
 		if err != nil {
-			return fmt.Errorf("failed to assign IP to node: %v", err)
+			return node, fmt.Errorf("failed to assign IP to node: %v", err)
 		}
 		node.IP = ip
 	}
@@ -275,16 +258,17 @@ func SaveNode(node models.Node) error {
 	key := fmt.Sprintf("nodes:%s", node.Name)
 	value, err := json.Marshal(node)
 	if err != nil {
-		return fmt.Errorf("failed to marshal node: %v", err)
+		return node, fmt.Errorf("failed to marshal node: %v", err)
 	}
 
 	err = own_redis.RedisClient.Set(own_redis.Ctx, key, value, 0).Err()
 	if err != nil {
-		return fmt.Errorf("failed to save node: %v", err)
+		return node, fmt.Errorf("failed to save node: %v", err)
 	}
+	bumpResourceVersion()
 
 	fmt.Printf("✅ Node '%s' registered with IP %s\n", node.Name, node.IP)
-	return nil
+	return node, nil
 }
 
 func UpdateNodeStatus(nodeName string, status models.NodeStatus) error {
@@ -304,42 +288,24 @@ func UpdateNodeStatus(nodeName string, status models.NodeStatus) error {
 	}
 
 	node.Status = status
-	return SaveNode(node)
+	_, err = SaveNode(node)
+	return err
 }
 
 func ListNodes() []models.Node {
-	if own_redis.RedisClient == nil {
-		fmt.Printf("❌ RedisClient is not initialized\n")
-		return nil
-	}
-
-	pattern := "nodes:*"
-	keys, err := own_redis.RedisClient.Keys(own_redis.Ctx, pattern).Result()
+	nodes, err := listAll[models.Node]("nodes:*")
 	if err != nil {
 		fmt.Printf("❌ Failed to list nodes: %v\n", err)
 		return nil
 	}
-
-	var nodes []models.Node
-	for _, key := range keys {
-		value, err := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
-		if err != nil {
-			fmt.Printf("❌ Failed to get node: %v\n", err)
-			continue
-		}
-
-		var node models.Node
-		if err := json.Unmarshal([]byte(value), &node); err != nil {
-			fmt.Printf("❌ Failed to unmarshal node: %v\n", err)
-			continue
-		}
-
-		nodes = append(nodes, node)
-	}
-
 	return nodes
 }
 
+// ListNodesPage returns a page of the nodes that pass filter.
+func ListNodesPage(opts ListOptions, filter func(models.Node) bool) (ListResult[models.Node], error) {
+	return listPage("nodes:*", opts, filter)
+}
+
 func PublishEvent(eventType, podName string) {
 	channel := "pods:events"
 	message := fmt.Sprintf("%s:%s", eventType, podName)
@@ -359,9 +325,7 @@ func WatchPods() {
 }
 
 func generateNodePort() int {
-	// NodePort range is typically 30000-32767
-	min := 30000
-	max := 32767
+	min, max := nodePortMin, nodePortMax
 
 	// Get existing services to check used ports
 	services := ListServices("")
@@ -390,33 +354,22 @@ func ListServices(namespace string) []models.Service {
 		namespace = "default"
 	}
 
-	pattern := fmt.Sprintf("services:%s:*", namespace)
-	keys, err := own_redis.RedisClient.Keys(own_redis.Ctx, pattern).Result()
+	services, err := listAll[models.Service](namespacedPattern("services", namespace))
 	if err != nil {
 		fmt.Printf("❌ Failed to list services: %v\n", err)
 		return nil
 	}
 
-	var services []models.Service
-	for _, key := range keys {
-		value, err := own_redis.RedisClient.Get(own_redis.Ctx, key).Result()
-		if err != nil {
-			fmt.Printf("❌ Error getting service for key '%s': %v\n", key, err)
-			continue
-		}
-
-		var service models.Service
-		if err := json.Unmarshal([]byte(value), &service); err != nil {
-			fmt.Printf("❌ Error unmarshaling service for key '%s': %v\n", key, err)
-			continue
-		}
-		services = append(services, service)
-	}
-
 	fmt.Printf("✅ Found %d services in namespace '%s'\n", len(services), namespace)
 	return services
 }
 
+// ListServicesPage returns a page of the services in namespace, or in all
+// namespaces when it is empty, that pass filter.
+func ListServicesPage(namespace string, opts ListOptions, filter func(models.Service) bool) (ListResult[models.Service], error) {
+	return listPage(namespacedPattern("services", namespace), opts, filter)
+}
+
 func findServicesForPod(pod *models.Pod) []models.Service {
 	if pod.Metadata.Labels == nil {
 		return nil
@@ -461,3 +414,34 @@ func GetNodeIP(nodeName string) (string, error) {
 
 	return node.IP, nil
 }
+
+func GetService(namespace, name string) (models.Service, bool, error) {
+	var service models.Service
+	found, err := getObject(fmt.Sprintf("services:%s:%s", defaultNamespace(namespace), name), &service)
+	return service, found, err
+}
+
+func GetNode(name string) (models.Node, bool, error) {
+	var node models.Node
+	found, err := getObject("nodes:"+name, &node)
+	return node, found, err
+}
+
+func DeleteService(namespace, name string) (bool, error) {
+	return deleteObject(fmt.Sprintf("services:%s:%s", defaultNamespace(namespace), name))
+}
+
+// DeleteNode removes a node and returns its address to the IP pool.
+func DeleteNode(name string) (bool, error) {
+	node, found, err := GetNode(name)
+	if err != nil || !found {
+		return false, err
+	}
+	deleted, err := deleteObject("nodes:" + name)
+	if deleted {
+		ipPool.mu.Lock()
+		delete(ipPool.usedIPs, node.IP)
+		ipPool.mu.Unlock()
+	}
+	return deleted, err
+}
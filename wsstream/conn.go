@@ -0,0 +1,115 @@
+// Package wsstream carries several byte streams over one WebSocket, as
+// exec and port forwarding need. Every binary message starts with the
+// number of the channel it belongs to, followed by the data, in the manner
+// of the Kubernetes channel.k8s.io protocols.
+package wsstream
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn is a WebSocket split into channels. Writes may come from several
+// goroutines; reads from one.
+type Conn struct {
+	ws      *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// Upgrade turns a request into a Conn speaking protocol, which the client
+// must offer. On failure the request has been answered.
+func Upgrade(w http.ResponseWriter, r *http.Request, protocol string) (*Conn, error) {
+	offered := false
+	for _, p := range websocket.Subprotocols(r) {
+		offered = offered || p == protocol
+	}
+	if !offered {
+		http.Error(w, fmt.Sprintf("the %s WebSocket subprotocol is required", protocol), http.StatusBadRequest)
+		return nil, fmt.Errorf("client did not offer the %s subprotocol", protocol)
+	}
+	upgrader := websocket.Upgrader{Subprotocols: []string{protocol}}
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{ws: ws}, nil
+}
+
+// Dial opens a Conn speaking protocol to a ws:// or wss:// URL. When the
+// server refuses the upgrade its response is returned with the error, its
+// body still unread.
+func Dial(ctx context.Context, url string, tlsConfig *tls.Config, header http.Header, protocol string) (*Conn, *http.Response, error) {
+	dialer := websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+		Subprotocols:    []string{protocol},
+	}
+	ws, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, resp, err
+	}
+	if ws.Subprotocol() != protocol {
+		ws.Close()
+		return nil, nil, fmt.Errorf("the server does not speak %s", protocol)
+	}
+	return &Conn{ws: ws}, resp, nil
+}
+
+// Write sends data on a channel as one message.
+func (c *Conn) Write(channel byte, data []byte) error {
+	message := make([]byte, 1+len(data))
+	message[0] = channel
+	copy(message[1:], data)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.ws.WriteMessage(websocket.BinaryMessage, message)
+}
+
+// Read returns the next message and its channel. It returns io.EOF when
+// the other side closed the connection normally.
+func (c *Conn) Read() (byte, []byte, error) {
+	for {
+		_, message, err := c.ws.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return 0, nil, io.EOF
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(message) > 0 {
+			return message[0], message[1:], nil
+		}
+	}
+}
+
+// Writer returns a writer that sends each write on channel.
+func (c *Conn) Writer(channel byte) io.Writer {
+	return channelWriter{conn: c, channel: channel}
+}
+
+type channelWriter struct {
+	conn    *Conn
+	channel byte
+}
+
+func (w channelWriter) Write(p []byte) (int, error) {
+	if err := w.conn.Write(w.channel, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close ends the connection with a normal closure.
+func (c *Conn) Close() error {
+	c.writeMu.Lock()
+	c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.writeMu.Unlock()
+	return c.ws.Close()
+}
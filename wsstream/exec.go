@@ -0,0 +1,206 @@
+package wsstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ExecProtocol is the subprotocol of exec sessions.
+const ExecProtocol = "v1.exec.mykube.io"
+
+// The channels of an exec session. The client writes stdin, resize and
+// close; the server writes stdout, stderr and, last, the session's status
+// on the error channel.
+const (
+	StdinChannel  byte = 0
+	StdoutChannel byte = 1
+	StderrChannel byte = 2
+	ErrorChannel  byte = 3
+	ResizeChannel byte = 4
+	// CloseChannel carries the number of a channel its writer is done
+	// with, such as stdin at end of input.
+	CloseChannel byte = 255
+)
+
+// TerminalSize is sent on the resize channel when the client's terminal
+// changes size.
+type TerminalSize struct {
+	Width  uint16 `json:"Width"`
+	Height uint16 `json:"Height"`
+}
+
+// Status ends a session on the error channel.
+type Status struct {
+	Status   string `json:"status"` // Success or Failure
+	Message  string `json:"message,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+}
+
+// ExitError is returned by StreamExec when the command exits non-zero.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command terminated with exit code %d", e.Code)
+}
+
+// ExecStreams are the standard streams of a command run over a session.
+// Stdin and Resize are nil when the session has no stdin or terminal.
+type ExecStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan TerminalSize
+}
+
+// ServeExec is the server side of an exec session: it passes the client's
+// input and terminal sizes to run, sends the output back and reports how
+// run ended. ctx passed to run is cancelled when the client goes away.
+// Errors with an ExitCode method, such as *exec.ExitError, are reported
+// as exit codes.
+func ServeExec(ctx context.Context, conn *Conn, stdin, tty bool, run func(context.Context, ExecStreams) error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	streams := ExecStreams{
+		Stdout: conn.Writer(StdoutChannel),
+		Stderr: conn.Writer(StderrChannel),
+	}
+	var stdinReader *io.PipeReader
+	var stdinWriter *io.PipeWriter
+	if stdin {
+		stdinReader, stdinWriter = io.Pipe()
+		streams.Stdin = stdinReader
+	}
+	var resize chan TerminalSize
+	if tty {
+		resize = make(chan TerminalSize, 1)
+		streams.Resize = resize
+	}
+
+	go func() {
+		defer cancel()
+		for {
+			channel, data, err := conn.Read()
+			if err != nil {
+				if stdinWriter != nil {
+					stdinWriter.CloseWithError(io.ErrUnexpectedEOF)
+				}
+				return
+			}
+			switch {
+			case channel == StdinChannel && stdinWriter != nil:
+				if _, err := stdinWriter.Write(data); err != nil {
+					// The command stopped reading; drop the rest
+					stdinWriter = nil
+				}
+			case channel == CloseChannel && len(data) == 1 && data[0] == StdinChannel && stdinWriter != nil:
+				stdinWriter.Close()
+				stdinWriter = nil
+			case channel == ResizeChannel && resize != nil:
+				var size TerminalSize
+				if json.Unmarshal(data, &size) == nil {
+					// Only the latest size matters
+					select {
+					case <-resize:
+					default:
+					}
+					resize <- size
+				}
+			}
+		}
+	}()
+
+	err := run(ctx, streams)
+	if stdinReader != nil {
+		// Unblock input the command never read
+		stdinReader.Close()
+	}
+	status := Status{Status: "Success"}
+	if err != nil {
+		status = Status{Status: "Failure", Message: err.Error()}
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
+			status.ExitCode = exitErr.ExitCode()
+			status.Message = fmt.Sprintf("command terminated with exit code %d", status.ExitCode)
+		}
+	}
+	data, _ := json.Marshal(status)
+	conn.Write(ErrorChannel, data)
+	conn.Close()
+}
+
+// StreamExec is the client side of an exec session. It sends stdin and
+// terminal sizes from streams, writes the output to them and returns when
+// the command ends: nil if it succeeded, an *ExitError if it exited
+// non-zero.
+func StreamExec(conn *Conn, streams ExecStreams) error {
+	defer conn.Close()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+	if streams.Stdin != nil {
+		// Not waited for: reading a terminal blocks until the next key.
+		// Input ends on errors too, so the command is not left waiting.
+		go func() {
+			io.Copy(conn.Writer(StdinChannel), streams.Stdin)
+			conn.Write(CloseChannel, []byte{StdinChannel})
+		}()
+	}
+	if streams.Resize != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case size, ok := <-streams.Resize:
+					if !ok {
+						return
+					}
+					data, _ := json.Marshal(size)
+					conn.Write(ResizeChannel, data)
+				}
+			}
+		}()
+	}
+
+	for {
+		channel, data, err := conn.Read()
+		if err != nil {
+			return fmt.Errorf("the connection ended before the command: %v", err)
+		}
+		switch channel {
+		case StdoutChannel:
+			if streams.Stdout != nil {
+				streams.Stdout.Write(data)
+			}
+		case StderrChannel:
+			if streams.Stderr != nil {
+				streams.Stderr.Write(data)
+			}
+		case ErrorChannel:
+			var status Status
+			if err := json.Unmarshal(data, &status); err != nil {
+				return fmt.Errorf("invalid status: %v", err)
+			}
+			switch {
+			case status.Status == "Success":
+				return nil
+			case status.ExitCode != 0:
+				return &ExitError{Code: status.ExitCode}
+			}
+			return errors.New(status.Message)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package wsstream
+
+import (
+	"io"
+	"net"
+)
+
+// PortForwardProtocol is the subprotocol of port forwarding sessions. Each
+// session carries one TCP connection to one port, named by the request.
+const PortForwardProtocol = "v1.portforward.mykube.io"
+
+// DataChannel carries the bytes of a forwarded connection, both ways. Each
+// side sends CloseChannel with DataChannel when its end stops sending.
+const DataChannel byte = 0
+
+// Tunnel relays a TCP connection over a port forwarding session until both
+// ends are done sending, passing on half-closes. It is the same on both
+// sides: the client's stream is the connection it accepted, the server's
+// the one it made to the pod. Both conn and stream are closed on return.
+func Tunnel(conn *Conn, stream net.Conn) error {
+	defer conn.Close()
+	defer stream.Close()
+
+	sent := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(conn.Writer(DataChannel), stream)
+		if err == nil {
+			err = conn.Write(CloseChannel, []byte{DataChannel})
+		}
+		sent <- err
+	}()
+
+	for {
+		channel, data, err := conn.Read()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case channel == DataChannel:
+			if _, err := stream.Write(data); err != nil {
+				return err
+			}
+		case channel == CloseChannel && len(data) == 1 && data[0] == DataChannel:
+			if tcp, ok := stream.(interface{ CloseWrite() error }); ok {
+				tcp.CloseWrite()
+			}
+			// The other end is done; wait for this one
+			return <-sent
+		}
+	}
+}